@@ -0,0 +1,288 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config adalah seluruh pengaturan backend. Nilai diambil dari default,
+// lalu file YAML (CONFIG_FILE), lalu environment variable.
+type Config struct {
+	Database  DatabaseConfig `yaml:"database"`
+	Redis     RedisConfig    `yaml:"redis"`
+	Server    ServerConfig   `yaml:"server"`
+	Session   SessionConfig  `yaml:"session"`
+	Refund    RefundConfig   `yaml:"refund"`
+	Loyalty   LoyaltyConfig  `yaml:"loyalty"`
+	Receipt   ReceiptConfig  `yaml:"receipt"`
+	UploadDir string         `yaml:"upload_dir"`
+}
+
+type DatabaseConfig struct {
+	// Driver: oracle, postgres atau sqlite
+	Driver      string `yaml:"driver"`
+	DSN         string `yaml:"dsn"`
+	Schema      string `yaml:"schema"`
+	AutoMigrate bool   `yaml:"auto_migrate"`
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+}
+
+type ServerConfig struct {
+	Addr        string   `yaml:"addr"`
+	TLSCertFile string   `yaml:"tls_cert_file"`
+	TLSKeyFile  string   `yaml:"tls_key_file"`
+	CORSOrigins []string `yaml:"cors_origins"`
+	// TrustProxy memakai X-Forwarded-For sebagai IP client pada log audit;
+	// aktifkan hanya jika server berada di belakang reverse proxy
+	TrustProxy bool `yaml:"trust_proxy"`
+}
+
+type SessionConfig struct {
+	// Secret untuk menandatangani access token, kosong berarti kunci acak
+	Secret string `yaml:"secret"`
+}
+
+type RefundConfig struct {
+	// Refund di atas nilai ini harus disetujui admin
+	ApprovalThreshold float64 `yaml:"approval_threshold"`
+}
+
+// LoyaltyConfig mengatur poin pelanggan. SpendPerPoint 0 mematikan
+// perolehan poin, PointValue 0 mematikan penukaran poin.
+type LoyaltyConfig struct {
+	// Setiap kelipatan SpendPerPoint rupiah yang dibayar mendapat 1 poin
+	SpendPerPoint float64 `yaml:"spend_per_point"`
+	// Nilai rupiah 1 poin saat ditukar
+	PointValue float64 `yaml:"point_value"`
+}
+
+// ReceiptConfig mengatur kepala, kaki dan lebar struk. TemplateFile
+// (text/template) menggantikan template bawaan jika diisi.
+type ReceiptConfig struct {
+	StoreName    string   `yaml:"store_name"`
+	Address      []string `yaml:"address"`
+	Phone        string   `yaml:"phone"`
+	Footer       []string `yaml:"footer"`
+	Width        int      `yaml:"width"`
+	TemplateFile string   `yaml:"template_file"`
+}
+
+// Default mengembalikan konfigurasi untuk development lokal
+func Default() Config {
+	return Config{
+		Database: DatabaseConfig{
+			Driver:      "oracle",
+			DSN:         "system/123456789@//localhost:1521/orc1",
+			AutoMigrate: true,
+		},
+		Redis: RedisConfig{
+			Addr: "localhost:6379",
+		},
+		Server: ServerConfig{
+			Addr:        ":8080",
+			CORSOrigins: []string{"http://localhost:5173"},
+		},
+		Refund: RefundConfig{
+			ApprovalThreshold: 100000,
+		},
+		Loyalty: LoyaltyConfig{
+			SpendPerPoint: 10000,
+			PointValue:    100,
+		},
+		Receipt: ReceiptConfig{
+			StoreName: "POS",
+			Footer:    []string{"Terima kasih"},
+			Width:     32,
+		},
+		UploadDir: "uploads",
+	}
+}
+
+// Load membaca konfigurasi dari file (jika CONFIG_FILE diset) dan environment
+func Load() (Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return cfg, err
+	}
+
+	// Tabel lama di Oracle berada di schema SYSBACKUP
+	if cfg.Database.Schema == "" && cfg.Database.Driver == "oracle" {
+		cfg.Database.Schema = "SYSBACKUP"
+	}
+
+	return cfg, cfg.Validate()
+}
+
+func (c *Config) applyEnv() error {
+	setString(&c.Database.Driver, "DB_DRIVER")
+	setString(&c.Database.DSN, "DB_DSN")
+	setString(&c.Database.Schema, "DB_SCHEMA")
+	if err := setBool(&c.Database.AutoMigrate, "DB_AUTO_MIGRATE"); err != nil {
+		return err
+	}
+
+	setString(&c.Redis.Addr, "REDIS_ADDR")
+	setString(&c.Redis.Password, "REDIS_PASSWORD")
+	if err := setInt(&c.Redis.DB, "REDIS_DB"); err != nil {
+		return err
+	}
+
+	setString(&c.Server.Addr, "LISTEN_ADDR")
+	setString(&c.Server.TLSCertFile, "TLS_CERT_FILE")
+	setString(&c.Server.TLSKeyFile, "TLS_KEY_FILE")
+	if v, ok := os.LookupEnv("CORS_ALLOWED_ORIGINS"); ok {
+		c.Server.CORSOrigins = splitList(v)
+	}
+	if err := setBool(&c.Server.TrustProxy, "TRUST_PROXY"); err != nil {
+		return err
+	}
+
+	setString(&c.Session.Secret, "SESSION_SECRET")
+	if err := setFloat(&c.Refund.ApprovalThreshold, "REFUND_APPROVAL_THRESHOLD"); err != nil {
+		return err
+	}
+	if err := setFloat(&c.Loyalty.SpendPerPoint, "LOYALTY_SPEND_PER_POINT"); err != nil {
+		return err
+	}
+	if err := setFloat(&c.Loyalty.PointValue, "LOYALTY_POINT_VALUE"); err != nil {
+		return err
+	}
+	setString(&c.Receipt.StoreName, "RECEIPT_STORE_NAME")
+	setString(&c.Receipt.Phone, "RECEIPT_PHONE")
+	setString(&c.Receipt.TemplateFile, "RECEIPT_TEMPLATE_FILE")
+	if err := setInt(&c.Receipt.Width, "RECEIPT_WIDTH"); err != nil {
+		return err
+	}
+	setString(&c.UploadDir, "UPLOAD_DIR")
+	return nil
+}
+
+// Validate memastikan konfigurasi lengkap dan konsisten
+func (c Config) Validate() error {
+	var errs []string
+
+	switch c.Database.Driver {
+	case "oracle", "postgres", "sqlite":
+	default:
+		errs = append(errs, fmt.Sprintf("database.driver must be oracle, postgres or sqlite, got %q", c.Database.Driver))
+	}
+	if c.Database.DSN == "" {
+		errs = append(errs, "database.dsn is required")
+	}
+	if c.Redis.Addr == "" {
+		errs = append(errs, "redis.addr is required")
+	}
+	if c.Redis.DB < 0 {
+		errs = append(errs, "redis.db must not be negative")
+	}
+	if c.Server.Addr == "" {
+		errs = append(errs, "server.addr is required")
+	}
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		errs = append(errs, "server.tls_cert_file and server.tls_key_file must be set together")
+	}
+	if len(c.Server.CORSOrigins) == 0 {
+		errs = append(errs, "server.cors_origins needs at least one origin")
+	}
+	if c.Session.Secret != "" && len(c.Session.Secret) < 32 {
+		errs = append(errs, "session.secret must be at least 32 characters")
+	}
+	if c.Refund.ApprovalThreshold < 0 {
+		errs = append(errs, "refund.approval_threshold must not be negative")
+	}
+	if c.Loyalty.SpendPerPoint < 0 || c.Loyalty.PointValue < 0 {
+		errs = append(errs, "loyalty.spend_per_point and loyalty.point_value must not be negative")
+	}
+	if c.Receipt.Width < 24 || c.Receipt.Width > 64 {
+		errs = append(errs, "receipt.width must be between 24 and 64 characters")
+	}
+	if c.UploadDir == "" {
+		errs = append(errs, "upload_dir is required")
+	}
+
+	if len(errs) > 0 {
+		return errors.New("invalid config: " + strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// TLSEnabled bernilai true jika sertifikat TLS dikonfigurasi
+func (c ServerConfig) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+func setString(dst *string, key string) {
+	if v, ok := os.LookupEnv(key); ok {
+		*dst = v
+	}
+}
+
+func setBool(dst *bool, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*dst = b
+	return nil
+}
+
+func setInt(dst *int, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*dst = n
+	return nil
+}
+
+func setFloat(dst *float64, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*dst = f
+	return nil
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
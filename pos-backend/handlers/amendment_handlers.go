@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"pos-backend/utils"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// AddOrderItem menambah baris ke order yang masih On Progress dan membalas order terbaru
+func AddOrderItem(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.OrderAmendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Item == nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := st.Orders.GetOrder(ctx, req.OrderID)
+	order, err := st.Orders.AddOrderItem(ctx, req.OrderID, *req.Item, utils.SessionFromContext(r.Context()).Username, req.Reason)
+	if err != nil {
+		writeStoreError(w, err, "Failed to add order item")
+		return
+	}
+	auditChange(r, "order", order.ID, before, order)
+	writeAmendedOrder(ctx, st, rdb, w, order)
+}
+
+// UpdateOrderItem mengubah jumlah satu baris order; alasan wajib diisi
+func UpdateOrderItem(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.OrderAmendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	order, err := st.Orders.SetOrderItemQuantity(ctx, req.DetailID, req.Quantity, utils.SessionFromContext(r.Context()).Username, req.Reason)
+	if err != nil {
+		writeStoreError(w, err, "Failed to update order item")
+		return
+	}
+	auditAmendment(ctx, st, r, order)
+	writeAmendedOrder(ctx, st, rdb, w, order)
+}
+
+// VoidOrderItem menghapus satu baris order dan mengembalikan stoknya; alasan wajib diisi
+func VoidOrderItem(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.OrderAmendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	order, err := st.Orders.VoidOrderItem(ctx, req.DetailID, utils.SessionFromContext(r.Context()).Username, req.Reason)
+	if err != nil {
+		writeStoreError(w, err, "Failed to void order item")
+		return
+	}
+	auditAmendment(ctx, st, r, order)
+	writeAmendedOrder(ctx, st, rdb, w, order)
+}
+
+// GetOrderAmendments returns every line change of an order, oldest first
+func GetOrderAmendments(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	id, ok := orderIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	amendments, err := st.Orders.OrderAmendments(ctx, id)
+	if err != nil {
+		writeStoreError(w, err, "Failed to retrieve order changes")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(amendments)
+}
+
+// auditAmendment mencatat order terbaru di log audit dengan riwayat
+// perubahan terakhir (jumlah dan total lama) sebagai nilai sebelumnya
+func auditAmendment(ctx context.Context, st *store.Store, r *http.Request, order *models.Order) {
+	var before *models.OrderAmendment
+	if amendments, err := st.Orders.OrderAmendments(ctx, order.ID); err == nil && len(amendments) > 0 {
+		before = &amendments[len(amendments)-1]
+	}
+	auditChange(r, "order", order.ID, before, order)
+}
+
+// writeAmendedOrder mengosongkan cache produk karena stok berubah,
+// memberi tahu dapur dan membalas order terbaru
+func writeAmendedOrder(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, order *models.Order) {
+	invalidateProducts(ctx, rdb)
+	publishOrderEvent(ctx, st, rdb, models.OrderEventUpdated, order.ID, nil)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
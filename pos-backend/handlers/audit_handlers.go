@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"pos-backend/utils"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// AuditRecorder menulis satu baris AUDIT_LOG untuk setiap request yang
+// dilewatkan utils.Audit. Gagal menulis audit hanya dicatat ke log karena
+// response sudah terkirim.
+func AuditRecorder(st *store.Store, trustProxy bool) func(r *http.Request, status int, rec *utils.AuditRecord) {
+	return func(r *http.Request, status int, rec *utils.AuditRecord) {
+		entry := &models.AuditEntry{
+			Action:   utils.AuditAction(r.URL.Path),
+			Method:   r.Method,
+			Path:     r.URL.Path,
+			Entity:   rec.Entity,
+			EntityID: rec.EntityID,
+			Before:   auditJSON(rec.Before),
+			After:    auditJSON(rec.After),
+			Status:   status,
+			IP:       utils.ClientIP(r, trustProxy),
+		}
+		if session := utils.SessionFromContext(r.Context()); session != nil {
+			entry.Actor, entry.Role = session.Username, session.Role
+		}
+
+		// Context request bisa sudah dibatalkan saat client menutup koneksi
+		if err := st.Audit.RecordAudit(context.WithoutCancel(r.Context()), entry); err != nil {
+			log.Printf("Failed to record audit for %s %s: %v", r.Method, r.URL.Path, err)
+		}
+	}
+}
+
+// auditChange mencatat entitas beserta nilai sebelum dan sesudah perubahan
+// pada record audit request. before atau after boleh nil.
+func auditChange(r *http.Request, entity string, id interface{}, before, after interface{}) {
+	rec := utils.AuditFromContext(r.Context())
+	if rec == nil {
+		return
+	}
+	rec.Entity = entity
+	if id != nil {
+		rec.EntityID = fmt.Sprint(id)
+	}
+	rec.Before, rec.After = before, after
+}
+
+// auditOrder mencatat order sebelum dan sesudah perubahan. Order sesudah
+// dibaca ulang dari store dan kosong jika order sudah dihapus.
+func auditOrder(ctx context.Context, st *store.Store, r *http.Request, id int, before *models.Order) {
+	after, err := st.Orders.GetOrder(ctx, id)
+	if err != nil {
+		after = nil
+	}
+	auditChange(r, "order", id, before, after)
+}
+
+func auditJSON(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" {
+		return nil
+	}
+	return b
+}
+
+// GetAuditLog menampilkan log audit terbaru dengan filter actor, action,
+// entity, entity_id, from, to (YYYY-MM-DD) dan limit
+func GetAuditLog(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := models.AuditFilter{
+		Actor:    query.Get("actor"),
+		Action:   query.Get("action"),
+		Entity:   query.Get("entity"),
+		EntityID: query.Get("entity_id"),
+	}
+	var err error
+	if v := query.Get("from"); v != "" {
+		if filter.From, err = time.ParseInLocation("2006-01-02", v, time.Local); err != nil {
+			http.Error(w, "Invalid from date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("to"); v != "" {
+		if filter.To, err = time.ParseInLocation("2006-01-02", v, time.Local); err != nil {
+			http.Error(w, "Invalid to date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		// to inklusif sampai akhir hari
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+	if v := query.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries, err := st.Audit.ListAudit(ctx, filter)
+	if err != nil {
+		writeStoreError(w, err, "Failed to retrieve audit log")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// VerifyAuditLog memeriksa rantai hash log audit dari awal
+func VerifyAuditLog(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	result, err := st.Audit.VerifyAudit(ctx)
+	if err != nil {
+		writeStoreError(w, err, "Failed to verify audit log")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// GetCategories menampilkan semua kategori sesuai urutan menu
+func GetCategories(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	categories, err := st.Categories.ListCategories(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(categories)
+}
+
+// CreateCategory membuat kategori baru, tanpa sort_order kategori ditaruh di akhir
+func CreateCategory(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var category models.Category
+	if err := json.NewDecoder(r.Body).Decode(&category); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := st.Categories.CreateCategory(ctx, &category); err != nil {
+		writeStoreError(w, err, "Failed to create category")
+		return
+	}
+	auditChange(r, "category", category.ID, nil, category)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(category)
+}
+
+// UpdateCategory mengganti nama dan urutan kategori (/update-category/{id})
+func UpdateCategory(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/update-category/"))
+	if err != nil {
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	var category models.Category
+	if err := json.NewDecoder(r.Body).Decode(&category); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	category.ID = id
+
+	before, _ := st.Categories.GetCategory(ctx, id)
+	if err := st.Categories.UpdateCategory(ctx, &category); err != nil {
+		writeStoreError(w, err, "Failed to update category")
+		return
+	}
+	auditChange(r, "category", id, before, category)
+
+	// Nama dan urutan kategori ikut tersimpan di cache produk
+	invalidateProducts(ctx, rdb)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(category)
+}
+
+// DeleteCategory menghapus kategori (/delete-category/{id}), produknya menjadi tanpa kategori
+func DeleteCategory(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/delete-category/"))
+	if err != nil {
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := st.Categories.GetCategory(ctx, id)
+	if err := st.Categories.DeleteCategory(ctx, id); err != nil {
+		writeStoreError(w, err, "Failed to delete category")
+		return
+	}
+	auditChange(r, "category", id, before, nil)
+
+	invalidateProducts(ctx, rdb)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReorderCategories menyimpan urutan kategori dari body {"ids": [3, 1, 2]}
+func ReorderCategories(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		IDs []int `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.IDs) == 0 {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := st.Categories.ListCategories(ctx)
+	if err := st.Categories.ReorderCategories(ctx, req.IDs); err != nil {
+		writeStoreError(w, err, "Failed to reorder categories")
+		return
+	}
+
+	invalidateProducts(ctx, rdb)
+
+	categories, err := st.Categories.ListCategories(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	auditChange(r, "category", nil, before, categories)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(categories)
+}
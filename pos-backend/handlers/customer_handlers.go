@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"pos-backend/utils"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// GetCustomers mencari pelanggan berdasarkan nama atau nomor telepon (?q=)
+func GetCustomers(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	customers, err := st.Customers.ListCustomers(ctx, r.URL.Query().Get("q"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customers)
+}
+
+// CreateCustomer mendaftarkan pelanggan baru dengan saldo poin 0
+func CreateCustomer(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var customer models.Customer
+	if err := json.NewDecoder(r.Body).Decode(&customer); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := st.Customers.CreateCustomer(ctx, &customer); err != nil {
+		writeStoreError(w, err, "Failed to create customer")
+		return
+	}
+	auditChange(r, "customer", customer.ID, nil, customer)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(customer)
+}
+
+// UpdateCustomer mengganti nama, telepon dan email pelanggan (/update-customer/{id})
+func UpdateCustomer(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/update-customer/"))
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	var customer models.Customer
+	if err := json.NewDecoder(r.Body).Decode(&customer); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	customer.ID = id
+
+	before, _ := st.Customers.GetCustomer(ctx, customer.ID)
+	if err := st.Customers.UpdateCustomer(ctx, &customer); err != nil {
+		writeStoreError(w, err, "Failed to update customer")
+		return
+	}
+	auditChange(r, "customer", customer.ID, before, customer)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customer)
+}
+
+// DeleteCustomer menghapus pelanggan (/delete-customer/{id}), ordernya tetap ada tanpa pelanggan
+func DeleteCustomer(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/delete-customer/"))
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := st.Customers.GetCustomer(ctx, id)
+	if err := st.Customers.DeleteCustomer(ctx, id); err != nil {
+		writeStoreError(w, err, "Failed to delete customer")
+		return
+	}
+	auditChange(r, "customer", id, before, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetCustomerHistory menampilkan order dan riwayat poin pelanggan (?id=)
+func GetCustomerHistory(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	history, err := st.Customers.CustomerHistory(ctx, id)
+	if err != nil {
+		writeStoreError(w, err, "Failed to load customer history")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// AdjustPoints mengoreksi saldo poin pelanggan secara manual
+func AdjustPoints(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var adj models.PointAdjustment
+	if err := json.NewDecoder(r.Body).Decode(&adj); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := st.Customers.GetCustomer(ctx, adj.CustomerID)
+	movement, err := st.Customers.AdjustPoints(ctx, adj, utils.SessionFromContext(r.Context()).Username)
+	if err != nil {
+		writeStoreError(w, err, "Failed to adjust points")
+		return
+	}
+	if after, err := st.Customers.GetCustomer(ctx, adj.CustomerID); err == nil {
+		auditChange(r, "customer", adj.CustomerID, before, after)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movement)
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"pos-backend/store"
+)
+
+// writeStoreError memetakan error dari store ke status HTTP yang sesuai.
+// msg dipakai sebagai awalan pesan untuk error yang tidak dikenal.
+func writeStoreError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, store.ErrInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, store.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, store.ErrApprovalRequired):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, msg+": "+err.Error(), http.StatusInternalServerError)
+	}
+}
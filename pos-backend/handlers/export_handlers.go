@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/xuri/excelize/v2"
+)
+
+// csvFlushRows adalah jumlah baris CSV yang ditampung sebelum dikirim ke client
+const csvFlushRows = 500
+
+// exportWriter menulis baris ekspor satu per satu ke response
+type exportWriter interface {
+	WriteRow(values []interface{}) error
+	// Close menyelesaikan file dan mengirim sisa datanya
+	Close() error
+	// Sent bernilai true jika sebagian data sudah terkirim ke client
+	Sent() bool
+}
+
+// newExportWriter memilih writer sesuai ?format= (csv atau xlsx) lalu menulis header kolom
+func newExportWriter(w http.ResponseWriter, r *http.Request, name string, columns []string) (exportWriter, bool) {
+	var ew exportWriter
+	var err error
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.csv\"", name))
+		ew = &csvExport{w: w, cw: csv.NewWriter(w)}
+	case "xlsx":
+		ew, err = newXLSXExport(w, name)
+		if err != nil {
+			http.Error(w, "Failed to create spreadsheet: "+err.Error(), http.StatusInternalServerError)
+			return nil, false
+		}
+	default:
+		http.Error(w, "Unknown export format: "+format, http.StatusBadRequest)
+		return nil, false
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, c := range columns {
+		header[i] = c
+	}
+	if err := ew.WriteRow(header); err != nil {
+		http.Error(w, "Failed to write export: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return ew, true
+}
+
+// finishExport menutup writer atau melaporkan error. Jika data sudah
+// terlanjur terkirim, error hanya bisa dicatat di log.
+func finishExport(w http.ResponseWriter, ew exportWriter, err error) {
+	if err == nil {
+		err = ew.Close()
+	}
+	if err == nil {
+		return
+	}
+	if ew.Sent() {
+		log.Println("Export aborted:", err)
+		return
+	}
+	w.Header().Del("Content-Disposition")
+	writeStoreError(w, err, "Failed to export")
+}
+
+// csvExport menulis CSV dan mengirimnya bertahap setiap csvFlushRows baris
+type csvExport struct {
+	w    http.ResponseWriter
+	cw   *csv.Writer
+	rows int
+	sent bool
+}
+
+func (e *csvExport) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = exportText(v)
+	}
+	if err := e.cw.Write(record); err != nil {
+		return err
+	}
+	e.rows++
+	if e.rows%csvFlushRows == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+func (e *csvExport) flush() error {
+	e.cw.Flush()
+	e.sent = true
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return e.cw.Error()
+}
+
+func (e *csvExport) Close() error { return e.flush() }
+func (e *csvExport) Sent() bool   { return e.sent }
+
+// xlsxExport memakai StreamWriter excelize yang menyimpan baris ke file
+// sementara sehingga memori tetap kecil untuk data besar
+type xlsxExport struct {
+	w    http.ResponseWriter
+	name string
+	f    *excelize.File
+	sw   *excelize.StreamWriter
+	row  int
+	sent bool
+}
+
+func newXLSXExport(w http.ResponseWriter, name string) (*xlsxExport, error) {
+	f := excelize.NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &xlsxExport{w: w, name: name, f: f, sw: sw}, nil
+}
+
+func (e *xlsxExport) WriteRow(values []interface{}) error {
+	e.row++
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
+	if err != nil {
+		return err
+	}
+	for i, v := range values {
+		if t, ok := v.(time.Time); ok {
+			values[i] = exportText(t)
+		}
+	}
+	return e.sw.SetRow(cell, values)
+}
+
+func (e *xlsxExport) Close() error {
+	defer e.f.Close()
+	if err := e.sw.Flush(); err != nil {
+		return err
+	}
+	e.w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.xlsx\"", e.name))
+	e.sent = true
+	return e.f.Write(e.w)
+}
+
+func (e *xlsxExport) Sent() bool { return e.sent }
+
+// exportText mengubah nilai sel menjadi teks untuk CSV. Teks yang diawali
+// =, +, -, @, tab atau CR diberi awalan ' supaya tidak dijalankan sebagai
+// rumus saat dibuka di spreadsheet; angka tetap apa adanya.
+func exportText(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// exportFilterParams membaca rentang tanggal dan ?status= (dipisah koma)
+func exportFilterParams(w http.ResponseWriter, r *http.Request) (models.ExportFilter, bool) {
+	from, to, ok := dateRangeParams(w, r)
+	if !ok {
+		return models.ExportFilter{}, false
+	}
+
+	filter := models.ExportFilter{From: from, To: to, Statuses: models.DefaultExportStatuses}
+	if v := r.URL.Query().Get("status"); v != "" {
+		filter.Statuses = nil
+		for _, status := range strings.Split(v, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				filter.Statuses = append(filter.Statuses, status)
+			}
+		}
+	}
+	return filter, true
+}
+
+// exportName membentuk nama file seperti orders_2024-01-01_2024-01-31
+func exportName(prefix string, filter models.ExportFilter) string {
+	return fmt.Sprintf("%s_%s_%s", prefix, filter.From.Format("2006-01-02"), filter.To.AddDate(0, 0, -1).Format("2006-01-02"))
+}
+
+// ExportOrders streams one row per order as CSV or XLSX (?format=). from and to
+// are inclusive dates; ?status= defaults to completed and refunded orders.
+func ExportOrders(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, ok := exportFilterParams(w, r)
+	if !ok {
+		return
+	}
+
+	ew, ok := newExportWriter(w, r, exportName("orders", filter), []string{
+		"order_id", "created_at", "status", "order_type", "table", "subtotal", "discount", "service_charge", "tax", "total_price", "amount_paid", "refunded", "net",
+	})
+	if !ok {
+		return
+	}
+
+	err := st.Orders.ExportOrders(ctx, filter, func(o models.OrderExportRow) error {
+		return ew.WriteRow([]interface{}{
+			o.ID, o.CreatedAt, o.Status, o.Type, o.Table, o.Subtotal, o.Discount, o.ServiceCharge, o.Tax, o.TotalPrice, o.AmountPaid, o.Refunded, o.TotalPrice - o.Refunded,
+		})
+	})
+	finishExport(w, ew, err)
+}
+
+// ExportOrderDetails streams one row per ORDER_DETAILS line with the same
+// filters as ExportOrders.
+func ExportOrderDetails(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, ok := exportFilterParams(w, r)
+	if !ok {
+		return
+	}
+
+	ew, ok := newExportWriter(w, r, exportName("order-details", filter), []string{
+		"order_id", "created_at", "status", "detail_id", "product_id", "product_name",
+		"unit_price", "quantity", "total_price", "discount", "service_charge", "tax", "refunded_quantity",
+	})
+	if !ok {
+		return
+	}
+
+	err := st.Orders.ExportOrderLines(ctx, filter, func(l models.OrderLineExportRow) error {
+		d := l.Detail
+		return ew.WriteRow([]interface{}{
+			l.OrderID, l.CreatedAt, l.Status, d.ID, d.ProductID, d.ProductName,
+			d.UnitPrice, d.Quantity, d.TotalPrice, d.Discount, d.ServiceCharge, d.Tax, l.Refunded,
+		})
+	})
+	finishExport(w, ew, err)
+}
+
+// ExportSalesReport writes the sales report (see GetSalesReport) with one row
+// per period followed by a total row.
+func ExportSalesReport(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to, ok := dateRangeParams(w, r)
+	if !ok {
+		return
+	}
+
+	granularity := r.URL.Query().Get("granularity")
+	if granularity == "" {
+		granularity = models.ReportDaily
+	}
+	if !models.IsValidGranularity(granularity) {
+		http.Error(w, "granularity must be day, week or month", http.StatusBadRequest)
+		return
+	}
+
+	// Laporan sudah teragregasi per periode sehingga aman dibangun di memori
+	report, err := st.Orders.SalesReport(ctx, from, to, granularity, defaultReportTop)
+	if err != nil {
+		writeStoreError(w, err, "Failed to build sales report")
+		return
+	}
+
+	filter := models.ExportFilter{From: from, To: to}
+	ew, ok := newExportWriter(w, r, exportName("sales-"+granularity, filter), []string{
+		"period", "gross_revenue", "discounts", "service_charge", "tax", "refunds", "revenue", "order_count", "average_ticket", "items_sold", "top_products",
+	})
+	if !ok {
+		return
+	}
+
+	for _, b := range report.Buckets {
+		if err = ew.WriteRow(salesReportRow(b.Start, b)); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = ew.WriteRow(salesReportRow("total", report.Total))
+	}
+	finishExport(w, ew, err)
+}
+
+// salesReportRow menyusun satu baris laporan, produk terlaris digabung dalam satu sel
+func salesReportRow(period string, b models.SalesBucket) []interface{} {
+	top := make([]string, len(b.TopProducts))
+	for i, p := range b.TopProducts {
+		top[i] = fmt.Sprintf("%s (%d)", p.ProductName, p.TotalSold)
+	}
+	return []interface{}{
+		period, b.GrossRevenue, b.Discounts, b.ServiceCharge, b.Tax, b.Refunds, b.Revenue, b.OrderCount, b.AverageTicket, b.ItemsSold, strings.Join(top, "; "),
+	}
+}
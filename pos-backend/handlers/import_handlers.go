@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"pos-backend/models"
+	"pos-backend/store"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	maxImportBody  = 64 << 20
+	maxImportRows  = 5000
+	maxImportImage = 10 << 20
+)
+
+// importColumns memetakan nama kolom CSV (huruf kecil) ke kolom yang dikenal
+var importColumns = map[string]string{
+	"name":           "name",
+	"price":          "price",
+	"category":       "category",
+	"sku":            "sku",
+	"image":          "image",
+	"image_filename": "image",
+}
+
+// importLine adalah satu baris CSV yang sudah dibaca beserta nama file gambarnya
+type importLine struct {
+	row   models.ProductImportRow
+	image string
+}
+
+// ImportProducts imports products from a CSV file (form field "file") with the
+// columns name, price, category, sku and image. Images named in the CSV are
+// taken from an optional ZIP (form field "images") or must already exist in
+// UploadDir. Products are matched by SKU, then by name. With dry_run=true every
+// row is validated and reported but nothing is saved.
+func ImportProducts(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBody)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "Invalid multipart form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(r.FormValue("dry_run"))
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing CSV file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	lines, results, err := readImportCSV(file)
+	if err != nil {
+		http.Error(w, "Invalid CSV: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var images map[string]*zip.File
+	if zf, header, err := r.FormFile("images"); err == nil {
+		defer zf.Close()
+		zr, err := zip.NewReader(zf, header.Size)
+		if err != nil {
+			http.Error(w, "Invalid images ZIP: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		images = make(map[string]*zip.File)
+		for _, f := range zr.File {
+			if !f.FileInfo().IsDir() {
+				images[strings.ToLower(filepath.Base(f.Name))] = f
+			}
+		}
+	}
+
+	// Gambar diperiksa lebih dulu; baris dengan gambar bermasalah tidak ikut diimport
+	var rows []models.ProductImportRow
+	for _, l := range lines {
+		if l.image != "" {
+			path, err := importImage(images, l.image, dryRun)
+			if err != nil {
+				results = append(results, importFailure(l.row, err.Error()))
+				continue
+			}
+			l.row.Product.ImageURL = path
+		}
+		rows = append(rows, l.row)
+	}
+
+	if len(rows) > 0 {
+		saved, err := st.Products.ImportProducts(ctx, rows, dryRun)
+		if err != nil {
+			writeStoreError(w, err, "Failed to import products")
+			return
+		}
+		results = append(results, saved...)
+	}
+
+	report := models.ProductImportReport{DryRun: dryRun, Rows: results}
+	sort.Slice(report.Rows, func(i, j int) bool { return report.Rows[i].Line < report.Rows[j].Line })
+	for _, res := range report.Rows {
+		switch res.Action {
+		case models.ImportCreated:
+			report.Created++
+		case models.ImportUpdated:
+			report.Updated++
+		default:
+			report.Failed++
+		}
+	}
+	report.Total = len(report.Rows)
+	if !dryRun {
+		auditChange(r, "product", nil, nil, report)
+	}
+
+	if !dryRun && report.Created+report.Updated > 0 {
+		invalidateProducts(ctx, rdb)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// readImportCSV membaca header dan semua baris. Baris yang tidak valid
+// langsung dilaporkan sebagai gagal.
+func readImportCSV(file io.Reader) ([]importLine, []models.ProductImportResult, error) {
+	cr := csv.NewReader(file)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read header: %v", err)
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if col, ok := importColumns[name]; ok {
+			index[col] = i
+		}
+	}
+	if _, ok := index["name"]; !ok {
+		return nil, nil, errors.New("missing name column")
+	}
+	if _, ok := index["price"]; !ok {
+		return nil, nil, errors.New("missing price column")
+	}
+
+	var lines []importLine
+	var failed []models.ProductImportResult
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+		if len(lines)+len(failed) >= maxImportRows {
+			return nil, nil, fmt.Errorf("more than %d rows", maxImportRows)
+		}
+
+		field := func(col string) string {
+			if i, ok := index[col]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		row := models.ProductImportRow{Line: line, Product: models.Product{
+			Name:              field("name"),
+			SKU:               field("sku"),
+			Category:          field("category"),
+			LowStockThreshold: defaultLowStockThreshold,
+		}}
+
+		price, err := strconv.ParseFloat(field("price"), 64)
+		switch {
+		case row.Product.Name == "":
+			failed = append(failed, importFailure(row, "name is required"))
+		case err != nil || price < 0:
+			failed = append(failed, importFailure(row, "invalid price"))
+		case len(row.Product.SKU) > 64:
+			failed = append(failed, importFailure(row, "sku is longer than 64 characters"))
+		default:
+			row.Product.Price = price
+			lines = append(lines, importLine{row: row, image: field("image")})
+		}
+	}
+	return lines, failed, nil
+}
+
+func importFailure(row models.ProductImportRow, msg string) models.ProductImportResult {
+	return models.ProductImportResult{
+		Line:   row.Line,
+		Name:   row.Product.Name,
+		SKU:    row.Product.SKU,
+		Action: models.ImportFailed,
+		Error:  msg,
+	}
+}
+
+// importImage mencari gambar di ZIP (atau di UploadDir jika tidak ada ZIP) dan
+// mengembalikan path yang disimpan di image_url. Gambar hanya disalin jika
+// bukan dry run.
+func importImage(images map[string]*zip.File, name string, dryRun bool) (string, error) {
+	path := filepath.Join(UploadDir, filepath.Base(name))
+	if images == nil {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("image %s not found", name)
+		}
+		return path, nil
+	}
+
+	f, ok := images[strings.ToLower(filepath.Base(name))]
+	if !ok {
+		return "", fmt.Errorf("image %s not found in ZIP", name)
+	}
+	if f.UncompressedSize64 > maxImportImage {
+		return "", fmt.Errorf("image %s is larger than 10 MB", name)
+	}
+	if dryRun {
+		return path, nil
+	}
+
+	src, err := f.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to read image %s: %v", name, err)
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(UploadDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create directory: %v", err)
+	}
+	dst, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %v", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, io.LimitReader(src, maxImportImage)); err != nil {
+		return "", fmt.Errorf("failed to save image %s: %v", name, err)
+	}
+	return path, nil
+}
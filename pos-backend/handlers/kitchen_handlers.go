@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"pos-backend/utils"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// kitchenHeartbeat menjaga koneksi SSE tetap hidup melewati proxy
+const kitchenHeartbeat = 15 * time.Second
+
+// GetKitchenOrders returns the orders the kitchen still has to work on
+func GetKitchenOrders(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	orders, err := st.Orders.ListOrders(ctx, models.OrderFilter{Statuses: models.KitchenOrderStatuses})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// KitchenStream pushes order events to the kitchen display over Server-Sent Events.
+// The first event is a snapshot of the current kitchen orders.
+func KitchenStream(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Berlangganan dulu sebelum mengambil snapshot agar tidak ada event yang terlewat
+	sub := utils.SubscribeOrderEvents(r.Context(), rdb)
+	defer sub.Close()
+	if _, err := sub.Receive(r.Context()); err != nil {
+		http.Error(w, "Failed to subscribe to order events: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	orders, err := st.Orders.ListOrders(r.Context(), models.OrderFilter{Statuses: models.KitchenOrderStatuses})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	snapshot, _ := json.Marshal(orders)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	writeSSE(w, "snapshot", snapshot)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(kitchenHeartbeat)
+	defer heartbeat.Stop()
+	events := sub.Channel()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			io.WriteString(w, ": ping\n\n")
+		case msg, ok := <-events:
+			if !ok {
+				return
+			}
+			var event models.OrderEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				continue
+			}
+			writeSSE(w, event.Type, []byte(msg.Payload))
+		}
+		flusher.Flush()
+	}
+}
+
+func writeSSE(w io.Writer, event string, data []byte) {
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
+
+// PrepareOrderItem marks one order line as prepared, or unprepared with {"prepared": false}
+func PrepareOrderItem(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	detailID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid order detail ID", http.StatusBadRequest)
+		return
+	}
+
+	req := struct {
+		Prepared *bool `json:"prepared"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	prepared := req.Prepared == nil || *req.Prepared
+
+	detail, err := st.Orders.SetItemPrepared(ctx, detailID, prepared, utils.SessionFromContext(r.Context()).Username)
+	if err != nil {
+		writeStoreError(w, err, "Failed to mark item")
+		return
+	}
+	auditChange(r, "order_detail", detailID, nil, detail)
+
+	publishOrderEvent(ctx, st, rdb, models.OrderEventItemPrepared, detail.OrderID, detail)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(detail)
+}
+
+// publishOrderEvent mengirim keadaan terbaru order ke layar dapur. Gagal
+// mengirim event hanya dicatat di log karena perubahan sudah tersimpan.
+func publishOrderEvent(ctx context.Context, st *store.Store, rdb *redis.Client, eventType string, orderID int, detail *models.OrderDetail) {
+	order, err := st.Orders.GetOrder(ctx, orderID)
+	if err != nil {
+		log.Printf("Failed to load order %d for %s event: %v", orderID, eventType, err)
+		return
+	}
+
+	event := models.OrderEvent{
+		Type:    eventType,
+		OrderID: orderID,
+		Status:  order.Status,
+		Order:   order,
+		Detail:  detail,
+	}
+	if err := utils.PublishOrderEvent(ctx, rdb, event); err != nil {
+		log.Printf("Failed to publish %s event for order %d: %v", eventType, orderID, err)
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// GetModifierGroups menampilkan grup modifier sebuah produk (?product_id=)
+func GetModifierGroups(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	productID, err := strconv.Atoi(r.URL.Query().Get("product_id"))
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	groups, err := st.Products.ModifierGroups(ctx, productID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(groups)
+}
+
+// SaveModifierGroup creates a modifier group when id is 0 and updates it
+// otherwise. The options in the body replace the group's options: options
+// with an id are updated, options without one are added and the rest removed.
+func SaveModifierGroup(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var group models.ModifierGroup
+	if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := st.Products.SaveModifierGroup(ctx, &group); err != nil {
+		writeStoreError(w, err, "Failed to save modifier group")
+		return
+	}
+	auditChange(r, "modifier_group", group.ID, nil, group)
+
+	// Modifier ikut tersimpan di cache produk
+	invalidateProducts(ctx, rdb)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// DeleteModifierGroup menghapus grup modifier beserta pilihannya (/delete-modifier-group/{id})
+func DeleteModifierGroup(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/delete-modifier-group/"))
+	if err != nil {
+		http.Error(w, "Invalid modifier group ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := st.Products.DeleteModifierGroup(ctx, id); err != nil {
+		writeStoreError(w, err, "Failed to delete modifier group")
+		return
+	}
+	auditChange(r, "modifier_group", id, nil, nil)
+
+	invalidateProducts(ctx, rdb)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,65 +2,33 @@ package handlers
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
+	"io"
+	"log"
 	"net/http"
 	"pos-backend/models"
+	"pos-backend/store"
+	"pos-backend/utils"
 	"strconv"
 
 	"github.com/go-redis/redis/v8"
-	_ "github.com/godror/godror"
 )
 
-// GetOrders retrieves orders with status 'On Progress'
-func GetOrders(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
-	rows, err := db.Query("SELECT id, menu, status, total_price, created_at FROM SYSBACKUP.ORDERS WHERE status = 'On Progress' ORDER BY id ASC")
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+// GetOrders retrieves orders that are still open (Draft, On Progress or Ready),
+// optionally filtered by ?type= (dine_in, takeaway, delivery) and ?table= (table name)
+func GetOrders(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	filter := models.OrderFilter{
+		Statuses: models.OpenOrderStatuses,
+		Type:     r.URL.Query().Get("type"),
+		Table:    r.URL.Query().Get("table"),
+	}
+	if filter.Type != "" && !models.IsValidOrderType(filter.Type) {
+		http.Error(w, "Invalid order type", http.StatusBadRequest)
 		return
 	}
-	defer rows.Close()
-
-	var orders []models.Order
-	for rows.Next() {
-		var order models.Order
-		var totalPrice sql.NullFloat64
-		err := rows.Scan(&order.ID, &order.Menu, &order.Status, &totalPrice, &order.CreatedAt)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		if totalPrice.Valid {
-			order.TotalPrice = new(float64)
-			*order.TotalPrice = totalPrice.Float64
-		} else {
-			order.TotalPrice = nil
-		}
-
-		detailRows, err := db.Query("SELECT product_name, quantity, total_price FROM SYSBACKUP.ORDER_DETAILS WHERE order_id = :1", order.ID)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer detailRows.Close()
-
-		var orderDetails []models.OrderDetail
-		for detailRows.Next() {
-			var detail models.OrderDetail
-			err := detailRows.Scan(&detail.ProductName, &detail.Quantity, &detail.TotalPrice)
-			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
-			}
-			orderDetails = append(orderDetails, detail)
-		}
-		order.Details = orderDetails
-
-		orders = append(orders, order)
-	}
-
-	if err = rows.Err(); err != nil {
+
+	orders, err := st.Orders.ListOrders(ctx, filter)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -70,7 +38,7 @@ func GetOrders(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.Respon
 }
 
 // CreateOrder creates a new order
-func CreateOrder(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+func CreateOrder(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
 		return
@@ -83,160 +51,228 @@ func CreateOrder(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.Resp
 		return
 	}
 
-	tx, err := db.Begin()
+	orderID, err := st.Orders.CreateOrder(ctx, &order, utils.SessionFromContext(r.Context()).Username)
 	if err != nil {
-		http.Error(w, "Failed to begin transaction: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err, "Failed to create order")
 		return
 	}
-	defer tx.Rollback()
+	auditOrder(ctx, st, r, orderID, nil)
 
-	var orderID int
-	query := `INSERT INTO SYSBACKUP.ORDERS (total_price, status) VALUES (:1, 'On Progress') RETURNING id INTO :2`
-	stmt, err := tx.Prepare(query)
-	if err != nil {
-		http.Error(w, "Failed to prepare statement: "+err.Error(), http.StatusInternalServerError)
+	// Stok produk berubah, kosongkan cache produk
+	invalidateProducts(ctx, rdb)
+	publishOrderEvent(ctx, st, rdb, models.OrderEventCreated, orderID, nil)
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte("Order created successfully. Order ID: " + strconv.Itoa(orderID)))
+}
+
+// CompleteOrder records the given payments and marks the order as completed
+func CompleteOrder(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
 		return
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec(order.TotalPrice, sql.Out{Dest: &orderID})
-	if err != nil {
-		http.Error(w, "Failed to create order: "+err.Error(), http.StatusInternalServerError)
+	id, ok := orderIDParam(w, r)
+	if !ok {
 		return
 	}
 
-	for _, item := range order.Items {
-		var productID int
-		err = tx.QueryRow("SELECT id FROM SYSBACKUP.PRODUCTS WHERE name = :1", item.ProductName).Scan(&productID)
-		if err != nil {
-			http.Error(w, "Failed to find product: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		detailQuery := "INSERT INTO SYSBACKUP.ORDER_DETAILS (order_id, product_name, quantity, total_price) VALUES (:1, :2, :3, :4)"
-		_, err = tx.Exec(detailQuery, orderID, item.ProductName, item.Quantity, item.TotalPrice)
-		if err != nil {
-			http.Error(w, "Failed to create order details: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
+	// Body boleh kosong jika order sudah dibayar lewat /add-payment
+	req, ok := decodePaymentRequest(w, r)
+	if !ok {
+		return
 	}
 
-	if err = tx.Commit(); err != nil {
-		http.Error(w, "Failed to commit transaction: "+err.Error(), http.StatusInternalServerError)
+	before, _ := st.Orders.GetOrder(ctx, id)
+	summary, err := st.Orders.CompleteOrder(ctx, id, req.Payments, utils.SessionFromContext(r.Context()).Username)
+	if err != nil {
+		writeStoreError(w, err, "Failed to complete order")
 		return
 	}
+	auditOrder(ctx, st, r, id, before)
+	publishOrderEvent(ctx, st, rdb, models.OrderEventUpdated, id, nil)
+	invalidateReports(ctx, rdb)
 
-	w.WriteHeader(http.StatusCreated)
-	w.Write([]byte("Order created successfully. Order ID: " + strconv.Itoa(orderID)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
 }
 
-// CompleteOrder marks an order as completed
-func CompleteOrder(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+// AddPayment records one or more tenders on an order that is still in progress
+func AddPayment(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
 		return
 	}
 
-	id := r.URL.Query().Get("id")
-	if id == "" {
-		http.Error(w, "Missing order ID", http.StatusBadRequest)
+	id, ok := orderIDParam(w, r)
+	if !ok {
 		return
 	}
 
-	queryComplete := "UPDATE SYSBACKUP.ORDERS SET status = 'Order Completed' WHERE id = :1"
-	result, err := db.Exec(queryComplete, id)
+	req, ok := decodePaymentRequest(w, r)
+	if !ok {
+		return
+	}
+	if len(req.Payments) == 0 {
+		http.Error(w, "No payments given", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := st.Orders.GetOrder(ctx, id)
+	summary, err := st.Orders.AddPayments(ctx, id, req.Payments, utils.SessionFromContext(r.Context()).Username)
 	if err != nil {
-		http.Error(w, "Failed to update order status: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err, "Failed to record payment")
 		return
 	}
+	auditOrder(ctx, st, r, id, before)
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		http.Error(w, "No order found with the given ID", http.StatusNotFound)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// GetOrderPayments returns the payment breakdown of an order
+func GetOrderPayments(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	id, ok := orderIDParam(w, r)
+	if !ok {
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Order marked as completed successfully. Order ID: " + id))
+	summary, err := st.Orders.PaymentSummary(ctx, id)
+	if err != nil {
+		writeStoreError(w, err, "Failed to retrieve payments")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
 }
 
-// CancelOrder marks an order as canceled
-func CancelOrder(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+type paymentRequest struct {
+	Payments []models.Payment `json:"payments"`
+}
+
+// decodePaymentRequest membaca body {"payments": [...]}, body kosong diperbolehkan
+func decodePaymentRequest(w http.ResponseWriter, r *http.Request) (paymentRequest, bool) {
+	var req paymentRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && err != io.EOF {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return req, false
+	}
+	return req, true
+}
+
+type statusRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// decodeStatusRequest membaca body {"status": ..., "reason": ...}, body kosong diperbolehkan
+func decodeStatusRequest(w http.ResponseWriter, r *http.Request) (statusRequest, bool) {
+	var req statusRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && err != io.EOF {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return req, false
+	}
+	return req, true
+}
+
+// UpdateOrderStatus moves an order to On Progress or Ready. Completing and
+// canceling go through their own endpoints because they touch payments and stock.
+func UpdateOrderStatus(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
 		return
 	}
 
-	id := r.URL.Query().Get("id")
-	if id == "" {
-		http.Error(w, "Missing order ID", http.StatusBadRequest)
+	id, ok := orderIDParam(w, r)
+	if !ok {
 		return
 	}
 
-	queryCancel := "UPDATE SYSBACKUP.ORDERS SET status = 'Order Canceled' WHERE id = :1"
-	result, err := db.Exec(queryCancel, id)
-	if err != nil {
-		http.Error(w, "Failed to update order status: "+err.Error(), http.StatusInternalServerError)
+	req, ok := decodeStatusRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.Status == "" {
+		http.Error(w, "Missing status", http.StatusBadRequest)
 		return
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		http.Error(w, "No order found with the given ID", http.StatusNotFound)
+	before, _ := st.Orders.GetOrder(ctx, id)
+	err := st.Orders.SetOrderStatus(ctx, id, req.Status, utils.SessionFromContext(r.Context()).Username, req.Reason)
+	if err != nil {
+		writeStoreError(w, err, "Failed to update order status")
 		return
 	}
+	auditOrder(ctx, st, r, id, before)
+	publishOrderEvent(ctx, st, rdb, models.OrderEventUpdated, id, nil)
 
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Order marked as canceled successfully. Order ID: " + id))
+	w.Write([]byte("Order status updated to " + req.Status + ". Order ID: " + strconv.Itoa(id)))
 }
 
-// GetCompletedOrders retrieves completed orders
-func GetCompletedOrders(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
-	rows, err := db.Query("SELECT id, menu, status, total_price, created_at FROM SYSBACKUP.ORDERS WHERE status IN ('Order Completed', 'Order Canceled') ORDER BY id ASC")
+// GetOrderTimeline returns every status change of an order, oldest first
+func GetOrderTimeline(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	id, ok := orderIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	timeline, err := st.Orders.OrderTimeline(ctx, id)
 	if err != nil {
-		http.Error(w, "Failed to retrieve completed orders: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err, "Failed to retrieve order timeline")
 		return
 	}
-	defer rows.Close()
 
-	var orders []models.Order
-	for rows.Next() {
-		var order models.Order
-		var totalPrice sql.NullFloat64
-		err := rows.Scan(&order.ID, &order.Menu, &order.Status, &totalPrice, &order.CreatedAt)
-		if err != nil {
-			http.Error(w, "Failed to parse order data: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(timeline)
+}
 
-		if totalPrice.Valid {
-			order.TotalPrice = &totalPrice.Float64
-		}
+// CancelOrder marks an order as canceled
+func CancelOrder(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
 
-		orderDetailsRows, err := db.Query("SELECT product_name, quantity FROM SYSBACKUP.ORDER_DETAILS WHERE order_id = :1", order.ID)
-		if err != nil {
-			http.Error(w, "Failed to retrieve order details: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer orderDetailsRows.Close()
+	id, ok := orderIDParam(w, r)
+	if !ok {
+		return
+	}
 
-		var details []models.OrderDetail
-		for orderDetailsRows.Next() {
-			var detail models.OrderDetail
-			err := orderDetailsRows.Scan(&detail.ProductName, &detail.Quantity)
-			if err != nil {
-				http.Error(w, "Failed to parse order detail data: "+err.Error(), http.StatusInternalServerError)
-				return
-			}
-			details = append(details, detail)
-		}
+	// Alasan pembatalan opsional, disimpan di riwayat status
+	req, ok := decodeStatusRequest(w, r)
+	if !ok {
+		return
+	}
 
-		order.Details = details
-		orders = append(orders, order)
+	before, _ := st.Orders.GetOrder(ctx, id)
+	err := st.Orders.CancelOrder(ctx, id, utils.SessionFromContext(r.Context()).Username, req.Reason)
+	if err == store.ErrNotFound {
+		http.Error(w, "No order found with the given ID", http.StatusNotFound)
+		return
+	} else if err != nil {
+		writeStoreError(w, err, "Failed to update order status")
+		return
 	}
+	auditOrder(ctx, st, r, id, before)
 
-	if err = rows.Err(); err != nil {
-		http.Error(w, "Error occurred during row iteration: "+err.Error(), http.StatusInternalServerError)
+	// Stok dikembalikan, kosongkan cache produk
+	invalidateProducts(ctx, rdb)
+	publishOrderEvent(ctx, st, rdb, models.OrderEventCanceled, id, nil)
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Order marked as canceled successfully. Order ID: " + strconv.Itoa(id)))
+}
+
+// GetCompletedOrders retrieves completed orders
+func GetCompletedOrders(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	orders, err := st.Orders.ListOrders(ctx, models.OrderFilter{Statuses: []string{models.OrderStatusCompleted, models.OrderStatusCanceled, models.OrderStatusRefunded, models.OrderStatusSplit, models.OrderStatusMerged}})
+	if err != nil {
+		http.Error(w, "Failed to retrieve completed orders: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
@@ -249,32 +285,57 @@ func GetCompletedOrders(ctx context.Context, db *sql.DB, rdb *redis.Client, w ht
 	json.NewEncoder(w).Encode(orders)
 }
 
-// DeleteOrder deletes an order by ID
-func DeleteOrder(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+// DeleteOrder deletes an order that is still open. Completed orders are refunded instead.
+func DeleteOrder(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
 		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
 		return
 	}
 
-	id := r.URL.Query().Get("id")
-	if id == "" {
-		http.Error(w, "Missing order ID", http.StatusBadRequest)
+	id, ok := orderIDParam(w, r)
+	if !ok {
 		return
 	}
 
-	query := "DELETE FROM SYSBACKUP.ORDERS WHERE id = :1"
-	result, err := db.Exec(query, id)
-	if err != nil {
-		http.Error(w, "Failed to delete order: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
+	// Order yang dihapus hanya tersisa di log audit
+	before, _ := st.Orders.GetOrder(ctx, id)
+	err := st.Orders.DeleteOrder(ctx, id)
+	if err == store.ErrNotFound {
 		http.Error(w, "No order found with the given ID", http.StatusNotFound)
 		return
+	} else if err != nil {
+		writeStoreError(w, err, "Failed to delete order")
+		return
+	}
+	auditChange(r, "order", id, before, nil)
+	// Stok order yang masih berjalan dikembalikan
+	invalidateProducts(ctx, rdb)
+	invalidateReports(ctx, rdb)
+
+	// Order sudah tidak bisa dibaca ulang, event dibuat dari data sebelum dihapus
+	event := models.OrderEvent{Type: models.OrderEventDeleted, OrderID: id}
+	if before != nil {
+		event.Status = before.Status
+	}
+	if err := utils.PublishOrderEvent(ctx, rdb, event); err != nil {
+		log.Printf("Failed to publish %s event for order %d: %v", event.Type, id, err)
 	}
 
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Order deleted successfully. Order ID: " + id))
+	w.Write([]byte("Order deleted successfully. Order ID: " + strconv.Itoa(id)))
+}
+
+// orderIDParam membaca ?id= dan menulis 400 jika tidak valid
+func orderIDParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Missing order ID", http.StatusBadRequest)
+		return 0, false
+	}
+	orderID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return orderID, true
 }
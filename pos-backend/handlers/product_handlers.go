@@ -2,67 +2,82 @@ package handlers
 
 import (
 	"context"
-	"database/sql"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"os"
 	"path/filepath"
 	"pos-backend/models"
+	"pos-backend/store"
 	"strconv"
 
 	"github.com/go-redis/redis/v8"
-	_ "github.com/godror/godror"
 )
 
+// UploadDir adalah folder penyimpanan gambar produk, diatur dari config
+var UploadDir = "uploads"
 
-func GetProducts(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+const defaultLowStockThreshold = 5
+
+// productsCacheKey menyimpan semua produk; produk per kategori disimpan di
+// productsCacheKey + ":category:<id>"
+const productsCacheKey = "products"
+
+// invalidateProducts membuang cache semua produk dan cache per kategori
+func invalidateProducts(ctx context.Context, rdb *redis.Client) {
+	keys := []string{productsCacheKey}
+	iter := rdb.Scan(ctx, 0, productsCacheKey+":category:*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	rdb.Del(ctx, keys...)
+}
+
+// GetProducts menampilkan produk terurut per kategori, ?category_id= untuk satu kategori saja
+func GetProducts(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
 		return
 	}
 
+	cacheKey := productsCacheKey
+	categoryID := 0
+	if v := r.URL.Query().Get("category_id"); v != "" {
+		var err error
+		categoryID, err = strconv.Atoi(v)
+		if err != nil || categoryID <= 0 {
+			http.Error(w, "Invalid category ID", http.StatusBadRequest)
+			return
+		}
+		cacheKey = fmt.Sprintf("%s:category:%d", productsCacheKey, categoryID)
+	}
+
 	// Cek apakah data produk ada di Redis
-	val, err := rdb.Get(ctx, "products").Result()
+	val, err := rdb.Get(ctx, cacheKey).Result()
 	if err == redis.Nil {
 		// Data tidak ada di Redis, ambil dari database
-		rows, err := db.Query("SELECT id, name, price, image_url FROM SYSBACKUP.PRODUCTS ORDER BY id ASC")
+		products, err := st.Products.ListProducts(ctx, categoryID)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		defer rows.Close()
-
-		var products []models.Product
-		for rows.Next() {
-			var product models.Product
-			err := rows.Scan(&product.ID, &product.Name, &product.Price, &product.ImageURL)
-			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
-			}
 
-			base64Image, err := encodeImageToBase64(product.ImageURL)
+		for i := range products {
+			base64Image, err := encodeImageToBase64(products[i].ImageURL)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
-			product.ImageURL = base64Image
-			products = append(products, product)
-		}
-
-		if err = rows.Err(); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+			products[i].ImageURL = base64Image
 		}
 
 		// Simpan data produk ke Redis dalam format JSON
 		productsJSON, _ := json.Marshal(products)
-		rdb.Set(ctx, "products", productsJSON, 0) // Set data di Redis tanpa expiration
+		rdb.Set(ctx, cacheKey, productsJSON, 0) // Set data di Redis tanpa expiration
 
 		w.Header().Set("Content-Type", "application/json")
 		w.Write(productsJSON) // Kirim data produk
@@ -95,7 +110,7 @@ return "data:image/png;base64," + base64Image, nil
 }
 
 // Ambil produk berdasarkan ID
-func GetProductByID(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+func GetProductByID(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
 		return
@@ -107,12 +122,15 @@ func GetProductByID(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.R
 		http.Error(w, "Missing product ID", http.StatusBadRequest)
 		return
 	}
+	productID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
 
-	var product models.Product
-	query := "SELECT id, name, price FROM SYSBACKUP.PRODUCTS WHERE id = :1"
-	err := db.QueryRow(query, id).Scan(&product.ID, &product.Name, &product.Price)
+	product, err := st.Products.GetProduct(ctx, productID)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if err == store.ErrNotFound {
 			http.Error(w, "Product not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -126,14 +144,14 @@ func GetProductByID(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.R
 
 
 //gambar
-func CreateProduct(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+func CreateProduct(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
 		return
 	}
 
 	// Upload gambar
-	imageURL, err := uploadImage(ctx, db, rdb, w, r)
+	imageURL, err := uploadImage(ctx, st, rdb, w, r)
 	if err != nil {
 		http.Error(w, "Image upload failed: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -144,36 +162,53 @@ func CreateProduct(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.Re
 	product.Name = r.FormValue("name")
 	product.Price, err = strconv.ParseFloat(r.FormValue("price"), 64)
 	product.ImageURL = imageURL
+	product.SKU = r.FormValue("sku")
+	product.Category = r.FormValue("category")
 
 	if err != nil {
 		http.Error(w, "Invalid product data", http.StatusBadRequest)
 		return
 	}
-
-	// Siapkan statement SQL untuk menghindari SQL injection
-	stmt, err := db.Prepare("INSERT INTO SYSBACKUP.PRODUCTS (name, price, image_url) VALUES (:1, :2, :3) RETURNING id INTO :4")
-	if err != nil {
-		http.Error(w, "Failed to prepare statement: "+err.Error(), http.StatusInternalServerError)
+	if product.CategoryID, err = categoryIDForm(r); err != nil {
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
 		return
 	}
-	defer stmt.Close()
 
-	// Menyimpan ID produk terakhir yang dimasukkan
-	var lastInsertID int
-	_, err = stmt.Exec(product.Name, product.Price, product.ImageURL, &lastInsertID)
+	// Stok awal opsional, kosong berarti stok tidak dilacak
+	product.LowStockThreshold = defaultLowStockThreshold
+	if v := r.FormValue("stock"); v != "" {
+		stock, err := strconv.Atoi(v)
+		if err != nil || stock < 0 {
+			http.Error(w, "Invalid stock", http.StatusBadRequest)
+			return
+		}
+		product.Stock = &stock
+	}
+	if v := r.FormValue("low_stock_threshold"); v != "" {
+		product.LowStockThreshold, err = strconv.Atoi(v)
+		if err != nil || product.LowStockThreshold < 0 {
+			http.Error(w, "Invalid low stock threshold", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Simpan produk dan ambil ID yang baru dibuat
+	lastInsertID, err := st.Products.CreateProduct(ctx, &product)
 	if err != nil {
-		http.Error(w, "Failed to execute statement: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err, "Failed to create product")
 		return
 	}
+	product.ID = lastInsertID
+	auditChange(r, "product", lastInsertID, nil, product)
 
 	// Kirim respons sukses dengan ID produk yang baru ditambahkan
 	w.WriteHeader(http.StatusCreated)
 	fmt.Fprintf(w, "Product added successfully with ID: %d", lastInsertID)
-	rdb.Del(ctx, "products") // Menghapus cache Redis
+	invalidateProducts(ctx, rdb) // Menghapus cache Redis
 }
 
 // Update produk
-func UpdateProduct(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+func UpdateProduct(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
 		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
 		return
@@ -182,19 +217,43 @@ func UpdateProduct(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.Re
 	// Ambil ID dari URL
 	id := r.URL.Path[len("/update-product/"):]
 
-	// Debugging: Log ID yang diterima
-	log.Println("Updating product with ID:", id)
-
 	// Decode data produk dari form
 	var product models.Product
 	product.ID, _ = strconv.Atoi(id)
+
+	// Data lama dipakai untuk log audit dan nilai default
+	existing, err := st.Products.GetProduct(ctx, product.ID)
+	if err == store.ErrNotFound {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "Failed to update product: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	product.Name = r.FormValue("name")
 	product.Price, _ = strconv.ParseFloat(r.FormValue("price"), 64)
+	product.SKU = r.FormValue("sku")
+	product.Category = r.FormValue("category")
+
+	// Batas stok menipis tetap memakai nilai lama jika tidak dikirim
+	if product.CategoryID, err = categoryIDForm(r); err != nil {
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+	if v := r.FormValue("low_stock_threshold"); v != "" {
+		product.LowStockThreshold, err = strconv.Atoi(v)
+		if err != nil || product.LowStockThreshold < 0 {
+			http.Error(w, "Invalid low stock threshold", http.StatusBadRequest)
+			return
+		}
+	} else {
+		product.LowStockThreshold = existing.LowStockThreshold
+	}
 
 	// Proses upload gambar (jika ada)
-	var err error
 	if r.MultipartForm != nil {
-		imageURL, uploadErr := uploadImage(ctx, db, rdb, w, r)
+		imageURL, uploadErr := uploadImage(ctx, st, rdb, w, r)
 		if uploadErr == nil {
 			product.ImageURL = imageURL // Simpan URL gambar baru jika upload berhasil
 		} else {
@@ -203,30 +262,31 @@ func UpdateProduct(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.Re
 		}
 	}
 
-	// Debugging: Log product data
-	log.Println("Product data to update:", product)
-
-	// Query untuk update produk, gunakan gambar baru jika ada, jika tidak gunakan gambar lama
-	query := `
-		UPDATE SYSBACKUP.PRODUCTS 
-		SET name = :1, price = :2, image_url = COALESCE(NULLIF(:3, ''), image_url) 
-		WHERE id = :4
-	`
-	_, err = db.Exec(query, product.Name, product.Price, product.ImageURL, product.ID)
-	if err != nil {
+	// Update produk, gunakan gambar baru jika ada, jika tidak gunakan gambar lama
+	err = st.Products.UpdateProduct(ctx, &product)
+	if err == store.ErrNotFound {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	} else if errors.Is(err, store.ErrConflict) || errors.Is(err, store.ErrInvalid) {
+		writeStoreError(w, err, "Failed to update product")
+		return
+	} else if err != nil {
 		http.Error(w, "Failed to update product: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if updated, err := st.Products.GetProduct(ctx, product.ID); err == nil {
+		auditChange(r, "product", product.ID, existing, updated)
+	}
 
 	// Kosongkan cache di Redis setelah update
-	rdb.Del(ctx, "products")
+	invalidateProducts(ctx, rdb)
 
 	// Kirim respons sukses
 	fmt.Fprintf(w, "Product updated successfully with ID: %d", product.ID)
 }
 
 // Hapus produk
-func DeleteProduct(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+func DeleteProduct(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
 		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
 		return
@@ -235,23 +295,31 @@ func DeleteProduct(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.Re
 	// Ambil ID dari URL
 	id := r.URL.Path[len("/delete-product/"):]
 
-	// Debugging: Log ID yang diterima
-	log.Println("Deleting product with ID:", id)
-
-	// Query untuk hapus produk
-	_, err := db.Exec("DELETE FROM SYSBACKUP.PRODUCTS WHERE id = :1", id)
+	productID, err := strconv.Atoi(id)
 	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	// Hapus produk, data lama disimpan di log audit
+	existing, _ := st.Products.GetProduct(ctx, productID)
+	err = st.Products.DeleteProduct(ctx, productID)
+	if err == store.ErrNotFound {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	} else if err != nil {
 		http.Error(w, "Failed to delete product: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	auditChange(r, "product", productID, existing, nil)
 
 	// Kosongkan cache di Redis setelah penghapusan
-	rdb.Del(ctx, "products")
+	invalidateProducts(ctx, rdb)
 
 	// Kirim respons sukses
 	fmt.Fprintf(w, "Product deleted successfully with ID: %s", id)
 }
-func uploadImage(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) (string, error) {
+func uploadImage(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) (string, error) {
 	// Parse multipart form, allowing up to 10 MB files
 	err := r.ParseMultipartForm(10 << 20)
 	if err != nil {
@@ -266,13 +334,13 @@ func uploadImage(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.Resp
 	defer file.Close()
 
 	// Create a directory to store images, if not exists
-	err = os.MkdirAll("./uploads", os.ModePerm)
+	err = os.MkdirAll(UploadDir, os.ModePerm)
 	if err != nil {
 		return "", fmt.Errorf("failed to create directory: %v", err)
 	}
 
 	// Generate the file path to store the image
-	filePath := filepath.Join("uploads", handler.Filename)
+	filePath := filepath.Join(UploadDir, filepath.Base(handler.Filename))
 
 	// Create a new file in the uploads directory
 	dst, err := os.Create(filePath)
@@ -290,3 +358,16 @@ func uploadImage(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.Resp
 	// Return the file path as the image URL
 	return filePath, nil
 }
+
+// categoryIDForm membaca category_id dari form, kosong berarti tidak diubah
+func categoryIDForm(r *http.Request) (*int, error) {
+	v := r.FormValue("category_id")
+	if v == "" {
+		return nil, nil
+	}
+	id, err := strconv.Atoi(v)
+	if err != nil || id <= 0 {
+		return nil, fmt.Errorf("invalid category ID %q", v)
+	}
+	return &id, nil
+}
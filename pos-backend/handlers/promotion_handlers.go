@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// GetPromotions menampilkan semua aturan promo
+func GetPromotions(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	promotions, err := st.Promotions.ListPromotions(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotions)
+}
+
+// SavePromotion creates a promotion when id is 0 and updates it otherwise.
+// usage_count in the body is ignored; it only changes when orders use the code.
+func SavePromotion(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var promo models.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promo); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := st.Promotions.GetPromotion(ctx, promo.ID)
+	if err := st.Promotions.SavePromotion(ctx, &promo); err != nil {
+		writeStoreError(w, err, "Failed to save promotion")
+		return
+	}
+	auditChange(r, "promotion", promo.ID, before, promo)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promo)
+}
+
+// DeletePromotion menghapus aturan promo (/delete-promotion/{id})
+func DeletePromotion(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/delete-promotion/"))
+	if err != nil {
+		http.Error(w, "Invalid promotion ID", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := st.Promotions.GetPromotion(ctx, id)
+	if err := st.Promotions.DeletePromotion(ctx, id); err != nil {
+		writeStoreError(w, err, "Failed to delete promotion")
+		return
+	}
+	auditChange(r, "promotion", id, before, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PriceOrder takes the same body as /create-order and returns the priced
+// order with line discounts, applied promotions and the total to charge,
+// without saving anything. Cashiers use it to preview a promo code.
+func PriceOrder(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var order models.Order
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := st.Orders.PriceOrder(ctx, &order); err != nil {
+		writeStoreError(w, err, "Failed to price order")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// GetPromotionReport returns gross revenue, total discount and the discount
+// given by each promotion on completed orders between from and to (inclusive
+// dates, YYYY-MM-DD).
+func GetPromotionReport(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to, ok := dateRangeParams(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := st.Promotions.PromotionReport(ctx, from, to)
+	if err != nil {
+		writeStoreError(w, err, "Failed to build promotion report")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
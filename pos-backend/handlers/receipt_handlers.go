@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"pos-backend/receipt"
+	"pos-backend/store"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Receipts merender struk sesuai template toko, diatur dari config
+var Receipts *receipt.Renderer
+
+// GetReceipt renders the receipt of an order. ?format= selects text (default),
+// escpos for a raw thermal printer stream, or pdf.
+func GetReceipt(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	order, err := st.Orders.GetOrder(ctx, id)
+	if err != nil {
+		writeStoreError(w, err, "Failed to retrieve order")
+		return
+	}
+
+	var body []byte
+	var contentType, ext string
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "text":
+		body, err = Receipts.Text(*order)
+		contentType, ext = "text/plain; charset=utf-8", "txt"
+	case "escpos":
+		body, err = Receipts.ESCPOS(*order)
+		contentType, ext = "application/octet-stream", "bin"
+	case "pdf":
+		body, err = Receipts.PDF(*order)
+		contentType, ext = "application/pdf", "pdf"
+	default:
+		http.Error(w, "Unknown receipt format: "+format, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to render receipt: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"receipt-%d.%s\"", id, ext))
+	w.Write(body)
+}
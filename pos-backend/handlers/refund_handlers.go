@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"pos-backend/utils"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RefundApprovalThreshold adalah batas total refund per order tanpa
+// persetujuan admin, diatur dari config
+var RefundApprovalThreshold float64 = 100000
+
+// refundRequest adalah refund dari kasir. Approver dan ApproverPassword
+// diisi admin yang menyetujui refund di atas RefundApprovalThreshold.
+type refundRequest struct {
+	Items            []models.RefundItem `json:"items"`
+	Reason           string              `json:"reason"`
+	Note             string              `json:"note"`
+	Restock          bool                `json:"restock"`
+	Method           string              `json:"method"`
+	Approver         string              `json:"approver"`
+	ApproverPassword string              `json:"approver_password"`
+}
+
+// RefundOrder mengembalikan uang untuk seluruh atau sebagian baris order yang sudah selesai
+func RefundOrder(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := orderIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req refundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	session := utils.SessionFromContext(r.Context())
+	refund := models.Refund{
+		OrderID:  id,
+		Method:   req.Method,
+		Reason:   req.Reason,
+		Note:     req.Note,
+		Restock:  req.Restock,
+		Items:    req.Items,
+		Username: session.Username,
+	}
+
+	// Admin menyetujui refund-nya sendiri, kasir butuh username dan password admin
+	if session.Role == models.RoleAdmin {
+		refund.ApprovedBy = session.Username
+	} else if req.Approver != "" {
+		approver, err := st.Users.GetUserByUsername(ctx, req.Approver)
+		if err != nil || approver.Role != models.RoleAdmin || !utils.CheckPasswordHash(req.ApproverPassword, approver.Password) {
+			http.Error(w, "Invalid approver credentials", http.StatusForbidden)
+			return
+		}
+		refund.ApprovedBy = approver.Username
+	}
+
+	before, _ := st.Orders.GetOrder(ctx, id)
+	if err := st.Orders.RefundOrder(ctx, &refund, RefundApprovalThreshold); err != nil {
+		writeStoreError(w, err, "Failed to refund order")
+		return
+	}
+	auditOrder(ctx, st, r, id, before)
+	publishOrderEvent(ctx, st, rdb, models.OrderEventUpdated, id, nil)
+	invalidateReports(ctx, rdb)
+
+	if refund.Restock {
+		// Stok dikembalikan, kosongkan cache produk
+		invalidateProducts(ctx, rdb)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(refund)
+}
+
+// GetOrderRefunds menampilkan semua refund sebuah order
+func GetOrderRefunds(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	id, ok := orderIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	refunds, err := st.Orders.OrderRefunds(ctx, id)
+	if err != nil {
+		writeStoreError(w, err, "Failed to retrieve refunds")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(refunds)
+}
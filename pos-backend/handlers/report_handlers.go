@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// reportVersionKey dinaikkan setiap ada perubahan penjualan sehingga
+	// semua cache laporan lama otomatis tidak dipakai lagi
+	reportVersionKey = "report:version"
+	reportCacheTTL   = 10 * time.Minute
+
+	defaultReportDays = 30
+	defaultReportTop  = 5
+	maxReportTop      = 50
+)
+
+// invalidateReports membuang cache laporan penjualan
+func invalidateReports(ctx context.Context, rdb *redis.Client) {
+	rdb.Incr(ctx, reportVersionKey)
+}
+
+// GetSalesReport returns revenue, order count, average ticket, items sold and
+// top products per day, week or month. from and to are inclusive dates
+// (YYYY-MM-DD) and default to the last 30 days.
+func GetSalesReport(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to, ok := dateRangeParams(w, r)
+	if !ok {
+		return
+	}
+
+	granularity := r.URL.Query().Get("granularity")
+	if granularity == "" {
+		granularity = models.ReportDaily
+	}
+	if !models.IsValidGranularity(granularity) {
+		http.Error(w, "granularity must be day, week or month", http.StatusBadRequest)
+		return
+	}
+
+	top := defaultReportTop
+	if v := r.URL.Query().Get("top"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > maxReportTop {
+			http.Error(w, fmt.Sprintf("top must be between 0 and %d", maxReportTop), http.StatusBadRequest)
+			return
+		}
+		top = n
+	}
+
+	// Cek apakah laporan yang sama sudah ada di Redis
+	version, _ := rdb.Get(ctx, reportVersionKey).Int64()
+	key := fmt.Sprintf("report:sales:%d:%s:%s:%s:%d", version, from.Format("2006-01-02"), to.Format("2006-01-02"), granularity, top)
+	if val, err := rdb.Get(ctx, key).Result(); err == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(val))
+		return
+	}
+
+	report, err := st.Orders.SalesReport(ctx, from, to, granularity, top)
+	if err != nil {
+		writeStoreError(w, err, "Failed to build sales report")
+		return
+	}
+
+	reportJSON, _ := json.Marshal(report)
+	rdb.Set(ctx, key, reportJSON, reportCacheTTL)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(reportJSON)
+}
+
+// dateRangeParams membaca ?from= dan ?to= (YYYY-MM-DD, inklusif) dan
+// mengembalikan rentang [from, to+1 hari). Default 30 hari terakhir.
+func dateRangeParams(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	from := to.AddDate(0, 0, -(defaultReportDays - 1))
+
+	var err error
+	if v := r.URL.Query().Get("to"); v != "" {
+		if to, err = time.ParseInLocation("2006-01-02", v, time.Local); err != nil {
+			http.Error(w, "Invalid to date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return from, to, false
+		}
+		if r.URL.Query().Get("from") == "" {
+			from = to.AddDate(0, 0, -(defaultReportDays - 1))
+		}
+	}
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = time.ParseInLocation("2006-01-02", v, time.Local); err != nil {
+			http.Error(w, "Invalid from date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return from, to, false
+		}
+	}
+	if to.Before(from) {
+		http.Error(w, "to must not be before from", http.StatusBadRequest)
+		return from, to, false
+	}
+	return from, to.AddDate(0, 0, 1), true
+}
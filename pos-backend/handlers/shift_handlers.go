@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"pos-backend/utils"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// OpenShift membuka shift kasir untuk user yang login dengan modal awal laci
+func OpenShift(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var open models.ShiftOpen
+	if err := json.NewDecoder(r.Body).Decode(&open); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	shift, err := st.Shifts.OpenShift(ctx, utils.SessionFromContext(r.Context()).Username, open)
+	if err != nil {
+		writeStoreError(w, err, "Failed to open shift")
+		return
+	}
+	auditChange(r, "shift", shift.ID, nil, shift)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(shift)
+}
+
+// GetCurrentShift menampilkan shift user yang login, 404 jika belum buka shift
+func GetCurrentShift(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	shift, err := st.Shifts.CurrentShift(ctx, utils.SessionFromContext(r.Context()).Username)
+	if err != nil {
+		writeStoreError(w, err, "Failed to load shift")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shift)
+}
+
+// AddCashMovement mencatat uang masuk/keluar laci pada shift user yang login
+func AddCashMovement(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var movement models.CashMovement
+	if err := json.NewDecoder(r.Body).Decode(&movement); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	movement.Username = utils.SessionFromContext(r.Context()).Username
+
+	if err := st.Shifts.AddCashMovement(ctx, &movement); err != nil {
+		writeStoreError(w, err, "Failed to record cash movement")
+		return
+	}
+	auditChange(r, "shift", movement.ShiftID, nil, movement)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(movement)
+}
+
+// CloseShift menutup shift dengan uang tunai yang dihitung dan membalas
+// Z-report. Tanpa shift_id yang ditutup adalah shift user yang login;
+// hanya admin yang boleh menutup shift kasir lain.
+func CloseShift(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.ShiftClose
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	session := utils.SessionFromContext(r.Context())
+	shift, ok := shiftForSession(ctx, st, w, session, req.ShiftID)
+	if !ok {
+		return
+	}
+
+	report, err := st.Shifts.CloseShift(ctx, shift.ID, req.CountedCash, req.Note, session.Username)
+	if err != nil {
+		writeStoreError(w, err, "Failed to close shift")
+		return
+	}
+	auditChange(r, "shift", shift.ID, shift, report)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetShiftReport menampilkan Z-report sebuah shift (?id=), tanpa id shift
+// user yang login. Kasir hanya bisa melihat shift miliknya.
+func GetShiftReport(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := 0
+	if v := r.URL.Query().Get("id"); v != "" {
+		var err error
+		if id, err = strconv.Atoi(v); err != nil {
+			http.Error(w, "Invalid shift ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	shift, ok := shiftForSession(ctx, st, w, utils.SessionFromContext(r.Context()), id)
+	if !ok {
+		return
+	}
+
+	report, err := st.Shifts.ShiftReport(ctx, shift.ID)
+	if err != nil {
+		writeStoreError(w, err, "Failed to build shift report")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetShifts menampilkan shift yang dibuka dalam rentang ?from= dan ?to=
+func GetShifts(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to, ok := dateRangeParams(w, r)
+	if !ok {
+		return
+	}
+
+	shifts, err := st.Shifts.ListShifts(ctx, from, to)
+	if err != nil {
+		writeStoreError(w, err, "Failed to load shifts")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shifts)
+}
+
+// shiftForSession mengambil shift id, atau shift yang sedang buka milik user
+// jika id 0, dan menolak akses kasir ke shift milik user lain
+func shiftForSession(ctx context.Context, st *store.Store, w http.ResponseWriter, session *utils.Session, id int) (*models.Shift, bool) {
+	var shift *models.Shift
+	var err error
+	if id == 0 {
+		shift, err = st.Shifts.CurrentShift(ctx, session.Username)
+	} else {
+		shift, err = st.Shifts.GetShift(ctx, id)
+	}
+	if err != nil {
+		writeStoreError(w, err, "Failed to load shift")
+		return nil, false
+	}
+	if shift.Username != session.Username && session.Role != models.RoleAdmin {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil, false
+	}
+	return shift, true
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"pos-backend/utils"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// SplitOrder memecah order per item atau rata ke beberapa order anak dan
+// membalas order asal beserta order anaknya
+func SplitOrder(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.OrderSplitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := st.Orders.GetOrder(ctx, req.OrderID)
+	split, err := st.Orders.SplitOrder(ctx, req, utils.SessionFromContext(r.Context()).Username)
+	if err != nil {
+		writeStoreError(w, err, "Failed to split order")
+		return
+	}
+	auditChange(r, "order", req.OrderID, before, split)
+	publishOrderEvent(ctx, st, rdb, models.OrderEventUpdated, split.Order.ID, nil)
+	for _, child := range split.Children {
+		publishOrderEvent(ctx, st, rdb, models.OrderEventCreated, child.ID, nil)
+	}
+	// Status dan total order berubah, laporan yang di-cache sudah basi
+	invalidateReports(ctx, rdb)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(split)
+}
+
+// MergeOrders memindahkan semua baris order sumber ke order tujuan dan
+// membalas order tujuan
+func MergeOrders(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.OrderMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Nilai audit mencakup kedua order karena keduanya berubah
+	source, _ := st.Orders.GetOrder(ctx, req.SourceID)
+	target, _ := st.Orders.GetOrder(ctx, req.TargetID)
+	order, err := st.Orders.MergeOrders(ctx, req.SourceID, req.TargetID, utils.SessionFromContext(r.Context()).Username, req.Reason)
+	if err != nil {
+		writeStoreError(w, err, "Failed to merge orders")
+		return
+	}
+	merged, _ := st.Orders.GetOrder(ctx, req.SourceID)
+	auditChange(r, "order", req.TargetID,
+		map[string]*models.Order{"source": source, "target": target},
+		map[string]*models.Order{"source": merged, "target": order})
+	publishOrderEvent(ctx, st, rdb, models.OrderEventUpdated, req.SourceID, nil)
+	publishOrderEvent(ctx, st, rdb, models.OrderEventUpdated, order.ID, nil)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"pos-backend/utils"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// AdjustStock mencatat restock, waste atau koreksi stok sebuah produk
+func AdjustStock(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var adj models.StockAdjustment
+	if err := json.NewDecoder(r.Body).Decode(&adj); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := st.Products.GetProduct(ctx, adj.ProductID)
+	movement, err := st.Products.AdjustStock(ctx, adj, utils.SessionFromContext(r.Context()).Username)
+	if err != nil {
+		writeStoreError(w, err, "Failed to adjust stock")
+		return
+	}
+	if after, err := st.Products.GetProduct(ctx, adj.ProductID); err == nil {
+		auditChange(r, "product", adj.ProductID, before, after)
+	}
+
+	// Stok ikut tersimpan di cache produk
+	invalidateProducts(ctx, rdb)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movement)
+}
+
+// GetLowStock menampilkan produk yang stoknya di bawah batas untuk dashboard admin
+func GetLowStock(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	products, err := st.Products.LowStockProducts(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(products)
+}
+
+// GetStockMovements menampilkan riwayat stok sebuah produk (?product_id=)
+func GetStockMovements(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.URL.Query().Get("product_id"))
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	movements, err := st.Products.StockMovements(ctx, productID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movements)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// GetTables menampilkan denah meja dengan status terisi/kosong dari order yang masih buka
+func GetTables(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tables, err := st.Tables.ListTables(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tables)
+}
+
+// CreateTable menambah meja baru ke denah
+func CreateTable(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var table models.Table
+	if err := json.NewDecoder(r.Body).Decode(&table); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := st.Tables.CreateTable(ctx, &table); err != nil {
+		writeStoreError(w, err, "Failed to create table")
+		return
+	}
+	auditChange(r, "table", table.ID, nil, table)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(table)
+}
+
+// UpdateTable mengganti nama, kursi, area dan urutan meja (/update-table/{id})
+func UpdateTable(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/update-table/"))
+	if err != nil {
+		http.Error(w, "Invalid table ID", http.StatusBadRequest)
+		return
+	}
+
+	var table models.Table
+	if err := json.NewDecoder(r.Body).Decode(&table); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	table.ID = id
+
+	before, _ := st.Tables.GetTable(ctx, table.ID)
+	if err := st.Tables.UpdateTable(ctx, &table); err != nil {
+		writeStoreError(w, err, "Failed to update table")
+		return
+	}
+	auditChange(r, "table", table.ID, before, table)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(table)
+}
+
+// DeleteTable menghapus meja (/delete-table/{id}) yang tidak punya order terbuka
+func DeleteTable(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/delete-table/"))
+	if err != nil {
+		http.Error(w, "Invalid table ID", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := st.Tables.GetTable(ctx, id)
+	if err := st.Tables.DeleteTable(ctx, id); err != nil {
+		writeStoreError(w, err, "Failed to delete table")
+		return
+	}
+	auditChange(r, "table", id, before, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
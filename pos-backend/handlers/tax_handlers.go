@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"pos-backend/models"
+	"pos-backend/store"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// GetTaxRules menampilkan semua aturan pajak dan service charge
+func GetTaxRules(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rules, err := st.Taxes.ListTaxRules(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+// SaveTaxRule creates a tax or service charge rule when id is 0 and updates
+// it otherwise. Existing orders keep the rates they were created with.
+func SaveTaxRule(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var rule models.TaxRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := st.Taxes.GetTaxRule(ctx, rule.ID)
+	if err := st.Taxes.SaveTaxRule(ctx, &rule); err != nil {
+		writeStoreError(w, err, "Failed to save tax rule")
+		return
+	}
+	auditChange(r, "tax_rule", rule.ID, before, rule)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// DeleteTaxRule menghapus aturan pajak (/delete-tax-rule/{id})
+func DeleteTaxRule(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/delete-tax-rule/"))
+	if err != nil {
+		http.Error(w, "Invalid tax rule ID", http.StatusBadRequest)
+		return
+	}
+
+	before, _ := st.Taxes.GetTaxRule(ctx, id)
+	if err := st.Taxes.DeleteTaxRule(ctx, id); err != nil {
+		writeStoreError(w, err, "Failed to delete tax rule")
+		return
+	}
+	auditChange(r, "tax_rule", id, before, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetTaxReport returns service charge and tax collected on completed orders
+// between from and to (inclusive dates, YYYY-MM-DD), per rule, together with
+// the part given back through refunds in the same period.
+func GetTaxReport(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to, ok := dateRangeParams(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := st.Taxes.TaxReport(ctx, from, to)
+	if err != nil {
+		writeStoreError(w, err, "Failed to build tax report")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -2,19 +2,20 @@ package handlers
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"pos-backend/models"
+	"pos-backend/store"
+	"pos-backend/utils"
+	"strconv"
 
 	"github.com/go-redis/redis/v8"
-	_ "github.com/godror/godror"
 	"golang.org/x/crypto/bcrypt"
 )
 
 
-func LoginHandler(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+func LoginHandler(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
 	var creds models.Credentials
 
 	// Decode request body
@@ -25,8 +26,8 @@ func LoginHandler(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.Res
 	}
 
 	// Ambil user dari database
-	user, err := getUserByUsername(creds.Username, db)
-	if err != nil || user == nil {
+	user, err := st.Users.GetUserByUsername(ctx, creds.Username)
+	if err != nil {
 		http.Error(w, "Username atau Password salah", http.StatusUnauthorized)
 		return
 	}
@@ -41,14 +42,81 @@ func LoginHandler(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.Res
 		return
 	}
 
-	// Jika login berhasil, kirim respons sukses dengan role
+	// Buat session baru di Redis dan terbitkan token
+	_, tokens, err := utils.CreateSession(ctx, rdb, user.Username, user.Role)
+	if err != nil {
+		http.Error(w, "Failed to create session: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Jika login berhasil, kirim respons sukses dengan role dan token
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Login successful",
-		"role":    user.Role,
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":       "Login successful",
+		"role":          user.Role,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_at":    tokens.ExpiresAt,
 	})
 }
 
+// RefreshTokenHandler menukar refresh token dengan access token baru
+func RefreshTokenHandler(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil || req.RefreshToken == "" {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return
+	}
+
+	session, tokens, err := utils.RefreshSession(ctx, rdb, req.RefreshToken)
+	if err == utils.ErrInvalidSession {
+		http.Error(w, "Invalid or expired refresh token", http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		http.Error(w, "Failed to refresh session: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"role":          session.Role,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_at":    tokens.ExpiresAt,
+	})
+}
+
+// LogoutHandler mencabut session yang sedang dipakai
+func LogoutHandler(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	session := utils.SessionFromContext(r.Context())
+	if session == nil {
+		http.Error(w, "Not logged in", http.StatusUnauthorized)
+		return
+	}
+
+	if err := utils.RevokeSession(ctx, rdb, session.ID); err != nil {
+		http.Error(w, "Failed to revoke session: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Logout successful"})
+}
+
 func HashPassword(password string) (string, error) {
     hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
     if err != nil {
@@ -61,7 +129,7 @@ func CheckPasswordHash(password, hash string) bool {
     err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
     return err == nil
 }
-func CreateAccount(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+func CreateAccount(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
 		return
@@ -80,6 +148,11 @@ func CreateAccount(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.Re
 		return
 	}
 
+	if !models.IsValidRole(user.Role) {
+		http.Error(w, "Unknown role: "+user.Role, http.StatusBadRequest)
+		return
+	}
+
 	// Hash password yang akan disimpan
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -91,29 +164,21 @@ func CreateAccount(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.Re
 	fmt.Println("Hash baru yang akan disimpan:", string(hashedPassword))
 
 	// Insert user ke database
-	query := "INSERT INTO SYSBACKUP.USERS (username, password, role) VALUES (:1, :2, :3)"
-	_, err = db.Exec(query, user.Username, hashedPassword, user.Role)
+	err = st.Users.CreateUser(ctx, &models.User{
+		Username: user.Username,
+		Password: string(hashedPassword),
+		Role:     user.Role,
+	})
 	if err != nil {
 		http.Error(w, "Failed to create account", http.StatusInternalServerError)
 		return
 	}
+	// Password tidak pernah masuk log audit
+	auditChange(r, "user", user.Username, nil, map[string]string{"username": user.Username, "role": user.Role})
 
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]string{"message": "Account created successfully"})
 }
-func getUserByUsername(username string,db *sql.DB ) (*models.User, error) {
-	var user models.User
-	query := "SELECT username, password, role FROM SYSBACKUP.USERS WHERE username = :1" // Sesuaikan dengan nama tabel Anda
-	err := db.QueryRow(query, username).Scan(&user.Username, &user.Password, &user.Role)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // User tidak ditemukan
-		}
-		return nil, err // Error lain
-	}
-	return &user, nil // Kembalikan user
-}
-
 // func hashPassword(password string) (string, error) {
 // 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 // 	if err != nil {
@@ -133,62 +198,54 @@ func getUserByUsername(username string,db *sql.DB ) (*models.User, error) {
 
 // buat dashboard
 // Function untuk showcase menu paling laris
-func TopSeller(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
-query := `
-	SELECT product_name, SUM(quantity) as total_sold
-	FROM SYSBACKUP.ORDER_DETAILS
-	GROUP BY product_name
-	ORDER BY total_sold DESC
-	FETCH FIRST 1 ROWS ONLY
-`
-rows, err := db.Query(query)
+func TopSeller(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+// Jumlah produk bisa diatur dengan ?limit=, default satu produk teratas
+limit := 1
+if v := r.URL.Query().Get("limit"); v != "" {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > maxReportTop {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+	limit = n
+}
+
+topSellingProducts, err := st.Orders.TopSellers(ctx, limit)
 if err != nil {
 	http.Error(w, err.Error(), http.StatusInternalServerError)
 	return
 }
-defer rows.Close()
-
-var topSellingProducts []models.TopSeller
-for rows.Next() {
-	var topProduct models.TopSeller
-	err := rows.Scan(&topProduct.ProductName, &topProduct.TotalSold)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	topSellingProducts = append(topSellingProducts, topProduct)
-}
 
 w.Header().Set("Content-Type", "application/json")
 json.NewEncoder(w).Encode(topSellingProducts)
 }
 
 // Function untuk mendapatkan total pendapatan
-func TotalRevenue(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
-var totalRevenue float64
-query := `
-	SELECT COALESCE(SUM(total_price), 0) 
-	FROM SYSBACKUP.ORDERS 
-	WHERE status = 'Order Completed'
-`
-
-err := db.QueryRow(query).Scan(&totalRevenue)
+func TotalRevenue(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+totalRevenue, err := st.Orders.TotalRevenue(ctx)
+if err != nil {
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+	return
+}
+
+// Rincian pendapatan per metode pembayaran
+byMethod, err := st.Orders.RevenueByMethod(ctx)
 if err != nil {
 	http.Error(w, err.Error(), http.StatusInternalServerError)
 	return
 }
 
 w.Header().Set("Content-Type", "application/json")
-json.NewEncoder(w).Encode(map[string]float64{"total_revenue": totalRevenue})
+json.NewEncoder(w).Encode(map[string]interface{}{
+	"total_revenue": totalRevenue,
+	"by_method":     byMethod,
+})
 }
 
 
 // Function untuk mendapatkan daftar produk
-func GetProductList(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
-var count int
-query := "SELECT COUNT(*) FROM SYSBACKUP.PRODUCTS"
-
-err := db.QueryRow(query).Scan(&count)
+func GetProductList(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+count, err := st.Products.CountProducts(ctx)
 if err != nil {
 	http.Error(w, err.Error(), http.StatusInternalServerError)
 	return
@@ -197,11 +254,8 @@ if err != nil {
 w.Header().Set("Content-Type", "application/json")
 json.NewEncoder(w).Encode(map[string]int{"product_count": count})
 }
-func CountOrderProgress(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
-var count int
-query := "SELECT COUNT(*) FROM SYSBACKUP.ORDERS WHERE status = 'On Progress'"
-
-err := db.QueryRow(query).Scan(&count)
+func CountOrderProgress(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+count, err := st.Orders.CountOrders(ctx, models.OrderStatusOnProgress)
 if err != nil {
 	http.Error(w, err.Error(), http.StatusInternalServerError)
 	return
@@ -210,11 +264,8 @@ if err != nil {
 w.Header().Set("Content-Type", "application/json")
 json.NewEncoder(w).Encode(map[string]int{"order_onprogress_count": count})
 }
-func CountAdmin(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
-var count int
-query := "SELECT COUNT(*) FROM SYSBACKUP.USERS WHERE role = 'admin'"
-
-err := db.QueryRow(query).Scan(&count)
+func CountAdmin(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+count, err := st.Users.CountUsers(ctx, models.RoleAdmin)
 if err != nil {
 	http.Error(w, err.Error(), http.StatusInternalServerError)
 	return
@@ -223,11 +274,8 @@ if err != nil {
 w.Header().Set("Content-Type", "application/json")
 json.NewEncoder(w).Encode(map[string]int{"admin_count": count})
 }
-func CountCashier(ctx context.Context, db *sql.DB, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
-var count int
-query := "SELECT COUNT(*) FROM SYSBACKUP.USERS WHERE role = 'kasir'"
-
-err := db.QueryRow(query).Scan(&count)
+func CountCashier(ctx context.Context, st *store.Store, rdb *redis.Client, w http.ResponseWriter, r *http.Request) {
+count, err := st.Users.CountUsers(ctx, models.RoleKasir)
 if err != nil {
 	http.Error(w, err.Error(), http.StatusInternalServerError)
 	return
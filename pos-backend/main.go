@@ -2,18 +2,22 @@ package main
 
 import (
 	"context"
-	"database/sql"
+	"crypto/rand"
 	"log"
 	"net/http"
+	"os"
+	"pos-backend/config"
+	"pos-backend/handlers"
+	"pos-backend/receipt"
 	"pos-backend/routes"
+	"pos-backend/store"
+	"pos-backend/utils"
 
 	"github.com/go-redis/redis/v8"
-	_ "github.com/godror/godror"
 )
 
-
 var (
-	db     *sql.DB
+	st     *store.Store
 	ctx    = context.Background()
 	rdb    *redis.Client
 )
@@ -23,57 +27,90 @@ var (
 			Role     string
 	}
 
-	func enableCORS(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Access-Control-Allow-Origin", "http://localhost:5173")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-			
-			// Handle preflight request
-			if r.Method == "OPTIONS" {
-				w.WriteHeader(http.StatusOK)
-				return
-			}
-	
-			// Call the next handler
-			next.ServeHTTP(w, r)
-		})
-	}
-	
+	// function utama
+	func main() {
+		cfg, err := config.Load()
+		if err != nil {
+			log.Fatalf("Error loading config: %v", err)
+		}
 
+		// Pilih database lewat database.driver (oracle, postgres, sqlite)
+		st, err = store.Open(cfg.Database.Driver, cfg.Database.DSN, cfg.Database.Schema)
+		if err != nil {
+			log.Fatalf("Error connecting to database: %v", err)
+		}
+		defer st.Close()
+		st.SetLoyalty(cfg.Loyalty.SpendPerPoint, cfg.Loyalty.PointValue)
 
+		// Subcommand "migrate" hanya mengelola skema lalu keluar
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			if err := runMigrateCommand(ctx, st, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
 
+		// Terapkan migration yang belum dijalankan, matikan dengan DB_AUTO_MIGRATE=false
+		if cfg.Database.AutoMigrate {
+			n, err := st.Migrate(ctx)
+			if err != nil {
+				log.Fatalf("Error running migrations: %v", err)
+			}
+			log.Printf("Applied %d migration(s)", n)
+		}
 
-	// function utama
-	func main() {
-		var err error
-		db, err = sql.Open("godror", "system/123456789@//localhost:1521/orc1") // Sesuaikan koneksi database
+		rdb, err = utils.NewRedisClient(ctx, cfg.Redis)
 		if err != nil {
-			log.Fatalf("Error connecting to database: %v", err)
+			log.Fatalf("Error connecting to Redis: %v", err)
 		}
-		defer db.Close()
-		rdb = redis.NewClient(&redis.Options{
-			Addr: "localhost:6379", // Ganti dengan alamat Redis Anda
-		})
+		defer rdb.Close()
 		// printHashedPassword("kasirpassword") // Ganti dengan password yang ingin diuji
 
+		// Kunci untuk menandatangani token session
+		secret := cfg.Session.Secret
+		if secret == "" {
+			log.Println("session.secret tidak diset, memakai kunci acak (token hilang saat restart)")
+			random := make([]byte, 32)
+			if _, err := rand.Read(random); err != nil {
+				log.Fatalf("Error generating session secret: %v", err)
+			}
+			secret = string(random)
+		}
+		utils.SetSessionSecret([]byte(secret))
+		handlers.UploadDir = cfg.UploadDir
+		handlers.RefundApprovalThreshold = cfg.Refund.ApprovalThreshold
+		handlers.Receipts, err = receipt.New(cfg.Receipt)
+		if err != nil {
+			log.Fatalf("Error loading receipt template: %v", err)
+		}
+
 		// endpoint produk
 		
 
-        routes.RegisterProductRoutes(ctx, db, rdb)
+        routes.RegisterProductRoutes(ctx, st, rdb)
 		// endpoint order
-		routes.RegisterOrderRoutes(ctx, db, rdb)
+		routes.RegisterOrderRoutes(ctx, st, rdb)
 
 
 		// endpoint user
-		routes.RegisterUserRoutes(ctx, db, rdb)
+		routes.RegisterUserRoutes(ctx, st, rdb)
 
 		
 	
-		// Use enableCORS for CORS handling
-		log.Println("Server is running on port 8080...")
-		if err := http.ListenAndServe(":8080", enableCORS(http.DefaultServeMux)); err != nil {
+		// Use EnableCORS for CORS handling
+		handler := utils.Authorize(routes.PublicPaths, routes.Permissions, http.DefaultServeMux)
+		// Audit dipasang di luar Authorize supaya request yang ditolak 403 ikut tercatat
+		handler = utils.Audit(routes.PublicPaths, handlers.AuditRecorder(st, cfg.Server.TrustProxy), handler)
+		handler = utils.RequireAuth(rdb, routes.PublicPaths, routes.StreamPaths, handler)
+		handler = utils.EnableCORS(cfg.Server.CORSOrigins, handler)
+
+		log.Printf("Server is running on %s...", cfg.Server.Addr)
+		if cfg.Server.TLSEnabled() {
+			err = http.ListenAndServeTLS(cfg.Server.Addr, cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile, handler)
+		} else {
+			err = http.ListenAndServe(cfg.Server.Addr, handler)
+		}
+		if err != nil {
 			log.Fatal("Error starting server:", err)
 		}
 	}
-	
\ No newline at end of file
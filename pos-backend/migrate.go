@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"pos-backend/store"
+)
+
+const migrateUsage = `usage: pos-backend migrate <command>
+
+commands:
+  up          jalankan semua migration yang belum diterapkan
+  down [n]    batalkan n migration terakhir (default 1)
+  status      tampilkan versi yang sudah dan belum diterapkan`
+
+// runMigrateCommand menangani subcommand "migrate"
+func runMigrateCommand(ctx context.Context, st *store.Store, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		n, err := st.Migrate(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s)\n", n)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		n, err := st.Rollback(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rolled back %d migration(s)\n", n)
+	case "status":
+		statuses, err := st.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(tw, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		tw.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Jenis perubahan baris pada order yang masih berjalan
+const (
+	AmendAdd      = "add"
+	AmendQuantity = "quantity"
+	AmendVoid     = "void"
+	AmendSplit    = "split"
+	AmendMerge    = "merge"
+)
+
+// OrderAmendment adalah satu baris riwayat di ORDER_AMENDMENTS. OldTotal dan
+// NewTotal adalah total order sebelum dan sesudah perubahan.
+type OrderAmendment struct {
+	ID            int       `json:"id"`
+	OrderID       int       `json:"order_id"`
+	OrderDetailID int       `json:"order_detail_id,omitempty"`
+	Action        string    `json:"action"`
+	ProductName   string    `json:"product_name"`
+	OldQuantity   int       `json:"old_quantity"`
+	NewQuantity   int       `json:"new_quantity"`
+	OldTotal      float64   `json:"old_total"`
+	NewTotal      float64   `json:"new_total"`
+	Username      string    `json:"username,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// OrderAmendRequest adalah body untuk mengubah baris order. OrderID dan Item
+// dipakai untuk menambah baris; DetailID dan Quantity untuk mengubah jumlah
+// atau void.
+type OrderAmendRequest struct {
+	OrderID  int        `json:"order_id"`
+	DetailID int        `json:"detail_id"`
+	Item     *OrderItem `json:"item,omitempty"`
+	Quantity int        `json:"quantity"`
+	Reason   string     `json:"reason"`
+}
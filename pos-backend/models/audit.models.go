@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry adalah satu baris AUDIT_LOG. Hash dihitung dari PrevHash dan
+// isi baris, sehingga mengubah atau menghapus baris lama memutus rantai.
+type AuditEntry struct {
+	ID       int    `json:"id"`
+	Actor    string `json:"actor"`
+	Role     string `json:"role,omitempty"`
+	Action   string `json:"action"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Entity   string `json:"entity,omitempty"`
+	EntityID string `json:"entity_id,omitempty"`
+	// Before dan After adalah JSON entitas sebelum dan sesudah perubahan
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	Status    int             `json:"status"`
+	IP        string          `json:"ip,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash"`
+}
+
+// AuditFilter menyaring log audit; field kosong tidak dipakai. From
+// inklusif dan To eksklusif.
+type AuditFilter struct {
+	Actor    string
+	Action   string
+	Entity   string
+	EntityID string
+	From     time.Time
+	To       time.Time
+	Limit    int
+}
+
+// AuditVerification adalah hasil pemeriksaan rantai hash AUDIT_LOG.
+// BrokenAt adalah id baris pertama yang hash-nya tidak cocok dan LastHash
+// adalah hash baris valid terakhir.
+type AuditVerification struct {
+	Checked  int    `json:"checked"`
+	Valid    bool   `json:"valid"`
+	BrokenAt int    `json:"broken_at,omitempty"`
+	LastHash string `json:"last_hash,omitempty"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// Category mengelompokkan produk di layar kasir, diurutkan berdasarkan SortOrder
+type Category struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	SortOrder    int    `json:"sort_order"`
+	ProductCount int    `json:"product_count"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Alasan pergerakan poin loyalty
+const (
+	PointsEarn   = "earn"
+	PointsRedeem = "redeem"
+	// PointsReturn mengembalikan poin yang ditukar saat order batal atau direfund
+	PointsReturn = "return"
+	// PointsRevoke menarik poin yang didapat dari order yang direfund
+	PointsRevoke = "revoke"
+	PointsAdjust = "adjust"
+)
+
+// Customer adalah pelanggan terdaftar. Phone dipakai kasir untuk mencari
+// pelanggan dan harus unik; Points adalah saldo poin saat ini.
+type Customer struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Phone     string    `json:"phone,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	Points    int       `json:"points"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PointMovement mencatat setiap perubahan poin, positif berarti poin bertambah
+type PointMovement struct {
+	ID         int       `json:"id"`
+	CustomerID int       `json:"customer_id"`
+	Points     int       `json:"points"`
+	Reason     string    `json:"reason"`
+	OrderID    *int      `json:"order_id,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	Username   string    `json:"username,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// PointAdjustment adalah koreksi poin manual dari admin
+type PointAdjustment struct {
+	CustomerID int    `json:"customer_id"`
+	Points     int    `json:"points"`
+	Note       string `json:"note"`
+}
+
+// CustomerHistory adalah data pelanggan beserta order dan riwayat poinnya,
+// terbaru lebih dulu
+type CustomerHistory struct {
+	Customer   Customer        `json:"customer"`
+	OrderCount int             `json:"order_count"`
+	TotalSpent float64         `json:"total_spent"`
+	Orders     []Order         `json:"orders"`
+	Points     []PointMovement `json:"points"`
+}
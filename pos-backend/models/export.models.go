@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// ExportFilter membatasi data yang diekspor. To bersifat eksklusif.
+type ExportFilter struct {
+	From     time.Time
+	To       time.Time
+	Statuses []string
+}
+
+// OrderExportRow adalah satu order pada ekspor order
+type OrderExportRow struct {
+	ID            int
+	CreatedAt     time.Time
+	Status        string
+	Type          string
+	Table         string
+	Subtotal      float64
+	Discount      float64
+	ServiceCharge float64
+	Tax           float64
+	TotalPrice    float64
+	AmountPaid    float64
+	Refunded      float64
+}
+
+// OrderLineExportRow adalah satu baris ORDER_DETAILS beserta tanggal dan status ordernya
+type OrderLineExportRow struct {
+	OrderID   int
+	CreatedAt time.Time
+	Status    string
+	Detail    OrderDetail
+	Refunded  int
+}
+
+// DefaultExportStatuses sama dengan data di GetCompletedOrders
+var DefaultExportStatuses = []string{OrderStatusCompleted, OrderStatusRefunded}
@@ -0,0 +1,36 @@
+package models
+
+// Hasil import satu baris produk
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportFailed  = "failed"
+)
+
+// ProductImportRow adalah satu baris file import. Line adalah nomor baris
+// di file CSV (header di baris 1).
+type ProductImportRow struct {
+	Line    int
+	Product Product
+}
+
+// ProductImportResult adalah hasil satu baris import
+type ProductImportResult struct {
+	Line      int    `json:"line"`
+	Name      string `json:"name,omitempty"`
+	SKU       string `json:"sku,omitempty"`
+	Action    string `json:"action"`
+	ProductID int    `json:"product_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ProductImportReport adalah laporan import yang dikirim ke client. Pada
+// dry run tidak ada perubahan yang disimpan.
+type ProductImportReport struct {
+	DryRun  bool                  `json:"dry_run"`
+	Total   int                   `json:"total"`
+	Created int                   `json:"created"`
+	Updated int                   `json:"updated"`
+	Failed  int                   `json:"failed"`
+	Rows    []ProductImportResult `json:"rows"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Jenis event order yang dikirim ke layar dapur. Event order.deleted tidak
+// membawa Order karena order sudah dihapus.
+const (
+	OrderEventCreated      = "order.created"
+	OrderEventUpdated      = "order.updated"
+	OrderEventCanceled     = "order.canceled"
+	OrderEventDeleted      = "order.deleted"
+	OrderEventItemPrepared = "item.prepared"
+)
+
+// KitchenOrderStatuses adalah status order yang tampil di layar dapur
+var KitchenOrderStatuses = []string{OrderStatusOnProgress, OrderStatusReady}
+
+// OrderEvent disebarkan lewat Redis pub/sub ke semua instance backend
+type OrderEvent struct {
+	Type    string       `json:"type"`
+	OrderID int          `json:"order_id"`
+	Status  string       `json:"status"`
+	Order   *Order       `json:"order,omitempty"`
+	Detail  *OrderDetail `json:"detail,omitempty"`
+	At      time.Time    `json:"at"`
+}
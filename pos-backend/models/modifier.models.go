@@ -0,0 +1,43 @@
+package models
+
+// ModifierGroup adalah kumpulan pilihan untuk satu produk, misalnya ukuran
+// (wajib, pilih satu) atau topping (opsional, maksimal tiga). MaxSelect 0
+// berarti tidak dibatasi.
+type ModifierGroup struct {
+	ID        int              `json:"id"`
+	ProductID int              `json:"product_id"`
+	Name      string           `json:"name"`
+	Required  bool             `json:"required"`
+	MinSelect int              `json:"min_select"`
+	MaxSelect int              `json:"max_select"`
+	SortOrder int              `json:"sort_order"`
+	Options   []ModifierOption `json:"options"`
+}
+
+// MinRequired adalah jumlah pilihan minimal, Required berarti paling sedikit satu
+func (g ModifierGroup) MinRequired() int {
+	if g.Required && g.MinSelect < 1 {
+		return 1
+	}
+	return g.MinSelect
+}
+
+// ModifierOption adalah satu pilihan dengan selisih harga terhadap harga produk
+type ModifierOption struct {
+	ID         int     `json:"id"`
+	GroupID    int     `json:"group_id"`
+	Name       string  `json:"name"`
+	PriceDelta float64 `json:"price_delta"`
+	SortOrder  int     `json:"sort_order"`
+}
+
+// OrderDetailModifier adalah snapshot pilihan modifier pada baris order,
+// tetap utuh walaupun grup atau pilihannya diubah kemudian
+type OrderDetailModifier struct {
+	ID            int     `json:"id"`
+	OrderDetailID int     `json:"order_detail_id"`
+	OptionID      int     `json:"option_id"`
+	GroupName     string  `json:"group_name"`
+	OptionName    string  `json:"option_name"`
+	PriceDelta    float64 `json:"price_delta"`
+}
@@ -1,9 +1,33 @@
 package models
 
+import "time"
+
 type OrderDetail struct {
-	ID          int     `json:"id"`
-	OrderID     int     `json:"order_id"`
-	ProductName string  `json:"product_name"`
-	Quantity    int     `json:"quantity"`
-	TotalPrice  float64 `json:"total_price"`
+	ID          int        `json:"id"`
+	OrderID     int        `json:"order_id"`
+	ProductID   int        `json:"product_id"`
+	ProductName string     `json:"product_name"`
+	UnitPrice   float64    `json:"unit_price"`
+	Quantity    int        `json:"quantity"`
+	TotalPrice  float64    `json:"total_price"`
+	PreparedAt  *time.Time `json:"prepared_at,omitempty"`
+	PreparedBy  string     `json:"prepared_by,omitempty"`
+	// Discount adalah diskon promo baris ditambah bagian diskon order
+	Discount float64 `json:"discount,omitempty"`
+	// ServiceCharge dan Tax ditambahkan ke baris, TaxIncluded sudah termasuk harga
+	ServiceCharge float64 `json:"service_charge,omitempty"`
+	Tax           float64 `json:"tax,omitempty"`
+	TaxIncluded   float64 `json:"tax_included,omitempty"`
+	// UnitPrice sudah termasuk selisih harga Modifiers
+	Modifiers []OrderDetailModifier `json:"modifiers,omitempty"`
+}
+
+// NetTotal adalah nilai baris setelah diskon
+func (d OrderDetail) NetTotal() float64 {
+	return d.TotalPrice - d.Discount
+}
+
+// ChargedTotal adalah nilai yang dibayar pelanggan untuk baris ini
+func (d OrderDetail) ChargedTotal() float64 {
+	return d.TotalPrice - d.Discount + d.ServiceCharge + d.Tax
 }
@@ -8,12 +8,46 @@ type Order struct {
     Status    string       `json:"status"`
     CreatedAt time.Time    `json:"created_at"`
     TotalPrice *float64    `json:"total_price"`
+    // Subtotal adalah jumlah harga baris sebelum diskon,
+    // TotalPrice = Subtotal - Discount + ServiceCharge + Tax
+    Subtotal   float64       `json:"subtotal"`
+    Discount   float64       `json:"discount"`
+    ServiceCharge float64    `json:"service_charge"`
+    Tax        float64       `json:"tax"`
+    // TaxIncluded adalah pajak yang sudah termasuk harga produk
+    TaxIncluded float64      `json:"tax_included,omitempty"`
+    Taxes      []AppliedTax  `json:"taxes,omitempty"`
+    // CustomerID opsional; RedeemPoints adalah poin yang ditukar sebagai diskon
+    CustomerID   *int        `json:"customer_id,omitempty"`
+    RedeemPoints int         `json:"redeem_points,omitempty"`
+    PointsEarned int         `json:"points_earned,omitempty"`
+    // Type adalah dine_in (wajib meja), takeaway atau delivery (wajib alamat).
+    // Meja bisa dipilih lewat TableID atau TableName.
+    Type       string        `json:"type"`
+    TableID    *int          `json:"table_id,omitempty"`
+    TableName  string        `json:"table_name,omitempty"`
+    DeliveryAddress string   `json:"delivery_address,omitempty"`
+    // ShiftID adalah shift kasir yang membuat order, diisi server
+    ShiftID    *int          `json:"shift_id,omitempty"`
+    // ParentOrderID diisi untuk order hasil split bill
+    ParentOrderID *int       `json:"parent_order_id,omitempty"`
+    // PromoCode dikirim client saat membuat order
+    PromoCode  string        `json:"promo_code,omitempty"`
+    Promotions []AppliedPromotion `json:"promotions,omitempty"`
 	Items      []OrderItem   `json:"items"` // List of ordered items
     Details    []OrderDetail `json:"details"` 
+    Payments   []Payment     `json:"payments,omitempty"`
+    Refunds    []Refund      `json:"refunds,omitempty"`
 }
 
+// OrderItem adalah satu baris pesanan dari client. Harga selalu dihitung
+// ulang di server dari PRODUCTS.price ditambah selisih harga modifier;
+// TotalPrice hanya dipakai untuk mendeteksi total yang tidak cocok.
 type OrderItem struct {
+    ProductID   int     `json:"product_id"`
     ProductName string  `json:"product_name"`
 	Quantity    int     `json:"quantity"`
     TotalPrice  float64 `json:"total_price"`
+    // ModifierOptionIDs adalah pilihan modifier untuk baris ini
+    ModifierOptionIDs []int `json:"modifier_option_ids,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Status order yang disimpan di kolom ORDERS.status
+const (
+	OrderStatusDraft      = "Draft"
+	OrderStatusOnProgress = "On Progress"
+	OrderStatusReady      = "Ready"
+	OrderStatusCompleted  = "Order Completed"
+	OrderStatusCanceled   = "Order Canceled"
+	OrderStatusRefunded   = "Order Refunded"
+	// OrderStatusSplit adalah order induk yang tagihannya dibagi rata ke
+	// order anak; OrderStatusMerged adalah order yang digabung ke order lain
+	OrderStatusSplit  = "Order Split"
+	OrderStatusMerged = "Order Merged"
+)
+
+// orderTransitions adalah satu-satunya sumber aturan perpindahan status order
+var orderTransitions = map[string][]string{
+	OrderStatusDraft:      {OrderStatusOnProgress, OrderStatusCanceled},
+	OrderStatusOnProgress: {OrderStatusReady, OrderStatusCompleted, OrderStatusCanceled, OrderStatusSplit, OrderStatusMerged},
+	OrderStatusReady:      {OrderStatusCompleted, OrderStatusCanceled, OrderStatusSplit, OrderStatusMerged},
+	OrderStatusCompleted:  {OrderStatusRefunded},
+}
+
+// OpenOrderStatuses adalah status order yang belum selesai dibayar
+var OpenOrderStatuses = []string{OrderStatusDraft, OrderStatusOnProgress, OrderStatusReady}
+
+// CanTransition mengecek apakah order boleh pindah dari status from ke to
+func CanTransition(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpenOrderStatus mengecek apakah status termasuk OpenOrderStatuses
+func IsOpenOrderStatus(status string) bool {
+	for _, s := range OpenOrderStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderStatusChange adalah satu baris riwayat di ORDER_STATUS_HISTORY
+type OrderStatusChange struct {
+	ID         int       `json:"id"`
+	OrderID    int       `json:"order_id"`
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	Username   string    `json:"username,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Metode pembayaran yang diterima kasir
+const (
+	PaymentCash      = "cash"
+	PaymentDebitCard = "debit_card"
+	PaymentQRIS      = "qris"
+	PaymentEWallet   = "ewallet"
+	PaymentPoints    = "points" // poin loyalty pelanggan order
+)
+
+var PaymentMethods = []string{PaymentCash, PaymentDebitCard, PaymentQRIS, PaymentEWallet, PaymentPoints}
+
+// IsValidPaymentMethod mengecek apakah metode terdaftar di PaymentMethods
+func IsValidPaymentMethod(method string) bool {
+	for _, m := range PaymentMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Payment adalah satu tender untuk sebuah order. Dari client cukup Method,
+// Amount (uang yang diserahkan) dan Reference; server mengisi sisanya.
+// Setelah disimpan, Amount adalah bagian yang dipakai untuk membayar order,
+// Tendered uang yang diserahkan dan Change kembalian (hanya untuk tunai).
+type Payment struct {
+	ID        int       `json:"id"`
+	OrderID   int       `json:"order_id"`
+	Method    string    `json:"method"`
+	Amount    float64   `json:"amount"`
+	Tendered  float64   `json:"tendered"`
+	Change    float64   `json:"change_due"`
+	Reference string    `json:"reference,omitempty"`
+	Username  string    `json:"username,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PaymentSummary merangkum pembayaran sebuah order
+type PaymentSummary struct {
+	OrderID    int       `json:"order_id"`
+	TotalPrice float64   `json:"total_price"`
+	AmountPaid float64   `json:"amount_paid"`
+	AmountDue  float64   `json:"amount_due"`
+	ChangeDue  float64   `json:"change_due"`
+	Payments   []Payment `json:"payments"`
+}
+
+// PaymentMethodTotal adalah pendapatan bersih per metode pembayaran,
+// Total sudah dikurangi Refunded
+type PaymentMethodTotal struct {
+	Method   string  `json:"method"`
+	Count    int     `json:"count"`
+	Total    float64 `json:"total"`
+	Refunded float64 `json:"refunded"`
+}
@@ -5,4 +5,14 @@ type Product struct {
 	Name     string  `json:"name"`
 	Price    float64 `json:"price"`
 	ImageURL string  `json:"image_url"`
-}
\ No newline at end of file
+	SKU      string  `json:"sku,omitempty"`
+	// CategoryID nil berarti produk belum masuk kategori. Category berisi
+	// nama kategori; saat menyimpan, nama yang belum ada dibuat otomatis.
+	CategoryID *int   `json:"category_id"`
+	Category   string `json:"category,omitempty"`
+	// Stock nil berarti stok produk ini tidak dilacak
+	Stock             *int `json:"stock"`
+	LowStockThreshold int  `json:"low_stock_threshold"`
+	// ModifierGroups diisi oleh ListProducts dan GetProduct untuk menu kasir
+	ModifierGroups []ModifierGroup `json:"modifier_groups,omitempty"`
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Jenis promo
+const (
+	PromoPercent  = "percent"
+	PromoFixed    = "fixed"
+	PromoBuyXGetY = "buy_x_get_y"
+)
+
+// Cakupan promo: per baris order atau total order
+const (
+	PromoScopeLine  = "line"
+	PromoScopeOrder = "order"
+)
+
+// Promotion adalah satu aturan diskon.
+//   - percent: Value persen dari harga baris atau subtotal order
+//   - fixed: Value rupiah per unit (line) atau per order (order)
+//   - buy_x_get_y: setiap BuyQuantity+GetQuantity unit, GetQuantity unit
+//     termurah mendapat Value persen diskon (0 berarti gratis)
+//
+// ProductID atau CategoryID membatasi produk yang ikut; keduanya kosong
+// berarti semua produk. Promo dengan Code hanya berlaku jika kode dimasukkan.
+// Days (1 = Senin ... 7 = Minggu) dan StartTime/EndTime (HH:MM) membentuk
+// jam happy hour; jam yang melewati tengah malam diperbolehkan.
+type Promotion struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`
+	Scope       string     `json:"scope"`
+	Value       float64    `json:"value"`
+	ProductID   *int       `json:"product_id,omitempty"`
+	CategoryID  *int       `json:"category_id,omitempty"`
+	BuyQuantity int        `json:"buy_quantity,omitempty"`
+	GetQuantity int        `json:"get_quantity,omitempty"`
+	MinSubtotal float64    `json:"min_subtotal,omitempty"`
+	Code        string     `json:"code,omitempty"`
+	UsageLimit  int        `json:"usage_limit"`
+	UsageCount  int        `json:"usage_count"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	Days        []int      `json:"days,omitempty"`
+	StartTime   string     `json:"start_time,omitempty"`
+	EndTime     string     `json:"end_time,omitempty"`
+	Active      bool       `json:"active"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ActiveAt mengecek masa berlaku, hari dan jam happy hour pada waktu t
+func (p Promotion) ActiveAt(t time.Time) bool {
+	if !p.Active || (p.StartsAt != nil && t.Before(*p.StartsAt)) || (p.EndsAt != nil && !t.Before(*p.EndsAt)) {
+		return false
+	}
+
+	if len(p.Days) > 0 {
+		weekday := (int(t.Weekday())+6)%7 + 1
+		found := false
+		for _, d := range p.Days {
+			found = found || d == weekday
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if p.StartTime == "" || p.EndTime == "" {
+		return true
+	}
+	now := t.Format("15:04")
+	if p.StartTime <= p.EndTime {
+		return now >= p.StartTime && now < p.EndTime
+	}
+	return now >= p.StartTime || now < p.EndTime
+}
+
+// FormatDays dan ParseDays menyimpan Days sebagai teks "1,2,3"
+func FormatDays(days []int) string {
+	parts := make([]string, len(days))
+	for i, d := range days {
+		parts[i] = strconv.Itoa(d)
+	}
+	return strings.Join(parts, ",")
+}
+
+func ParseDays(s string) []int {
+	var days []int
+	for _, part := range strings.Split(s, ",") {
+		if d, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// AppliedPromotion adalah promo yang dipakai sebuah order. OrderDetailID
+// kosong berarti diskon order yang dibagi ke semua baris.
+type AppliedPromotion struct {
+	ID            int     `json:"id"`
+	OrderID       int     `json:"order_id"`
+	PromotionID   int     `json:"promotion_id"`
+	OrderDetailID *int    `json:"order_detail_id,omitempty"`
+	Name          string  `json:"name"`
+	Code          string  `json:"code,omitempty"`
+	Amount        float64 `json:"amount"`
+}
+
+// PromotionUsage adalah ringkasan pemakaian satu promo pada laporan
+type PromotionUsage struct {
+	PromotionID int     `json:"promotion_id"`
+	Name        string  `json:"name"`
+	Code        string  `json:"code,omitempty"`
+	OrderCount  int     `json:"order_count"`
+	Discount    float64 `json:"discount"`
+}
+
+// PromotionReport adalah total diskon per promo antara From dan To
+type PromotionReport struct {
+	From          time.Time        `json:"from"`
+	To            time.Time        `json:"to"`
+	GrossRevenue  float64          `json:"gross_revenue"`
+	TotalDiscount float64          `json:"total_discount"`
+	Promotions    []PromotionUsage `json:"promotions"`
+}
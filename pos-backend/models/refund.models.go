@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Kode alasan refund
+const (
+	RefundReasonCustomerRequest = "customer_request"
+	RefundReasonWrongItem       = "wrong_item"
+	RefundReasonQualityIssue    = "quality_issue"
+	RefundReasonOvercharge      = "overcharge"
+	RefundReasonOther           = "other"
+)
+
+var RefundReasons = []string{
+	RefundReasonCustomerRequest,
+	RefundReasonWrongItem,
+	RefundReasonQualityIssue,
+	RefundReasonOvercharge,
+	RefundReasonOther,
+}
+
+// IsValidRefundReason mengecek apakah alasan terdaftar di RefundReasons
+func IsValidRefundReason(reason string) bool {
+	for _, r := range RefundReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// RefundItem adalah jumlah yang dikembalikan dari satu baris ORDER_DETAILS
+type RefundItem struct {
+	ID            int     `json:"id"`
+	RefundID      int     `json:"refund_id"`
+	OrderDetailID int     `json:"order_detail_id"`
+	ProductID     int     `json:"product_id"`
+	ProductName   string  `json:"product_name"`
+	Quantity      int     `json:"quantity"`
+	Amount        float64 `json:"amount"`
+}
+
+// Refund mengembalikan uang untuk order yang sudah selesai. Items kosong
+// berarti semua sisa baris order dikembalikan. Amount dihitung di server
+// dari harga baris order; Method default ke tunai.
+type Refund struct {
+	ID         int          `json:"id"`
+	OrderID    int          `json:"order_id"`
+	Method     string       `json:"method"`
+	Amount     float64      `json:"amount"`
+	Reason     string       `json:"reason"`
+	Note       string       `json:"note,omitempty"`
+	Restock    bool         `json:"restock"`
+	Items      []RefundItem `json:"items"`
+	Username   string       `json:"username,omitempty"`
+	ApprovedBy string       `json:"approved_by,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
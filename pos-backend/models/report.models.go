@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Granularity laporan penjualan
+const (
+	ReportDaily   = "day"
+	ReportWeekly  = "week"
+	ReportMonthly = "month"
+)
+
+// IsValidGranularity mengecek apakah granularity dikenal
+func IsValidGranularity(g string) bool {
+	return g == ReportDaily || g == ReportWeekly || g == ReportMonthly
+}
+
+// SalesBucket adalah ringkasan penjualan satu periode. Start adalah tanggal
+// awal periode (Senin untuk mingguan, tanggal 1 untuk bulanan).
+// GrossRevenue adalah nilai sebelum diskon dan pajak; Revenue adalah uang
+// yang diterima: GrossRevenue - Discounts + ServiceCharge + Tax dikurangi
+// Refunds yang terjadi di periode yang sama.
+type SalesBucket struct {
+	Start         string      `json:"start"`
+	GrossRevenue  float64     `json:"gross_revenue"`
+	Discounts     float64     `json:"discounts"`
+	ServiceCharge float64     `json:"service_charge"`
+	Tax           float64     `json:"tax"`
+	Refunds       float64     `json:"refunds"`
+	Revenue       float64     `json:"revenue"`
+	OrderCount    int         `json:"order_count"`
+	AverageTicket float64     `json:"average_ticket"`
+	ItemsSold     int         `json:"items_sold"`
+	TopProducts   []TopSeller `json:"top_products"`
+}
+
+// SalesReport adalah laporan penjualan order selesai antara From (inklusif)
+// dan To (eksklusif)
+type SalesReport struct {
+	From        time.Time     `json:"from"`
+	To          time.Time     `json:"to"`
+	Granularity string        `json:"granularity"`
+	Buckets     []SalesBucket `json:"buckets"`
+	Total       SalesBucket   `json:"total"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Jenis uang masuk/keluar laci di luar penjualan
+const (
+	CashIn  = "in"
+	CashOut = "out"
+)
+
+// Shift adalah satu sesi kasir dari buka sampai tutup laci. ExpectedCash
+// dan CountedCash baru terisi saat shift ditutup.
+type Shift struct {
+	ID           int        `json:"id"`
+	Username     string     `json:"username"`
+	OpeningFloat float64    `json:"opening_float"`
+	OpenedAt     time.Time  `json:"opened_at"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`
+	ClosedBy     string     `json:"closed_by,omitempty"`
+	ExpectedCash *float64   `json:"expected_cash,omitempty"`
+	CountedCash  *float64   `json:"counted_cash,omitempty"`
+	Note         string     `json:"note,omitempty"`
+}
+
+// ShiftOpen adalah body untuk membuka shift
+type ShiftOpen struct {
+	OpeningFloat float64 `json:"opening_float"`
+	Note         string  `json:"note"`
+}
+
+// ShiftClose adalah body untuk menutup shift. ShiftID 0 berarti shift
+// milik user yang sedang login.
+type ShiftClose struct {
+	ShiftID     int     `json:"shift_id"`
+	CountedCash float64 `json:"counted_cash"`
+	Note        string  `json:"note"`
+}
+
+// CashMovement adalah uang masuk (modal tambahan) atau keluar (setor,
+// belanja kecil) dari laci selama shift
+type CashMovement struct {
+	ID        int       `json:"id"`
+	ShiftID   int       `json:"shift_id"`
+	Type      string    `json:"type"`
+	Amount    float64   `json:"amount"`
+	Note      string    `json:"note,omitempty"`
+	Username  string    `json:"username,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ZReport merangkum satu shift: order yang dibuat, tender yang diterima,
+// refund yang dibayarkan dan rekonsiliasi uang tunai. Untuk shift yang
+// masih buka CountedCash dan Discrepancy kosong.
+type ZReport struct {
+	Shift          Shift                `json:"shift"`
+	OrderCount     int                  `json:"order_count"`
+	CompletedCount int                  `json:"completed_count"`
+	CanceledCount  int                  `json:"canceled_count"`
+	OpenCount      int                  `json:"open_count"`
+	Sales          float64              `json:"sales"`
+	Discounts      float64              `json:"discounts"`
+	ServiceCharge  float64              `json:"service_charge"`
+	Tax            float64              `json:"tax"`
+	Tenders        []PaymentMethodTotal `json:"tenders"`
+	RefundCount    int                  `json:"refund_count"`
+	Refunded       float64              `json:"refunded"`
+	CashSales      float64              `json:"cash_sales"`
+	CashRefunds    float64              `json:"cash_refunds"`
+	CashIn         float64              `json:"cash_in"`
+	CashOut        float64              `json:"cash_out"`
+	CashMovements  []CashMovement       `json:"cash_movements"`
+	ExpectedCash   float64              `json:"expected_cash"`
+	CountedCash    *float64             `json:"counted_cash,omitempty"`
+	Discrepancy    *float64             `json:"discrepancy,omitempty"`
+}
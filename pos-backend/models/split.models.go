@@ -0,0 +1,38 @@
+package models
+
+// Batas jumlah bagian saat tagihan dibagi rata
+const (
+	MinSplitParts = 2
+	MaxSplitParts = 20
+)
+
+// SplitItem memindahkan Quantity dari satu baris order ke order anak.
+// Quantity 0 berarti seluruh baris.
+type SplitItem struct {
+	DetailID int `json:"detail_id"`
+	Quantity int `json:"quantity"`
+}
+
+// OrderSplitRequest adalah body untuk split bill. Isi Items untuk
+// memindahkan baris ke satu order anak, atau Parts untuk membagi tagihan
+// rata ke sejumlah order anak.
+type OrderSplitRequest struct {
+	OrderID int         `json:"order_id"`
+	Items   []SplitItem `json:"items,omitempty"`
+	Parts   int         `json:"parts,omitempty"`
+	Reason  string      `json:"reason"`
+}
+
+// OrderMergeRequest memindahkan semua baris order SourceID ke TargetID,
+// misalnya saat tamu pindah meja
+type OrderMergeRequest struct {
+	SourceID int    `json:"source_id"`
+	TargetID int    `json:"target_id"`
+	Reason   string `json:"reason"`
+}
+
+// OrderSplit adalah hasil split: order asal setelah dipecah dan order anaknya
+type OrderSplit struct {
+	Order    Order   `json:"order"`
+	Children []Order `json:"children"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Alasan pergerakan stok
+const (
+	StockReasonSale       = "sale"
+	StockReasonCancel     = "cancel"
+	StockReasonRefund     = "refund"
+	StockReasonRestock    = "restock"
+	StockReasonWaste      = "waste"
+	StockReasonCorrection = "correction"
+	// StockReasonTransfer memindahkan stok yang sudah diambil dari satu order
+	// ke order lain saat split bill atau gabung order; stok produk tidak berubah
+	StockReasonTransfer = "transfer"
+)
+
+// StockAdjustment adalah perubahan stok manual dari admin.
+// Restock menambah Quantity, waste mengurangi Quantity, sedangkan
+// correction mengganti stok menjadi Quantity hasil hitung fisik.
+type StockAdjustment struct {
+	ProductID int    `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+	Reason    string `json:"reason"`
+	Note      string `json:"note"`
+}
+
+// StockMovement mencatat setiap perubahan stok, positif berarti stok bertambah
+type StockMovement struct {
+	ID        int       `json:"id"`
+	ProductID int       `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+	Reason    string    `json:"reason"`
+	OrderID   *int      `json:"order_id,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	Username  string    `json:"username,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,47 @@
+package models
+
+// Jenis order
+const (
+	OrderTypeDineIn   = "dine_in"
+	OrderTypeTakeaway = "takeaway"
+	OrderTypeDelivery = "delivery"
+)
+
+var OrderTypes = []string{OrderTypeDineIn, OrderTypeTakeaway, OrderTypeDelivery}
+
+// IsValidOrderType mengecek apakah jenis order terdaftar di OrderTypes
+func IsValidOrderType(orderType string) bool {
+	for _, t := range OrderTypes {
+		if t == orderType {
+			return true
+		}
+	}
+	return false
+}
+
+// Status meja pada denah meja
+const (
+	TableFree     = "free"
+	TableOccupied = "occupied"
+)
+
+// Table adalah meja untuk order dine-in. Status, OpenOrders dan OpenTotal
+// dihitung dari order yang masih buka di meja tersebut.
+type Table struct {
+	ID         int     `json:"id"`
+	Name       string  `json:"name"`
+	Seats      int     `json:"seats"`
+	Area       string  `json:"area,omitempty"`
+	SortOrder  int     `json:"sort_order"`
+	Status     string  `json:"status"`
+	OpenOrders []int   `json:"open_orders"`
+	OpenTotal  float64 `json:"open_total"`
+}
+
+// OrderFilter membatasi order yang ditampilkan. Statuses kosong berarti
+// semua status; Table adalah nama meja.
+type OrderFilter struct {
+	Statuses []string
+	Type     string
+	Table    string
+}
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// Jenis aturan pajak
+const (
+	TaxService = "service"
+	TaxTax     = "tax"
+)
+
+// TaxRule adalah satu aturan pajak (PB1/PPN) atau service charge dalam
+// persen. Service charge dihitung dari nilai baris setelah diskon, pajak
+// dari nilai baris setelah diskon ditambah service charge.
+//
+// ProductID atau CategoryID membatasi produk yang dikenai aturan; keduanya
+// kosong berarti semua produk. Inclusive berarti harga produk sudah termasuk
+// pajak, jadi pajaknya hanya dicatat dan tidak ditambahkan ke total.
+type TaxRule struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	Rate       float64   `json:"rate"`
+	Inclusive  bool      `json:"inclusive"`
+	ProductID  *int      `json:"product_id,omitempty"`
+	CategoryID *int      `json:"category_id,omitempty"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AppliedTax adalah pajak atau service charge yang dihitung untuk sebuah
+// order. Base adalah nilai yang dikenai tarif, Amount hasilnya.
+type AppliedTax struct {
+	ID        int     `json:"id"`
+	OrderID   int     `json:"order_id"`
+	TaxRuleID int     `json:"tax_rule_id"`
+	Name      string  `json:"name"`
+	Type      string  `json:"type"`
+	Rate      float64 `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+	Base      float64 `json:"base"`
+	Amount    float64 `json:"amount"`
+}
+
+// TaxSummary adalah total satu aturan pajak pada laporan pajak
+type TaxSummary struct {
+	TaxRuleID  int     `json:"tax_rule_id"`
+	Name       string  `json:"name"`
+	Type       string  `json:"type"`
+	Rate       float64 `json:"rate"`
+	Inclusive  bool    `json:"inclusive"`
+	OrderCount int     `json:"order_count"`
+	Base       float64 `json:"base"`
+	Amount     float64 `json:"amount"`
+}
+
+// TaxReport merangkum pajak dan service charge order selesai antara From dan
+// To. Tax adalah pajak yang ditambahkan ke total, TaxIncluded pajak yang
+// sudah termasuk harga. Bagian refund dihitung pada tanggal refund dibuat.
+type TaxReport struct {
+	From                  time.Time    `json:"from"`
+	To                    time.Time    `json:"to"`
+	ServiceCharge         float64      `json:"service_charge"`
+	Tax                   float64      `json:"tax"`
+	TaxIncluded           float64      `json:"tax_included"`
+	RefundedServiceCharge float64      `json:"refunded_service_charge"`
+	RefundedTax           float64      `json:"refunded_tax"`
+	Rules                 []TaxSummary `json:"rules"`
+}
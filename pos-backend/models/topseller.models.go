@@ -1,6 +1,7 @@
 package models
 
 type TopSeller struct {
-	ProductName string `json:"product_name"`
-	TotalSold   int    `json:"total_sold"`
+	ProductName string  `json:"product_name"`
+	TotalSold   int     `json:"total_sold"`
+	Revenue     float64 `json:"revenue"`
 }
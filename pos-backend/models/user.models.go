@@ -1,7 +1,27 @@
 package models
 
+// Role yang dikenal sistem. Role baru cukup ditambahkan di sini
+// dan di tabel permission pada package routes.
+const (
+	RoleAdmin = "admin"
+	RoleKasir = "kasir"
+	RoleDapur = "dapur"
+)
+
+var Roles = []string{RoleAdmin, RoleKasir, RoleDapur}
+
 type User struct {
 	Username string
 	Password string
 	Role     string
 }
+
+// IsValidRole mengecek apakah role terdaftar di Roles
+func IsValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package receipt
+
+import (
+	"bytes"
+
+	"pos-backend/models"
+)
+
+// Perintah ESC/POS yang dipakai printer thermal
+var (
+	escInit      = []byte{0x1b, '@'}        // ESC @ reset printer
+	escCodePage  = []byte{0x1b, 't', 16}    // ESC t 16 pilih WPC1252
+	escFeedLines = []byte{0x1b, 'd', 4}     // ESC d n maju n baris
+	gsPartialCut = []byte{0x1d, 'V', 66, 0} // GS V 66 0 potong kertas
+)
+
+// ESCPOS merender struk sebagai byte stream mentah untuk printer thermal
+func (r *Renderer) ESCPOS(order models.Order) ([]byte, error) {
+	text, err := r.Text(order)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(escInit)
+	buf.Write(escCodePage)
+	buf.Write(toCP1252(text))
+	buf.Write(escFeedLines)
+	buf.Write(gsPartialCut)
+	return buf.Bytes(), nil
+}
+
+// toCP1252 mengubah teks UTF-8 ke code page printer, karakter yang tidak
+// ada di Latin-1 diganti "?"
+func toCP1252(text []byte) []byte {
+	out := make([]byte, 0, len(text))
+	for _, c := range string(text) {
+		if c < 0x100 {
+			out = append(out, byte(c))
+		} else {
+			out = append(out, '?')
+		}
+	}
+	return out
+}
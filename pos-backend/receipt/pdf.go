@@ -0,0 +1,51 @@
+package receipt
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"pos-backend/models"
+)
+
+const (
+	pdfPaperWidth = 80.0 // mm, kertas thermal 80mm
+	pdfMargin     = 4.0
+)
+
+// PDF merender struk di halaman selebar kertas thermal dengan font Courier
+// sehingga tata letaknya sama dengan versi teks
+func (r *Renderer) PDF(order models.Order) ([]byte, error) {
+	text, err := r.Text(order)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimRight(string(text), "\n"), "\n")
+
+	// Lebar karakter Courier adalah 0.6 kali ukuran font
+	charWidth := (pdfPaperWidth - 2*pdfMargin) / float64(r.cfg.Width)
+	fontSize := charWidth / 0.6 / 25.4 * 72
+	lineHeight := charWidth / 0.6 * 1.2
+	height := 2*pdfMargin + lineHeight*float64(len(lines))
+
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr: "mm",
+		Size:    gofpdf.SizeType{Wd: pdfPaperWidth, Ht: height},
+	})
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+	pdf.SetFont("Courier", "", fontSize)
+
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	for _, line := range lines {
+		pdf.CellFormat(0, lineHeight, tr(line), "", 1, "L", false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,192 @@
+// Package receipt merender struk order ke teks lebar tetap, ESC/POS dan PDF.
+// Ketiga format memakai hasil template teks yang sama.
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"pos-backend/config"
+	"pos-backend/models"
+)
+
+// defaultTemplate dipakai jika receipt.template_file tidak diisi
+const defaultTemplate = `{{center .Store.StoreName}}
+{{range .Store.Address}}{{center .}}
+{{end}}{{if .Store.Phone}}{{center .Store.Phone}}
+{{end}}{{divider}}
+{{row (printf "Order #%d" .Order.ID) (datetime .Order.CreatedAt)}}
+{{row "Status" .Order.Status}}
+{{if .ParentOrderID}}{{row "Split dari" (printf "Order #%d" .ParentOrderID)}}
+{{end}}{{if .Order.TableName}}{{row "Meja" .Order.TableName}}
+{{else if .Order.DeliveryAddress}}{{row "Delivery" ""}}
+{{wrap .Order.DeliveryAddress}}
+{{else if eq .Order.Type "takeaway"}}{{row "Takeaway" ""}}
+{{end}}{{divider}}
+{{range .Order.Details}}{{wrap .ProductName}}
+{{range .Modifiers}}{{row (printf "  + %s" .OptionName) ""}}
+{{end}}{{row (printf "  %d x %s" .Quantity (money .UnitPrice)) (money .TotalPrice)}}
+{{end}}{{divider}}
+{{if or .Order.Discount .Order.Taxes}}{{row "Subtotal" (money .Order.Subtotal)}}
+{{range .Order.Promotions}}{{row .Name (printf "-%s" (money .Amount))}}
+{{end}}{{range .Order.Taxes}}{{if .Inclusive}}{{row (printf "%s %g%% (termasuk)" .Name .Rate) (money .Amount)}}
+{{else}}{{row (printf "%s %g%%" .Name .Rate) (money .Amount)}}
+{{end}}{{end}}{{end}}{{row "TOTAL" (money .Total)}}
+{{range .Order.Payments}}{{row .Method (money .Tendered)}}
+{{end}}{{if .Change}}{{row "Kembali" (money .Change)}}
+{{end}}{{range .Order.Refunds}}{{row (printf "Refund %s" .Reason) (printf "-%s" (money .Amount))}}
+{{end}}{{if .Order.PointsEarned}}{{row "Poin didapat" (printf "%d" .Order.PointsEarned)}}
+{{end}}{{divider}}
+{{range .Store.Footer}}{{center .}}
+{{end}}`
+
+// Data adalah nilai yang tersedia di template struk
+type Data struct {
+	Store     config.ReceiptConfig
+	Order     models.Order
+	Total     float64
+	Paid      float64
+	Change    float64
+	Refunded  float64
+	PrintedAt time.Time
+
+	// ParentOrderID adalah order asal untuk struk hasil split bill
+	ParentOrderID int
+}
+
+// Renderer menyimpan template yang sudah di-parse untuk satu toko
+type Renderer struct {
+	cfg  config.ReceiptConfig
+	tmpl *template.Template
+}
+
+// New mem-parse template dari config, atau template bawaan jika tidak ada
+func New(cfg config.ReceiptConfig) (*Renderer, error) {
+	text := defaultTemplate
+	if cfg.TemplateFile != "" {
+		data, err := os.ReadFile(cfg.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read receipt template: %w", err)
+		}
+		text = string(data)
+	}
+
+	r := &Renderer{cfg: cfg}
+	tmpl, err := template.New("receipt").Funcs(r.funcs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse receipt template: %w", err)
+	}
+	r.tmpl = tmpl
+	return r, nil
+}
+
+// Width adalah jumlah karakter per baris struk
+func (r *Renderer) Width() int {
+	return r.cfg.Width
+}
+
+// Text merender struk sebagai teks lebar tetap
+func (r *Renderer) Text(order models.Order) ([]byte, error) {
+	data := Data{
+		Store:     r.cfg,
+		Order:     order,
+		PrintedAt: time.Now(),
+	}
+	if order.TotalPrice != nil {
+		data.Total = *order.TotalPrice
+	}
+	if order.ParentOrderID != nil {
+		data.ParentOrderID = *order.ParentOrderID
+	}
+	for _, p := range order.Payments {
+		data.Paid += p.Amount
+		data.Change += p.Change
+	}
+	for _, refund := range order.Refunds {
+		data.Refunded += refund.Amount
+	}
+
+	var buf bytes.Buffer
+	if err := r.tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render receipt: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (r *Renderer) funcs() template.FuncMap {
+	width := r.cfg.Width
+	return template.FuncMap{
+		"money":    Money,
+		"datetime": func(t time.Time) string { return t.Format("02/01/06 15:04") },
+		"divider":  func() string { return strings.Repeat("-", width) },
+		"center": func(s string) string {
+			s = truncate(s, width)
+			return strings.Repeat(" ", (width-utf8.RuneCountInString(s))/2) + s
+		},
+		"wrap": func(s string) string { return wrap(s, width) },
+		// row menaruh left di kiri dan right rata kanan dalam satu baris
+		"row": func(left, right string) string {
+			right = truncate(right, width)
+			left = truncate(left, width-utf8.RuneCountInString(right)-1)
+			gap := width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+			return left + strings.Repeat(" ", gap) + right
+		},
+	}
+}
+
+// wrap memecah teks per kata agar tidak melebihi n karakter per baris
+func wrap(s string, n int) string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(s) {
+		word = truncate(word, n)
+		if line != "" && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > n {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	return strings.Join(append(lines, line), "\n")
+}
+
+func truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// Money memformat rupiah dengan pemisah ribuan titik, misalnya 32.000 atau 1.250,50
+func Money(v float64) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	cents := int64(math.Round(v * 100))
+	whole := fmt.Sprintf("%d", cents/100)
+
+	var b strings.Builder
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+	if frac := cents % 100; frac != 0 {
+		fmt.Fprintf(&b, ",%02d", frac)
+	}
+	return sign + b.String()
+}
@@ -2,33 +2,132 @@ package routes
 
 import (
 	"context"
-	"database/sql"
 	"net/http"
 	"pos-backend/handlers"
+	"pos-backend/store"
 
 	"github.com/go-redis/redis/v8"
 )
 
-    func RegisterOrderRoutes(ctx context.Context, db *sql.DB, rdb *redis.Client) {
+    func RegisterOrderRoutes(ctx context.Context, st *store.Store, rdb *redis.Client) {
         
  
         http.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
-            handlers.GetOrders(ctx, db, rdb, w, r)
+            handlers.GetOrders(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/orders/{id}/receipt", func(w http.ResponseWriter, r *http.Request) {
+            handlers.GetReceipt(ctx, st, rdb, w, r)
         })
         http.HandleFunc("/create-order", func(w http.ResponseWriter, r *http.Request) {
-            handlers.CreateOrder(ctx, db, rdb, w, r)
+            handlers.CreateOrder(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/price-order", func(w http.ResponseWriter, r *http.Request) {
+            handlers.PriceOrder(ctx, st, rdb, w, r)
         })
         http.HandleFunc("/complete-order", func(w http.ResponseWriter, r *http.Request) {
-            handlers.CompleteOrder(ctx, db, rdb, w, r)
+            handlers.CompleteOrder(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/add-payment", func(w http.ResponseWriter, r *http.Request) {
+            handlers.AddPayment(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/order-payments", func(w http.ResponseWriter, r *http.Request) {
+            handlers.GetOrderPayments(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/order-status", func(w http.ResponseWriter, r *http.Request) {
+            handlers.UpdateOrderStatus(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/order-timeline", func(w http.ResponseWriter, r *http.Request) {
+            handlers.GetOrderTimeline(ctx, st, rdb, w, r)
         })
         http.HandleFunc("/cancel-order", func(w http.ResponseWriter, r *http.Request) {
-            handlers.CancelOrder(ctx, db, rdb, w, r)
+            handlers.CancelOrder(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/refund-order", func(w http.ResponseWriter, r *http.Request) {
+            handlers.RefundOrder(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/order-refunds", func(w http.ResponseWriter, r *http.Request) {
+            handlers.GetOrderRefunds(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/kitchen/orders", func(w http.ResponseWriter, r *http.Request) {
+            handlers.GetKitchenOrders(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/kitchen/stream", func(w http.ResponseWriter, r *http.Request) {
+            handlers.KitchenStream(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/kitchen/prepare-item", func(w http.ResponseWriter, r *http.Request) {
+            handlers.PrepareOrderItem(ctx, st, rdb, w, r)
         })
         http.HandleFunc("/completed-orders", func(w http.ResponseWriter, r *http.Request) {
-            handlers.GetCompletedOrders(ctx, db, rdb, w, r)
+            handlers.GetCompletedOrders(ctx, st, rdb, w, r)
         })
         http.HandleFunc("/delete-order", func(w http.ResponseWriter, r *http.Request) {
-            handlers.DeleteOrder(ctx, db, rdb, w, r)
+            handlers.DeleteOrder(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/customers", func(w http.ResponseWriter, r *http.Request) {
+            handlers.GetCustomers(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/create-customer", func(w http.ResponseWriter, r *http.Request) {
+            handlers.CreateCustomer(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/update-customer/", func(w http.ResponseWriter, r *http.Request) {
+            handlers.UpdateCustomer(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/delete-customer/", func(w http.ResponseWriter, r *http.Request) {
+            handlers.DeleteCustomer(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/customer-history", func(w http.ResponseWriter, r *http.Request) {
+            handlers.GetCustomerHistory(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/adjust-points", func(w http.ResponseWriter, r *http.Request) {
+            handlers.AdjustPoints(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/open-shift", func(w http.ResponseWriter, r *http.Request) {
+            handlers.OpenShift(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/current-shift", func(w http.ResponseWriter, r *http.Request) {
+            handlers.GetCurrentShift(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/cash-movement", func(w http.ResponseWriter, r *http.Request) {
+            handlers.AddCashMovement(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/close-shift", func(w http.ResponseWriter, r *http.Request) {
+            handlers.CloseShift(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/shift-report", func(w http.ResponseWriter, r *http.Request) {
+            handlers.GetShiftReport(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/shifts", func(w http.ResponseWriter, r *http.Request) {
+            handlers.GetShifts(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/tables", func(w http.ResponseWriter, r *http.Request) {
+            handlers.GetTables(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/create-table", func(w http.ResponseWriter, r *http.Request) {
+            handlers.CreateTable(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/update-table/", func(w http.ResponseWriter, r *http.Request) {
+            handlers.UpdateTable(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/delete-table/", func(w http.ResponseWriter, r *http.Request) {
+            handlers.DeleteTable(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/add-order-item", func(w http.ResponseWriter, r *http.Request) {
+            handlers.AddOrderItem(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/update-order-item", func(w http.ResponseWriter, r *http.Request) {
+            handlers.UpdateOrderItem(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/void-order-item", func(w http.ResponseWriter, r *http.Request) {
+            handlers.VoidOrderItem(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/order-amendments", func(w http.ResponseWriter, r *http.Request) {
+            handlers.GetOrderAmendments(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/split-order", func(w http.ResponseWriter, r *http.Request) {
+            handlers.SplitOrder(ctx, st, rdb, w, r)
+        })
+        http.HandleFunc("/merge-orders", func(w http.ResponseWriter, r *http.Request) {
+            handlers.MergeOrders(ctx, st, rdb, w, r)
         })
     }
     
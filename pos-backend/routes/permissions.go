@@ -0,0 +1,126 @@
+package routes
+
+import (
+	"pos-backend/models"
+	"pos-backend/utils"
+)
+
+// PublicPaths bisa diakses tanpa login
+var PublicPaths = []string{"/login", "/refresh-token"}
+
+// StreamPaths adalah stream SSE yang boleh membawa access token di query
+// karena EventSource tidak bisa mengirim header Authorization
+var StreamPaths = []string{"/kitchen/stream"}
+
+var (
+	adminOnly     = []string{models.RoleAdmin}
+	adminAndKasir = []string{models.RoleAdmin, models.RoleKasir}
+	kitchenStaff  = []string{models.RoleAdmin, models.RoleKasir, models.RoleDapur}
+)
+
+// Permissions memetakan setiap route ke role yang boleh memanggilnya.
+// Pola yang diakhiri "/" berlaku untuk semua path di bawahnya, sama seperti
+// http.ServeMux. Route yang tidak terdaftar ditolak untuk semua role.
+var Permissions = map[string][]string{
+	// produk
+	"/products":        adminAndKasir,
+	"/product/":        adminAndKasir,
+	"/create-product":  adminOnly,
+	"/import-products": adminOnly,
+	"/update-product/": adminOnly,
+	"/delete-product/": adminOnly,
+	"/adjust-stock":    adminOnly,
+	"/low-stock":       adminOnly,
+	"/stock-movements": adminOnly,
+
+	// modifier (ukuran, level pedas, topping)
+	"/modifier-groups":        adminAndKasir,
+	"/save-modifier-group":    adminOnly,
+	"/delete-modifier-group/": adminOnly,
+
+	// kategori
+	"/categories":         adminAndKasir,
+	"/create-category":    adminOnly,
+	"/update-category/":   adminOnly,
+	"/delete-category/":   adminOnly,
+	"/reorder-categories": adminOnly,
+
+	// promo dan kode voucher
+	"/promotions":        adminAndKasir,
+	"/save-promotion":    adminOnly,
+	"/delete-promotion/": adminOnly,
+	"/promotion-report":  adminOnly,
+
+	// pajak (PB1/PPN) dan service charge
+	"/tax-rules":        adminAndKasir,
+	"/save-tax-rule":    adminOnly,
+	"/delete-tax-rule/": adminOnly,
+	"/tax-report":       adminOnly,
+
+	// order
+	"/orders":           adminAndKasir,
+	"/orders/":          adminAndKasir,
+	"/create-order":     adminAndKasir,
+	"/price-order":      adminAndKasir,
+	"/complete-order":   adminAndKasir,
+	"/add-payment":      adminAndKasir,
+	"/order-payments":   adminAndKasir,
+	"/order-status":     adminAndKasir,
+	"/order-timeline":   adminAndKasir,
+	"/cancel-order":     adminAndKasir,
+	"/refund-order":     adminAndKasir,
+	"/order-refunds":    adminAndKasir,
+	"/completed-orders": adminAndKasir,
+	"/delete-order":     adminOnly,
+
+	// pelanggan dan poin loyalty
+	"/customers":        adminAndKasir,
+	"/create-customer":  adminAndKasir,
+	"/update-customer/": adminAndKasir,
+	"/delete-customer/": adminOnly,
+	"/customer-history": adminAndKasir,
+	"/adjust-points":    adminOnly,
+
+	// shift kasir dan laci
+	"/open-shift":    adminAndKasir,
+	"/current-shift": adminAndKasir,
+	"/cash-movement": adminAndKasir,
+	"/close-shift":   adminAndKasir,
+	"/shift-report":  adminAndKasir,
+	"/shifts":        adminOnly,
+
+	// denah meja
+	"/tables":        adminAndKasir,
+	"/create-table":  adminOnly,
+	"/update-table/": adminOnly,
+	"/delete-table/": adminOnly,
+
+	// ubah order yang masih berjalan
+	"/add-order-item":    adminAndKasir,
+	"/update-order-item": adminAndKasir,
+	"/void-order-item":   adminAndKasir,
+	"/order-amendments":  adminAndKasir,
+
+	// split dan gabung bill
+	"/split-order":  adminAndKasir,
+	"/merge-orders": adminAndKasir,
+
+	// dapur
+	"/kitchen/": kitchenStaff,
+
+	// user dan dashboard
+	"/logout":           {utils.AnyRole},
+	"/create-account":   adminOnly,
+	"/count-admin":      adminOnly,
+	"/count-cashier":    adminOnly,
+	"/top-selling-menu": adminAndKasir,
+	"/total-revenue":    adminAndKasir,
+	"/sales-report":     adminOnly,
+	"/export/":          adminOnly,
+	"/product-count":    adminAndKasir,
+	"/onprogress-count": adminAndKasir,
+
+	// log audit
+	"/audit-log":        adminOnly,
+	"/verify-audit-log": adminOnly,
+}
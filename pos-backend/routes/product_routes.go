@@ -2,28 +2,83 @@ package routes
 
 import (
 	"context"
-	"database/sql"
 	"net/http"
 	"pos-backend/handlers"
+	"pos-backend/store"
 
 	"github.com/go-redis/redis/v8"
 )
-func RegisterProductRoutes(ctx context.Context, db *sql.DB, rdb *redis.Client) {
+func RegisterProductRoutes(ctx context.Context, st *store.Store, rdb *redis.Client) {
 
 
 
     http.HandleFunc("/products", func(w http.ResponseWriter, r *http.Request) {
-        handlers.GetProducts(ctx, db, rdb, w, r)
+        handlers.GetProducts(ctx, st, rdb, w, r)
     })
     http.HandleFunc("/product/", func(w http.ResponseWriter, r *http.Request) {
-        handlers.GetProductByID(ctx, db, rdb, w, r)
+        handlers.GetProductByID(ctx, st, rdb, w, r)
     })
     http.HandleFunc("/create-product", func(w http.ResponseWriter, r *http.Request) {
-        handlers.CreateProduct(ctx, db, rdb, w, r)
+        handlers.CreateProduct(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/import-products", func(w http.ResponseWriter, r *http.Request) {
+        handlers.ImportProducts(ctx, st, rdb, w, r)
     })
     http.HandleFunc("/update-product/", func(w http.ResponseWriter, r *http.Request) {
-        handlers.UpdateProduct(ctx, db, rdb, w, r)
+        handlers.UpdateProduct(ctx, st, rdb, w, r)
     })
     http.HandleFunc("/delete-product/", func(w http.ResponseWriter, r *http.Request) {
-        handlers.DeleteProduct(ctx, db, rdb, w, r)
-    })}
\ No newline at end of file
+        handlers.DeleteProduct(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/modifier-groups", func(w http.ResponseWriter, r *http.Request) {
+        handlers.GetModifierGroups(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/save-modifier-group", func(w http.ResponseWriter, r *http.Request) {
+        handlers.SaveModifierGroup(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/delete-modifier-group/", func(w http.ResponseWriter, r *http.Request) {
+        handlers.DeleteModifierGroup(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/categories", func(w http.ResponseWriter, r *http.Request) {
+        handlers.GetCategories(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/create-category", func(w http.ResponseWriter, r *http.Request) {
+        handlers.CreateCategory(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/update-category/", func(w http.ResponseWriter, r *http.Request) {
+        handlers.UpdateCategory(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/delete-category/", func(w http.ResponseWriter, r *http.Request) {
+        handlers.DeleteCategory(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/reorder-categories", func(w http.ResponseWriter, r *http.Request) {
+        handlers.ReorderCategories(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/promotions", func(w http.ResponseWriter, r *http.Request) {
+        handlers.GetPromotions(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/save-promotion", func(w http.ResponseWriter, r *http.Request) {
+        handlers.SavePromotion(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/delete-promotion/", func(w http.ResponseWriter, r *http.Request) {
+        handlers.DeletePromotion(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/tax-rules", func(w http.ResponseWriter, r *http.Request) {
+        handlers.GetTaxRules(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/save-tax-rule", func(w http.ResponseWriter, r *http.Request) {
+        handlers.SaveTaxRule(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/delete-tax-rule/", func(w http.ResponseWriter, r *http.Request) {
+        handlers.DeleteTaxRule(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/adjust-stock", func(w http.ResponseWriter, r *http.Request) {
+        handlers.AdjustStock(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/low-stock", func(w http.ResponseWriter, r *http.Request) {
+        handlers.GetLowStock(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/stock-movements", func(w http.ResponseWriter, r *http.Request) {
+        handlers.GetStockMovements(ctx, st, rdb, w, r)
+    })
+}
\ No newline at end of file
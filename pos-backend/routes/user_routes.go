@@ -2,38 +2,68 @@ package routes
 
 import (
 	"context"
-	"database/sql"
 	"net/http"
 	"pos-backend/handlers"
+	"pos-backend/store"
 
 	"github.com/go-redis/redis/v8"
 )
 
-func RegisterUserRoutes(ctx context.Context, db *sql.DB, rdb *redis.Client) {
+func RegisterUserRoutes(ctx context.Context, st *store.Store, rdb *redis.Client) {
 
     http.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
-        handlers.LoginHandler(ctx, db, rdb, w, r)
+        handlers.LoginHandler(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/refresh-token", func(w http.ResponseWriter, r *http.Request) {
+        handlers.RefreshTokenHandler(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/logout", func(w http.ResponseWriter, r *http.Request) {
+        handlers.LogoutHandler(ctx, st, rdb, w, r)
     })
     http.HandleFunc("/create-account", func(w http.ResponseWriter, r *http.Request) {
-        handlers.CreateAccount(ctx, db, rdb, w, r)
+        handlers.CreateAccount(ctx, st, rdb, w, r)
     })
     http.HandleFunc("/count-admin", func(w http.ResponseWriter, r *http.Request) {
-        handlers.CountAdmin(ctx, db, rdb, w, r)
+        handlers.CountAdmin(ctx, st, rdb, w, r)
     })
     http.HandleFunc("/count-cashier", func(w http.ResponseWriter, r *http.Request) {
-        handlers.CountCashier(ctx, db, rdb, w, r)
+        handlers.CountCashier(ctx, st, rdb, w, r)
     })
     http.HandleFunc("/top-selling-menu", func(w http.ResponseWriter, r *http.Request) {
-        handlers.TopSeller(ctx, db, rdb, w, r)
+        handlers.TopSeller(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/sales-report", func(w http.ResponseWriter, r *http.Request) {
+        handlers.GetSalesReport(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/promotion-report", func(w http.ResponseWriter, r *http.Request) {
+        handlers.GetPromotionReport(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/tax-report", func(w http.ResponseWriter, r *http.Request) {
+        handlers.GetTaxReport(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/export/orders", func(w http.ResponseWriter, r *http.Request) {
+        handlers.ExportOrders(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/export/order-details", func(w http.ResponseWriter, r *http.Request) {
+        handlers.ExportOrderDetails(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/export/sales-report", func(w http.ResponseWriter, r *http.Request) {
+        handlers.ExportSalesReport(ctx, st, rdb, w, r)
     })
     http.HandleFunc("/total-revenue", func(w http.ResponseWriter, r *http.Request) {
-        handlers.TotalRevenue(ctx, db, rdb, w, r)
+        handlers.TotalRevenue(ctx, st, rdb, w, r)
     })
     http.HandleFunc("/product-count", func(w http.ResponseWriter, r *http.Request) {
-        handlers.GetProductList(ctx, db, rdb, w, r)
+        handlers.GetProductList(ctx, st, rdb, w, r)
     })
     http.HandleFunc("/onprogress-count", func(w http.ResponseWriter, r *http.Request) {
-        handlers.CountOrderProgress(ctx, db, rdb, w, r)
+        handlers.CountOrderProgress(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/audit-log", func(w http.ResponseWriter, r *http.Request) {
+        handlers.GetAuditLog(ctx, st, rdb, w, r)
+    })
+    http.HandleFunc("/verify-audit-log", func(w http.ResponseWriter, r *http.Request) {
+        handlers.VerifyAuditLog(ctx, st, rdb, w, r)
     })
 
 
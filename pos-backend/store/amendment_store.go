@@ -0,0 +1,376 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"pos-backend/models"
+)
+
+// AddOrderItem menambah baris baru ke order yang masih On Progress lalu
+// menghitung ulang promo, pajak dan total order
+func (s *sqlStore) AddOrderItem(ctx context.Context, orderID int, item models.OrderItem, username, reason string) (*models.Order, error) {
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		order, details, err := s.amendableOrder(ctx, tx, orderID)
+		if err != nil {
+			return err
+		}
+
+		detail, err := s.priceItem(ctx, tx, len(details)+1, item)
+		if err != nil {
+			return err
+		}
+		detail.OrderID = orderID
+		if err := s.insertDetail(ctx, tx, &detail); err != nil {
+			return err
+		}
+		if err := s.takeStock(ctx, tx, orderID, detail); err != nil {
+			return err
+		}
+
+		return s.repriceOrder(ctx, tx, order, append(details, detail), models.OrderAmendment{
+			OrderDetailID: detail.ID,
+			Action:        models.AmendAdd,
+			ProductName:   detail.ProductName,
+			NewQuantity:   detail.Quantity,
+			Username:      username,
+			Reason:        strings.TrimSpace(reason),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetOrder(ctx, orderID)
+}
+
+// SetOrderItemQuantity mengubah jumlah satu baris order yang masih On
+// Progress. Baris yang jumlahnya bertambah perlu disiapkan ulang oleh dapur.
+func (s *sqlStore) SetOrderItemQuantity(ctx context.Context, detailID, quantity int, username, reason string) (*models.Order, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive, void the item to remove it", ErrInvalid)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalid)
+	}
+
+	var orderID int
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		orderID, err = s.detailOrderID(ctx, tx, detailID)
+		if err != nil {
+			return err
+		}
+		order, details, err := s.amendableOrder(ctx, tx, orderID)
+		if err != nil {
+			return err
+		}
+		// Baris bisa sudah dipindah atau dihapus sebelum order terkunci
+		i := detailIndex(details, detailID)
+		if i < 0 {
+			return fmt.Errorf("%w: order detail %d is not part of order %d", ErrNotFound, detailID, orderID)
+		}
+		old := details[i].Quantity
+		if quantity == old {
+			return fmt.Errorf("%w: quantity is already %d", ErrInvalid, quantity)
+		}
+
+		if quantity > old {
+			extra := details[i]
+			extra.Quantity = quantity - old
+			if err := s.takeStock(ctx, tx, orderID, extra); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}ORDER_DETAILS SET prepared_at = NULL, prepared_by = NULL WHERE id = ?"), detailID); err != nil {
+				return err
+			}
+			details[i].PreparedAt, details[i].PreparedBy = nil, ""
+		} else if err := s.giveBackStock(ctx, tx, orderID, details[i].ProductID, old-quantity); err != nil {
+			return err
+		}
+		details[i].Quantity = quantity
+		details[i].TotalPrice = roundMoney(details[i].UnitPrice * float64(quantity))
+
+		return s.repriceOrder(ctx, tx, order, details, models.OrderAmendment{
+			OrderDetailID: detailID,
+			Action:        models.AmendQuantity,
+			ProductName:   details[i].ProductName,
+			OldQuantity:   old,
+			NewQuantity:   quantity,
+			Username:      username,
+			Reason:        reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetOrder(ctx, orderID)
+}
+
+// VoidOrderItem menghapus satu baris dari order yang masih On Progress dan
+// mengembalikan stoknya. Baris terakhir tidak bisa di-void; batalkan ordernya.
+func (s *sqlStore) VoidOrderItem(ctx context.Context, detailID int, username, reason string) (*models.Order, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalid)
+	}
+
+	var orderID int
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		orderID, err = s.detailOrderID(ctx, tx, detailID)
+		if err != nil {
+			return err
+		}
+		order, details, err := s.amendableOrder(ctx, tx, orderID)
+		if err != nil {
+			return err
+		}
+		// Baris bisa sudah dipindah atau dihapus sebelum order terkunci
+		i := detailIndex(details, detailID)
+		if i < 0 {
+			return fmt.Errorf("%w: order detail %d is not part of order %d", ErrNotFound, detailID, orderID)
+		}
+		if len(details) == 1 {
+			return fmt.Errorf("%w: cannot void the last item, cancel the order instead", ErrInvalid)
+		}
+		voided := details[i]
+
+		if err := s.giveBackStock(ctx, tx, orderID, voided.ProductID, voided.Quantity); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}ORDER_DETAIL_MODIFIERS WHERE order_detail_id = ?"), detailID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}ORDER_PROMOTIONS WHERE order_detail_id = ?"), detailID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}ORDER_DETAILS WHERE id = ?"), detailID); err != nil {
+			return err
+		}
+
+		details = append(details[:i], details[i+1:]...)
+		return s.repriceOrder(ctx, tx, order, details, models.OrderAmendment{
+			OrderDetailID: detailID,
+			Action:        models.AmendVoid,
+			ProductName:   voided.ProductName,
+			OldQuantity:   voided.Quantity,
+			Username:      username,
+			Reason:        reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetOrder(ctx, orderID)
+}
+
+func (s *sqlStore) OrderAmendments(ctx context.Context, orderID int) ([]models.OrderAmendment, error) {
+	if n, err := s.count(ctx, "SELECT COUNT(*) FROM {schema}ORDERS WHERE id = ?", orderID); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrNotFound
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.q(`
+		SELECT id, order_id, order_detail_id, action, product_name, old_quantity, new_quantity, old_total, new_total, username, reason, created_at
+		FROM {schema}ORDER_AMENDMENTS
+		WHERE order_id = ?
+		ORDER BY id ASC`), orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	amendments := []models.OrderAmendment{}
+	for rows.Next() {
+		var a models.OrderAmendment
+		var detailID sql.NullInt64
+		var username, reason sql.NullString
+		if err := rows.Scan(&a.ID, &a.OrderID, &detailID, &a.Action, &a.ProductName, &a.OldQuantity, &a.NewQuantity, &a.OldTotal, &a.NewTotal, &username, &reason, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		a.OrderDetailID = int(detailID.Int64)
+		a.Username = username.String
+		a.Reason = reason.String
+		amendments = append(amendments, a)
+	}
+	return amendments, rows.Err()
+}
+
+// amendableOrder mengunci order yang akan diubah barisnya dan memuat
+// baris serta kode promonya. Hanya order On Progress yang bisa diubah.
+func (s *sqlStore) amendableOrder(ctx context.Context, tx *sql.Tx, orderID int) (*models.Order, []models.OrderDetail, error) {
+	return s.lockOrderLines(ctx, tx, orderID, models.OrderStatusOnProgress)
+}
+
+// lockOrderLines mengunci order berstatus salah satu statuses beserta
+// baris dan kode promonya. Bagian split bill yang tidak punya baris
+// ditolak karena totalnya tidak bisa dihitung ulang dari baris.
+func (s *sqlStore) lockOrderLines(ctx context.Context, tx *sql.Tx, orderID int, statuses ...string) (*models.Order, []models.OrderDetail, error) {
+	order, err := scanOrder(tx.QueryRowContext(ctx, s.q("SELECT "+orderColumns+" FROM {schema}ORDERS WHERE id = ? "+s.dialect.forUpdate()), orderID))
+	if err == sql.ErrNoRows {
+		return nil, nil, fmt.Errorf("%w: order %d", ErrNotFound, orderID)
+	} else if err != nil {
+		return nil, nil, err
+	}
+	allowed := false
+	for _, status := range statuses {
+		allowed = allowed || order.Status == status
+	}
+	if !allowed {
+		return nil, nil, fmt.Errorf("%w: order %d is %s, only orders %s can be changed", ErrConflict, orderID, order.Status, strings.Join(statuses, " or "))
+	}
+
+	// Kode promo hanya tersimpan di ORDER_PROMOTIONS
+	var code sql.NullString
+	err = tx.QueryRowContext(ctx, s.q("SELECT MAX(code) FROM {schema}ORDER_PROMOTIONS WHERE order_id = ? AND code IS NOT NULL"), orderID).Scan(&code)
+	if err != nil {
+		return nil, nil, err
+	}
+	order.PromoCode = code.String
+
+	details, err := s.orderDetails(ctx, tx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(details) == 0 {
+		return nil, nil, fmt.Errorf("%w: order %d is an even share of a split bill and has no items to change", ErrConflict, orderID)
+	}
+	return order, details, nil
+}
+
+// detailOrderID mencari order pemilik baris
+func (s *sqlStore) detailOrderID(ctx context.Context, tx *sql.Tx, detailID int) (int, error) {
+	var orderID int
+	err := tx.QueryRowContext(ctx, s.q("SELECT order_id FROM {schema}ORDER_DETAILS WHERE id = ?"), detailID).Scan(&orderID)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%w: order detail %d", ErrNotFound, detailID)
+	}
+	return orderID, err
+}
+
+func detailIndex(details []models.OrderDetail, detailID int) int {
+	for i, d := range details {
+		if d.ID == detailID {
+			return i
+		}
+	}
+	return -1
+}
+
+// repriceOrder menghitung ulang promo, pajak dan total setelah baris order
+// berubah, menyimpan hasilnya dan mencatat perubahan di ORDER_AMENDMENTS.
+// Promo dihitung pada waktu order dibuat supaya happy hour tetap berlaku
+// untuk tab yang dibuka saat itu. Total baru tidak boleh di bawah
+// pembayaran yang sudah diterima.
+func (s *sqlStore) repriceOrder(ctx context.Context, tx *sql.Tx, order *models.Order, details []models.OrderDetail, amendments ...models.OrderAmendment) error {
+	oldTotal := 0.0
+	if order.TotalPrice != nil {
+		oldTotal = *order.TotalPrice
+	}
+	oldCode := order.PromoCode
+
+	pricing, err := s.adjustDetails(ctx, tx, order, details, order.CreatedAt)
+	if err != nil {
+		return err
+	}
+	orderTotals(order, pricing)
+	total := *order.TotalPrice
+
+	payments, err := s.orderPayments(ctx, tx, order.ID)
+	if err != nil {
+		return err
+	}
+	if paid := summarizePayments(order.ID, total, payments).AmountPaid; total < paid && !moneyEqual(total, paid) {
+		return fmt.Errorf("%w: order total %.2f would be below %.2f already paid", ErrConflict, total, paid)
+	}
+
+	if oldCode != "" && order.PromoCode == "" {
+		if err := s.releasePromoUsage(ctx, tx, order.ID); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}ORDER_PROMOTIONS WHERE order_id = ?"), order.ID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}ORDER_TAXES WHERE order_id = ?"), order.ID); err != nil {
+		return err
+	}
+
+	for _, d := range pricing.details {
+		_, err := tx.ExecContext(ctx, s.q(`
+			UPDATE {schema}ORDER_DETAILS SET quantity = ?, total_price = ?, discount = ?, service_charge = ?, tax = ?, tax_included = ?
+			WHERE id = ?`),
+			d.Quantity, d.TotalPrice, d.Discount, d.ServiceCharge, d.Tax, d.TaxIncluded, d.ID)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, s.q(`
+		UPDATE {schema}ORDERS SET subtotal = ?, discount = ?, service_charge = ?, tax = ?, tax_included = ?, total_price = ?
+		WHERE id = ?`),
+		order.Subtotal, order.Discount, order.ServiceCharge, order.Tax, order.TaxIncluded, total, order.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.insertAppliedPromotions(ctx, tx, order); err != nil {
+		return err
+	}
+	if err := s.saveAppliedTaxes(ctx, tx, order); err != nil {
+		return err
+	}
+
+	return s.recordAmendments(ctx, tx, order.ID, oldTotal, total, amendments)
+}
+
+// recordAmendments mencatat perubahan baris order dengan total order sebelum dan sesudahnya
+func (s *sqlStore) recordAmendments(ctx context.Context, tx *sql.Tx, orderID int, oldTotal, newTotal float64, amendments []models.OrderAmendment) error {
+	for _, a := range amendments {
+		_, err := tx.ExecContext(ctx, s.q(`
+			INSERT INTO {schema}ORDER_AMENDMENTS (order_id, order_detail_id, action, product_name, old_quantity, new_quantity, old_total, new_total, username, reason)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+			orderID, a.OrderDetailID, a.Action, a.ProductName, a.OldQuantity, a.NewQuantity,
+			oldTotal, newTotal, nullString(a.Username), nullString(a.Reason))
+		if err != nil {
+			return fmt.Errorf("failed to record order change: %w", err)
+		}
+	}
+	return nil
+}
+
+// giveBackStock mengembalikan stok dari baris yang dikurangi atau di-void,
+// paling banyak sejumlah yang benar-benar diambil order untuk produk itu
+func (s *sqlStore) giveBackStock(ctx context.Context, tx *sql.Tx, orderID, productID, quantity int) error {
+	taken, err := s.stockTaken(ctx, tx, orderID, productID)
+	if err != nil {
+		return err
+	}
+	if quantity > taken {
+		quantity = taken
+	}
+	if quantity <= 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}PRODUCTS SET stock = stock + ? WHERE id = ? AND stock IS NOT NULL"), quantity, productID); err != nil {
+		return err
+	}
+	return s.insertStockMovement(ctx, tx, models.StockMovement{
+		ProductID: productID,
+		Quantity:  quantity,
+		Reason:    models.StockReasonCancel,
+		OrderID:   &orderID,
+	})
+}
+
+// stockTaken adalah stok produk yang masih dipegang order: penjualan
+// dikurangi pengembalian dan pindahan antar order
+func (s *sqlStore) stockTaken(ctx context.Context, tx *sql.Tx, orderID, productID int) (int, error) {
+	var taken int
+	err := tx.QueryRowContext(ctx, s.q("SELECT COALESCE(-SUM(quantity), 0) FROM {schema}STOCK_MOVEMENTS WHERE order_id = ? AND product_id = ? AND reason IN (?, ?, ?)"),
+		orderID, productID, models.StockReasonSale, models.StockReasonCancel, models.StockReasonTransfer).Scan(&taken)
+	return taken, err
+}
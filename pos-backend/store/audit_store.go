@@ -0,0 +1,193 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"pos-backend/models"
+)
+
+const (
+	// auditGenesisHash adalah prev_hash baris pertama AUDIT_LOG
+	auditGenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+	// auditAppendAttempts membatasi percobaan ulang saat request lain lebih
+	// dulu menyambung rantai (prev_hash unik)
+	auditAppendAttempts = 5
+	auditListLimit      = 100
+	auditMaxListLimit   = 1000
+)
+
+const auditColumns = "id, actor, actor_role, action, method, path, entity, entity_id, before_value, after_value, status, ip, created_at, prev_hash, hash"
+
+// RecordAudit menambahkan baris ke ujung rantai AUDIT_LOG. Tidak ada
+// method untuk mengubah atau menghapus baris audit.
+func (s *sqlStore) RecordAudit(ctx context.Context, entry *models.AuditEntry) error {
+	entry.Actor = truncateAudit(entry.Actor, 100)
+	entry.Role = truncateAudit(entry.Role, 20)
+	entry.Action = truncateAudit(entry.Action, 100)
+	entry.Path = truncateAudit(entry.Path, 255)
+	entry.Entity = truncateAudit(entry.Entity, 50)
+	entry.EntityID = truncateAudit(entry.EntityID, 50)
+	entry.IP = truncateAudit(entry.IP, 64)
+	// Presisi mikrodetik dan UTC supaya nilai yang dibaca ulang dari
+	// database menghasilkan hash yang sama
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	var err error
+	for attempt := 0; attempt < auditAppendAttempts; attempt++ {
+		if err = s.appendAudit(ctx, entry); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to record audit entry: %w", err)
+}
+
+func (s *sqlStore) appendAudit(ctx context.Context, entry *models.AuditEntry) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		entry.PrevHash = auditGenesisHash
+		err := tx.QueryRowContext(ctx, s.q("SELECT hash FROM {schema}AUDIT_LOG WHERE id = (SELECT MAX(id) FROM {schema}AUDIT_LOG)")).Scan(&entry.PrevHash)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		entry.Hash = auditHash(entry)
+
+		entry.ID, err = s.dialect.insertReturningID(ctx, tx, s.q(`
+			INSERT INTO {schema}AUDIT_LOG (actor, actor_role, action, method, path, entity, entity_id, before_value, after_value, status, ip, created_at, prev_hash, hash)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+			nullString(entry.Actor), nullString(entry.Role), entry.Action, entry.Method, entry.Path, nullString(entry.Entity), nullString(entry.EntityID),
+			nullString(string(entry.Before)), nullString(string(entry.After)), entry.Status, nullString(entry.IP), entry.CreatedAt, entry.PrevHash, entry.Hash)
+		return err
+	})
+}
+
+// ListAudit mengambil log audit terbaru lebih dulu sesuai filter
+func (s *sqlStore) ListAudit(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	var where []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		where = append(where, cond)
+		args = append(args, arg)
+	}
+	if filter.Actor != "" {
+		add("actor = ?", filter.Actor)
+	}
+	if filter.Action != "" {
+		add("action = ?", filter.Action)
+	}
+	if filter.Entity != "" {
+		add("entity = ?", filter.Entity)
+	}
+	if filter.EntityID != "" {
+		add("entity_id = ?", filter.EntityID)
+	}
+	if !filter.From.IsZero() {
+		add("created_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		add("created_at < ?", filter.To.UTC())
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = auditListLimit
+	}
+	if limit > auditMaxListLimit {
+		limit = auditMaxListLimit
+	}
+
+	query := "SELECT " + auditColumns + " FROM {schema}AUDIT_LOG"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC " + s.dialect.limit(limit)
+
+	rows, err := s.db.QueryContext(ctx, s.q(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
+
+// VerifyAudit menghitung ulang rantai hash dari baris pertama. Baris yang
+// diubah, disisipkan atau dihapus di tengah membuat rantai putus; LastHash
+// bisa dicatat di luar sistem untuk mendeteksi baris terakhir yang dihapus.
+func (s *sqlStore) VerifyAudit(ctx context.Context) (*models.AuditVerification, error) {
+	rows, err := s.db.QueryContext(ctx, s.q("SELECT "+auditColumns+" FROM {schema}AUDIT_LOG ORDER BY id ASC"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := &models.AuditVerification{Valid: true}
+	prev := auditGenesisHash
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		result.Checked++
+		if entry.PrevHash != prev || auditHash(entry) != entry.Hash {
+			result.Valid = false
+			result.BrokenAt = entry.ID
+			return result, nil
+		}
+		prev = entry.Hash
+		result.LastHash = entry.Hash
+	}
+	return result, rows.Err()
+}
+
+func scanAuditEntry(row rowScanner) (*models.AuditEntry, error) {
+	var e models.AuditEntry
+	var actor, role, entity, entityID, before, after, ip sql.NullString
+	if err := row.Scan(&e.ID, &actor, &role, &e.Action, &e.Method, &e.Path, &entity, &entityID, &before, &after,
+		&e.Status, &ip, &e.CreatedAt, &e.PrevHash, &e.Hash); err != nil {
+		return nil, err
+	}
+	e.Actor, e.Role, e.Entity, e.EntityID, e.IP = actor.String, role.String, entity.String, entityID.String, ip.String
+	if before.String != "" {
+		e.Before = []byte(before.String)
+	}
+	if after.String != "" {
+		e.After = []byte(after.String)
+	}
+	e.CreatedAt = e.CreatedAt.UTC()
+	return &e, nil
+}
+
+// auditHash adalah SHA-256 dari prev_hash dan semua kolom baris. Setiap
+// nilai diawali panjangnya supaya batas antar kolom tidak ambigu.
+func auditHash(e *models.AuditEntry) string {
+	h := sha256.New()
+	for _, v := range []string{
+		e.PrevHash, e.Actor, e.Role, e.Action, e.Method, e.Path, e.Entity, e.EntityID,
+		string(e.Before), string(e.After), strconv.Itoa(e.Status), e.IP,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	} {
+		io.WriteString(h, strconv.Itoa(len(v))+":"+v)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func truncateAudit(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
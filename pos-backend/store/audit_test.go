@@ -0,0 +1,51 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+
+	"pos-backend/models"
+)
+
+func TestVerifyAuditTampered(t *testing.T) {
+	tests := []struct {
+		name     string
+		tamper   string
+		brokenAt int
+	}{
+		{"untouched", "", 0},
+		{"changed value", `UPDATE AUDIT_LOG SET after_value = '{"price":3}' WHERE id = 3`, 3},
+		{"changed actor", `UPDATE AUDIT_LOG SET actor = 'kasir' WHERE id = 1`, 1},
+		{"changed status and hash", `UPDATE AUDIT_LOG SET status = 500, hash = prev_hash WHERE id = 4`, 4},
+		{"deleted row", `DELETE FROM AUDIT_LOG WHERE id = 2`, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ctx := openTestStore(t)
+			for i := 0; i < 5; i++ {
+				entry := &models.AuditEntry{
+					Actor: "admin", Role: "admin", Action: "update-product", Method: "PUT", Path: "/update-product/1",
+					Entity: "product", EntityID: "1", Before: json.RawMessage(`{"price":1}`), After: json.RawMessage(`{"price":2}`),
+					Status: 200, IP: "127.0.0.1",
+				}
+				if err := st.Audit.RecordAudit(ctx, entry); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.tamper != "" {
+				if _, err := st.DB.ExecContext(ctx, tt.tamper); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result, err := st.Audit.VerifyAudit(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Valid != (tt.brokenAt == 0) || result.BrokenAt != tt.brokenAt {
+				t.Errorf("valid %v broken at %d, want broken at %d", result.Valid, result.BrokenAt, tt.brokenAt)
+			}
+		})
+	}
+}
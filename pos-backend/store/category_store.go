@@ -0,0 +1,165 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"pos-backend/models"
+)
+
+func (s *sqlStore) ListCategories(ctx context.Context) ([]models.Category, error) {
+	rows, err := s.db.QueryContext(ctx, s.q(`
+		SELECT c.id, c.name, c.sort_order, COUNT(p.id)
+		FROM {schema}CATEGORIES c
+		LEFT JOIN {schema}PRODUCTS p ON p.category_id = c.id
+		GROUP BY c.id, c.name, c.sort_order
+		ORDER BY c.sort_order ASC, c.id ASC`))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []models.Category{}
+	for rows.Next() {
+		var c models.Category
+		if err := rows.Scan(&c.ID, &c.Name, &c.SortOrder, &c.ProductCount); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+func (s *sqlStore) GetCategory(ctx context.Context, id int) (*models.Category, error) {
+	var c models.Category
+	err := s.db.QueryRowContext(ctx, s.q(`
+		SELECT c.id, c.name, c.sort_order, (SELECT COUNT(*) FROM {schema}PRODUCTS p WHERE p.category_id = c.id)
+		FROM {schema}CATEGORIES c WHERE c.id = ?`), id).Scan(&c.ID, &c.Name, &c.SortOrder, &c.ProductCount)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return &c, err
+}
+
+func (s *sqlStore) CreateCategory(ctx context.Context, category *models.Category) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return s.insertCategory(ctx, tx, category)
+	})
+}
+
+// insertCategory menyimpan kategori baru. SortOrder 0 berarti taruh di urutan terakhir.
+func (s *sqlStore) insertCategory(ctx context.Context, tx *sql.Tx, category *models.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if err := s.checkCategoryName(ctx, tx, category.Name, 0); err != nil {
+		return err
+	}
+
+	if category.SortOrder == 0 {
+		if err := tx.QueryRowContext(ctx, s.q("SELECT COALESCE(MAX(sort_order), 0) + 1 FROM {schema}CATEGORIES")).Scan(&category.SortOrder); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	category.ID, err = s.dialect.insertReturningID(ctx, tx,
+		s.q("INSERT INTO {schema}CATEGORIES (name, sort_order) VALUES (?, ?)"), category.Name, category.SortOrder)
+	return err
+}
+
+func (s *sqlStore) UpdateCategory(ctx context.Context, category *models.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if err := s.checkCategoryName(ctx, s.db, category.Name, category.ID); err != nil {
+		return err
+	}
+	return expectRows(s.db.ExecContext(ctx, s.q("UPDATE {schema}CATEGORIES SET name = ?, sort_order = ? WHERE id = ?"),
+		category.Name, category.SortOrder, category.ID))
+}
+
+// DeleteCategory menghapus kategori; produknya tetap ada tanpa kategori,
+// promo dan aturan pajak kategori ikut dihapus
+func (s *sqlStore) DeleteCategory(ctx context.Context, id int) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}PRODUCTS SET category_id = NULL WHERE category_id = ?"), id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}PROMOTIONS WHERE category_id = ?"), id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}TAX_RULES WHERE category_id = ?"), id); err != nil {
+			return err
+		}
+		return expectRows(tx.ExecContext(ctx, s.q("DELETE FROM {schema}CATEGORIES WHERE id = ?"), id))
+	})
+}
+
+// ReorderCategories memberi sort_order 1, 2, 3, ... sesuai urutan ids
+func (s *sqlStore) ReorderCategories(ctx context.Context, ids []int) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		for i, id := range ids {
+			err := expectRows(tx.ExecContext(ctx, s.q("UPDATE {schema}CATEGORIES SET sort_order = ? WHERE id = ?"), i+1, id))
+			if err == ErrNotFound {
+				return fmt.Errorf("%w: category %d", ErrNotFound, id)
+			} else if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// checkCategoryName menolak nama kosong atau nama yang sudah dipakai kategori lain
+func (s *sqlStore) checkCategoryName(ctx context.Context, q queryer, name string, categoryID int) error {
+	if name == "" {
+		return fmt.Errorf("%w: category name is required", ErrInvalid)
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("%w: category name is longer than 100 characters", ErrInvalid)
+	}
+
+	var id int
+	err := q.QueryRowContext(ctx, s.q("SELECT id FROM {schema}CATEGORIES WHERE LOWER(name) = LOWER(?)"), name).Scan(&id)
+	if err == sql.ErrNoRows || (err == nil && id == categoryID) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: category %s already exists", ErrConflict, name)
+}
+
+// resolveCategory mengisi CategoryID dan Category produk. CategoryID harus
+// merujuk kategori yang ada; jika hanya nama yang diisi, kategori dicari
+// berdasarkan nama dan dibuat jika belum ada.
+func (s *sqlStore) resolveCategory(ctx context.Context, q queryer, product *models.Product) error {
+	if product.CategoryID != nil {
+		err := q.QueryRowContext(ctx, s.q("SELECT name FROM {schema}CATEGORIES WHERE id = ?"), *product.CategoryID).Scan(&product.Category)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: category %d does not exist", ErrInvalid, *product.CategoryID)
+		}
+		return err
+	}
+
+	name := strings.TrimSpace(product.Category)
+	if name == "" {
+		return nil
+	}
+
+	var id int
+	err := q.QueryRowContext(ctx, s.q("SELECT id, name FROM {schema}CATEGORIES WHERE LOWER(name) = LOWER(?)"), name).Scan(&id, &product.Category)
+	if err == sql.ErrNoRows {
+		tx, ok := q.(*sql.Tx)
+		if !ok {
+			return fmt.Errorf("%w: category %s does not exist", ErrInvalid, name)
+		}
+		category := models.Category{Name: name}
+		if err := s.insertCategory(ctx, tx, &category); err != nil {
+			return err
+		}
+		id, product.Category = category.ID, category.Name
+	} else if err != nil {
+		return err
+	}
+	product.CategoryID = &id
+	return nil
+}
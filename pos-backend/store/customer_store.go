@@ -0,0 +1,499 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+
+	"pos-backend/models"
+)
+
+// customerListLimit membatasi hasil pencarian pelanggan di layar kasir
+const customerListLimit = 100
+
+const customerColumns = "id, name, phone, email, points, created_at"
+
+func scanCustomer(row rowScanner) (*models.Customer, error) {
+	var c models.Customer
+	var phone, email sql.NullString
+	if err := row.Scan(&c.ID, &c.Name, &phone, &email, &c.Points, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	c.Phone = phone.String
+	c.Email = email.String
+	return &c, nil
+}
+
+func (s *sqlStore) ListCustomers(ctx context.Context, query string) ([]models.Customer, error) {
+	sqlQuery := "SELECT " + customerColumns + " FROM {schema}CUSTOMERS"
+	var args []interface{}
+	if query = strings.TrimSpace(query); query != "" {
+		sqlQuery += " WHERE LOWER(name) LIKE ? OR phone LIKE ?"
+		like := "%" + strings.ToLower(query) + "%"
+		args = append(args, like, like)
+	}
+	sqlQuery += " ORDER BY name ASC, id ASC " + s.dialect.limit(customerListLimit)
+
+	rows, err := s.db.QueryContext(ctx, s.q(sqlQuery), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := []models.Customer{}
+	for rows.Next() {
+		c, err := scanCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, *c)
+	}
+	return customers, rows.Err()
+}
+
+func (s *sqlStore) GetCustomer(ctx context.Context, id int) (*models.Customer, error) {
+	c, err := scanCustomer(s.db.QueryRowContext(ctx, s.q("SELECT "+customerColumns+" FROM {schema}CUSTOMERS WHERE id = ?"), id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return c, err
+}
+
+func (s *sqlStore) CreateCustomer(ctx context.Context, customer *models.Customer) error {
+	if err := s.checkCustomer(ctx, s.db, customer); err != nil {
+		return err
+	}
+	var err error
+	customer.Points = 0
+	customer.ID, err = s.dialect.insertReturningID(ctx, s.db,
+		s.q("INSERT INTO {schema}CUSTOMERS (name, phone, email) VALUES (?, ?, ?)"),
+		customer.Name, nullString(customer.Phone), nullString(customer.Email))
+	if err != nil {
+		return err
+	}
+	created, err := s.GetCustomer(ctx, customer.ID)
+	if err != nil {
+		return err
+	}
+	*customer = *created
+	return nil
+}
+
+// UpdateCustomer mengganti data kontak pelanggan; saldo poin hanya berubah
+// lewat order atau AdjustPoints
+func (s *sqlStore) UpdateCustomer(ctx context.Context, customer *models.Customer) error {
+	if err := s.checkCustomer(ctx, s.db, customer); err != nil {
+		return err
+	}
+	err := expectRows(s.db.ExecContext(ctx, s.q("UPDATE {schema}CUSTOMERS SET name = ?, phone = ?, email = ? WHERE id = ?"),
+		customer.Name, nullString(customer.Phone), nullString(customer.Email), customer.ID))
+	if err != nil {
+		return err
+	}
+	updated, err := s.GetCustomer(ctx, customer.ID)
+	if err != nil {
+		return err
+	}
+	*customer = *updated
+	return nil
+}
+
+// DeleteCustomer menghapus pelanggan dan riwayat poinnya; order lama tetap
+// ada tanpa pelanggan
+func (s *sqlStore) DeleteCustomer(ctx context.Context, id int) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}ORDERS SET customer_id = NULL WHERE customer_id = ?"), id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}POINT_MOVEMENTS WHERE customer_id = ?"), id); err != nil {
+			return err
+		}
+		return expectRows(tx.ExecContext(ctx, s.q("DELETE FROM {schema}CUSTOMERS WHERE id = ?"), id))
+	})
+}
+
+// checkCustomer merapikan input lalu menolak nama kosong, email tidak valid
+// atau nomor telepon yang sudah dipakai pelanggan lain
+func (s *sqlStore) checkCustomer(ctx context.Context, q queryer, customer *models.Customer) error {
+	customer.Name = strings.TrimSpace(customer.Name)
+	customer.Phone = strings.TrimSpace(customer.Phone)
+	customer.Email = strings.TrimSpace(customer.Email)
+	if customer.Name == "" {
+		return fmt.Errorf("%w: customer name is required", ErrInvalid)
+	}
+	if len(customer.Name) > 100 {
+		return fmt.Errorf("%w: customer name is longer than 100 characters", ErrInvalid)
+	}
+	if len(customer.Phone) > 30 {
+		return fmt.Errorf("%w: phone is longer than 30 characters", ErrInvalid)
+	}
+	if customer.Email != "" && (len(customer.Email) > 100 || !strings.Contains(customer.Email, "@")) {
+		return fmt.Errorf("%w: invalid email %q", ErrInvalid, customer.Email)
+	}
+	if customer.Phone == "" {
+		return nil
+	}
+
+	var id int
+	err := q.QueryRowContext(ctx, s.q("SELECT id FROM {schema}CUSTOMERS WHERE phone = ?"), customer.Phone).Scan(&id)
+	if err == sql.ErrNoRows || (err == nil && id == customer.ID) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: phone %s is already registered", ErrConflict, customer.Phone)
+}
+
+// CustomerHistory mengambil order pelanggan beserta barisnya dan riwayat
+// poin, terbaru lebih dulu. TotalSpent adalah total order selesai
+// dikurangi refund.
+func (s *sqlStore) CustomerHistory(ctx context.Context, id int) (*models.CustomerHistory, error) {
+	customer, err := s.GetCustomer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	history := &models.CustomerHistory{Customer: *customer, Orders: []models.Order{}}
+
+	rows, err := s.db.QueryContext(ctx, s.q("SELECT "+orderColumns+" FROM {schema}ORDERS WHERE customer_id = ? ORDER BY id DESC"), id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		history.Orders = append(history.Orders, *order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := s.loadOrderLines(ctx, history.Orders); err != nil {
+		return nil, err
+	}
+	for _, o := range history.Orders {
+		if o.Status != models.OrderStatusCompleted && o.Status != models.OrderStatusRefunded {
+			continue
+		}
+		history.OrderCount++
+		if o.TotalPrice != nil {
+			history.TotalSpent += *o.TotalPrice
+		}
+		for _, r := range o.Refunds {
+			history.TotalSpent -= r.Amount
+		}
+	}
+	history.TotalSpent = roundMoney(history.TotalSpent)
+
+	history.Points, err = s.pointMovements(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (s *sqlStore) pointMovements(ctx context.Context, customerID int) ([]models.PointMovement, error) {
+	rows, err := s.db.QueryContext(ctx, s.q("SELECT id, customer_id, points, reason, order_id, note, username, created_at FROM {schema}POINT_MOVEMENTS WHERE customer_id = ? ORDER BY id DESC"), customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := []models.PointMovement{}
+	for rows.Next() {
+		var m models.PointMovement
+		var orderID sql.NullInt64
+		var note, username sql.NullString
+		if err := rows.Scan(&m.ID, &m.CustomerID, &m.Points, &m.Reason, &orderID, &note, &username, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		if orderID.Valid {
+			id := int(orderID.Int64)
+			m.OrderID = &id
+		}
+		m.Note = note.String
+		m.Username = username.String
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+// AdjustPoints menambah atau mengurangi poin pelanggan secara manual.
+// Saldo tidak boleh menjadi negatif.
+func (s *sqlStore) AdjustPoints(ctx context.Context, adj models.PointAdjustment, username string) (*models.PointMovement, error) {
+	if adj.Points == 0 {
+		return nil, fmt.Errorf("%w: points must not be zero", ErrInvalid)
+	}
+	movement := models.PointMovement{
+		CustomerID: adj.CustomerID,
+		Points:     adj.Points,
+		Reason:     models.PointsAdjust,
+		Note:       strings.TrimSpace(adj.Note),
+		Username:   username,
+	}
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := s.customerPoints(ctx, tx, adj.CustomerID); err != nil {
+			return err
+		}
+		return s.movePoints(ctx, tx, movement)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &movement, nil
+}
+
+// customerPoints mengambil saldo poin; pelanggan yang tidak ada menghasilkan ErrInvalid
+func (s *sqlStore) customerPoints(ctx context.Context, q queryer, customerID int) (int, error) {
+	var points int
+	err := q.QueryRowContext(ctx, s.q("SELECT points FROM {schema}CUSTOMERS WHERE id = ?"), customerID).Scan(&points)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%w: customer %d does not exist", ErrInvalid, customerID)
+	}
+	return points, err
+}
+
+// movePoints mengubah saldo poin pelanggan lalu mencatat pergerakannya.
+// Pengurangan dicek ulang di UPDATE supaya saldo tidak pernah negatif
+// walau dua kasir menukar poin bersamaan.
+func (s *sqlStore) movePoints(ctx context.Context, tx *sql.Tx, m models.PointMovement) error {
+	var err error
+	if m.Points < 0 {
+		err = expectRows(tx.ExecContext(ctx, s.q("UPDATE {schema}CUSTOMERS SET points = points + ? WHERE id = ? AND points >= ?"), m.Points, m.CustomerID, -m.Points))
+		if err == ErrNotFound {
+			return fmt.Errorf("%w: customer %d does not have %d points", ErrInvalid, m.CustomerID, -m.Points)
+		}
+	} else {
+		err = expectRows(tx.ExecContext(ctx, s.q("UPDATE {schema}CUSTOMERS SET points = points + ? WHERE id = ?"), m.Points, m.CustomerID))
+		if err == ErrNotFound {
+			return fmt.Errorf("%w: customer %d does not exist", ErrInvalid, m.CustomerID)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, s.q("INSERT INTO {schema}POINT_MOVEMENTS (customer_id, points, reason, order_id, note, username) VALUES (?, ?, ?, ?, ?, ?)"),
+		m.CustomerID, m.Points, m.Reason, nullInt(m.OrderID), nullString(m.Note), nullString(m.Username))
+	return err
+}
+
+// redeemDiscount menghitung diskon dari poin yang ditukar order. Poin baru
+// dipotong saat order disimpan.
+func (s *sqlStore) redeemDiscount(ctx context.Context, tx *sql.Tx, order *models.Order, net float64) (float64, error) {
+	if order.RedeemPoints < 0 {
+		return 0, fmt.Errorf("%w: redeem_points must not be negative", ErrInvalid)
+	}
+	if order.CustomerID == nil {
+		return 0, fmt.Errorf("%w: redeeming points requires a customer", ErrInvalid)
+	}
+	if s.pointValue <= 0 {
+		return 0, fmt.Errorf("%w: points cannot be redeemed", ErrInvalid)
+	}
+	// Poin order yang sudah tersimpan sudah dipotong dari saldo
+	if order.ID == 0 {
+		points, err := s.customerPoints(ctx, tx, *order.CustomerID)
+		if err != nil {
+			return 0, err
+		}
+		if order.RedeemPoints > points {
+			return 0, fmt.Errorf("%w: customer has %d points, %d requested", ErrInvalid, points, order.RedeemPoints)
+		}
+	}
+	amount := roundMoney(float64(order.RedeemPoints) * s.pointValue)
+	if amount > net && !moneyEqual(amount, net) {
+		return 0, fmt.Errorf("%w: %d points are worth %.2f, more than the order total %.2f", ErrInvalid, order.RedeemPoints, amount, net)
+	}
+	return amount, nil
+}
+
+// payWithPoints memotong poin pelanggan order untuk tender poin dan
+// mengembalikan jumlah poin yang dipakai, dibulatkan ke atas
+func (s *sqlStore) payWithPoints(ctx context.Context, tx *sql.Tx, orderID int, customerID *int, p models.Payment) (int, error) {
+	if customerID == nil {
+		return 0, fmt.Errorf("%w: order %d has no customer to pay with points", ErrInvalid, orderID)
+	}
+	if s.pointValue <= 0 {
+		return 0, fmt.Errorf("%w: points cannot be redeemed", ErrInvalid)
+	}
+	points := int(math.Ceil(p.Amount/s.pointValue - 1e-9))
+	err := s.movePoints(ctx, tx, models.PointMovement{
+		CustomerID: *customerID,
+		Points:     -points,
+		Reason:     models.PointsRedeem,
+		OrderID:    &orderID,
+		Note:       "payment",
+		Username:   p.Username,
+	})
+	return points, err
+}
+
+// earnPoints memberi poin untuk order yang baru selesai. Bagian yang dibayar
+// dengan poin tidak menghasilkan poin baru.
+func (s *sqlStore) earnPoints(ctx context.Context, tx *sql.Tx, orderID int, username string) error {
+	if s.spendPerPoint <= 0 {
+		return nil
+	}
+	var customerID sql.NullInt64
+	var total sql.NullFloat64
+	err := tx.QueryRowContext(ctx, s.q("SELECT customer_id, total_price FROM {schema}ORDERS WHERE id = ?"), orderID).Scan(&customerID, &total)
+	if err != nil || !customerID.Valid {
+		return err
+	}
+
+	var paidWithPoints float64
+	err = tx.QueryRowContext(ctx, s.q("SELECT COALESCE(SUM(amount), 0) FROM {schema}PAYMENTS WHERE order_id = ? AND method = ?"),
+		orderID, models.PaymentPoints).Scan(&paidWithPoints)
+	if err != nil {
+		return err
+	}
+	points := int(math.Floor((total.Float64-paidWithPoints)/s.spendPerPoint + 1e-9))
+	if points <= 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}ORDERS SET points_earned = ? WHERE id = ?"), points, orderID); err != nil {
+		return err
+	}
+	return s.movePoints(ctx, tx, models.PointMovement{
+		CustomerID: int(customerID.Int64),
+		Points:     points,
+		Reason:     models.PointsEarn,
+		OrderID:    &orderID,
+		Username:   username,
+	})
+}
+
+// returnRedeemedPoints mengembalikan semua poin yang ditukar order dan belum
+// dikembalikan, dipakai saat order dibatalkan
+func (s *sqlStore) returnRedeemedPoints(ctx context.Context, tx *sql.Tx, orderID int, username, note string) error {
+	rows, err := tx.QueryContext(ctx, s.q(`
+		SELECT customer_id, SUM(points)
+		FROM {schema}POINT_MOVEMENTS
+		WHERE order_id = ? AND reason IN (?, ?)
+		GROUP BY customer_id`), orderID, models.PointsRedeem, models.PointsReturn)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var movements []models.PointMovement
+	for rows.Next() {
+		var customerID, points int
+		if err := rows.Scan(&customerID, &points); err != nil {
+			return err
+		}
+		if points < 0 {
+			movements = append(movements, models.PointMovement{
+				CustomerID: customerID,
+				Points:     -points,
+				Reason:     models.PointsReturn,
+				OrderID:    &orderID,
+				Note:       note,
+				Username:   username,
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	for _, m := range movements {
+		if err := s.movePoints(ctx, tx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refundPoints menyesuaikan poin setelah refund: tender poin yang
+// di-refund dikembalikan sebagai poin, poin yang didapat order ditarik
+// sebanding dengan nilai yang sudah di-refund, dan poin yang ditukar sebagai
+// diskon dikembalikan saat order di-refund penuh. Penarikan dibatasi saldo
+// pelanggan karena poinnya mungkin sudah terpakai.
+func (s *sqlStore) refundPoints(ctx context.Context, tx *sql.Tx, refund *models.Refund, refunded, total float64, full bool) error {
+	var customerID sql.NullInt64
+	var earned, redeemed int
+	err := tx.QueryRowContext(ctx, s.q("SELECT customer_id, points_earned, points_redeemed FROM {schema}ORDERS WHERE id = ?"),
+		refund.OrderID).Scan(&customerID, &earned, &redeemed)
+	if err != nil {
+		return err
+	}
+	orderID := refund.OrderID
+
+	if refund.Method == models.PaymentPoints {
+		if !customerID.Valid {
+			return fmt.Errorf("%w: order %d has no customer to refund points to", ErrInvalid, orderID)
+		}
+		if s.pointValue <= 0 {
+			return fmt.Errorf("%w: points cannot be refunded", ErrInvalid)
+		}
+		err := s.movePoints(ctx, tx, models.PointMovement{
+			CustomerID: int(customerID.Int64),
+			Points:     int(math.Round(refund.Amount / s.pointValue)),
+			Reason:     models.PointsReturn,
+			OrderID:    &orderID,
+			Note:       "refund",
+			Username:   refund.Username,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if !customerID.Valid {
+		return nil
+	}
+	customer := int(customerID.Int64)
+
+	if full && redeemed > 0 {
+		err := s.movePoints(ctx, tx, models.PointMovement{
+			CustomerID: customer,
+			Points:     redeemed,
+			Reason:     models.PointsReturn,
+			OrderID:    &orderID,
+			Note:       "order refunded",
+			Username:   refund.Username,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if earned <= 0 || total <= 0 {
+		return nil
+	}
+	target := earned
+	if !full {
+		target = int(math.Floor(float64(earned)*refunded/total + 1e-9))
+	}
+	var revoked int
+	err = tx.QueryRowContext(ctx, s.q("SELECT COALESCE(-SUM(points), 0) FROM {schema}POINT_MOVEMENTS WHERE order_id = ? AND reason = ?"),
+		orderID, models.PointsRevoke).Scan(&revoked)
+	if err != nil {
+		return err
+	}
+	points := target - revoked
+	balance, err := s.customerPoints(ctx, tx, customer)
+	if err != nil {
+		return err
+	}
+	if points > balance {
+		points = balance
+	}
+	if points <= 0 {
+		return nil
+	}
+	return s.movePoints(ctx, tx, models.PointMovement{
+		CustomerID: customer,
+		Points:     -points,
+		Reason:     models.PointsRevoke,
+		OrderID:    &orderID,
+		Username:   refund.Username,
+	})
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// Dialect menampung perbedaan SQL antar database
+type Dialect interface {
+	Name() string
+	driverName() string
+	// connString melengkapi DSN dengan opsi koneksi yang wajib untuk dialect
+	connString(dsn string) string
+	// placeholder mengembalikan bind variable ke-n (mulai dari 1)
+	placeholder(n int) string
+	// insertReturningID menjalankan INSERT dan mengembalikan kolom id yang baru dibuat
+	insertReturningID(ctx context.Context, q queryer, query string, args ...interface{}) (int, error)
+	// limit mengembalikan klausa untuk membatasi jumlah baris
+	limit(n int) string
+	// tableExists mengecek apakah tabel sudah ada di schema
+	tableExists(ctx context.Context, q queryer, schema, table string) (bool, error)
+	// transactionalDDL menandakan DDL bisa di-rollback di dalam transaksi
+	transactionalDDL() bool
+	// forUpdate mengembalikan klausa untuk mengunci baris yang dibaca di dalam transaksi
+	forUpdate() string
+	// dateBucket mengembalikan ekspresi 'YYYY-MM-DD' untuk awal hari, minggu
+	// (Senin) atau bulan dari kolom waktu
+	dateBucket(column, granularity string) string
+}
+
+// queryer diimplementasikan oleh *sql.DB dan *sql.Tx
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// rebind mengganti {schema} dengan prefix schema dan setiap ? dengan
+// bind variable milik dialect
+func rebind(d Dialect, schema, query string) string {
+	query = withSchema(schema, query)
+
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			b.WriteString(d.placeholder(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// withSchema mengganti {schema} dengan prefix schema, atau menghapusnya jika kosong
+func withSchema(schema, query string) string {
+	if schema == "" {
+		return strings.ReplaceAll(query, "{schema}", "")
+	}
+	return strings.ReplaceAll(query, "{schema}", schema+".")
+}
+
+func itoa(n int) string {
+	return strconv.Itoa(n)
+}
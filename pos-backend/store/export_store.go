@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"pos-backend/models"
+)
+
+// exportWhere menyusun filter tanggal dan status untuk query ekspor
+func exportWhere(filter models.ExportFilter) (string, []interface{}) {
+	where := "o.created_at >= ? AND o.created_at < ?"
+	args := []interface{}{filter.From, filter.To}
+	if len(filter.Statuses) > 0 {
+		where += " AND o.status IN (?" + strings.Repeat(", ?", len(filter.Statuses)-1) + ")"
+		for _, status := range filter.Statuses {
+			args = append(args, status)
+		}
+	}
+	return where, args
+}
+
+// ExportOrders memanggil fn untuk setiap order secara berurutan tanpa
+// memuat semua baris ke memori
+func (s *sqlStore) ExportOrders(ctx context.Context, filter models.ExportFilter, fn func(models.OrderExportRow) error) error {
+	where, args := exportWhere(filter)
+	rows, err := s.db.QueryContext(ctx, s.q(`
+		SELECT o.id, o.created_at, o.status, o.order_type, o.table_name, o.subtotal, o.discount, o.service_charge, o.tax, o.total_price,
+			(SELECT COALESCE(SUM(p.amount), 0) FROM {schema}PAYMENTS p WHERE p.order_id = o.id),
+			(SELECT COALESCE(SUM(r.amount), 0) FROM {schema}REFUNDS r WHERE r.order_id = o.id)
+		FROM {schema}ORDERS o
+		WHERE `+where+`
+		ORDER BY o.id ASC`), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.OrderExportRow
+		var subtotal, total sql.NullFloat64
+		var table sql.NullString
+		if err := rows.Scan(&row.ID, &row.CreatedAt, &row.Status, &row.Type, &table, &subtotal, &row.Discount, &row.ServiceCharge, &row.Tax, &total, &row.AmountPaid, &row.Refunded); err != nil {
+			return err
+		}
+		row.Table = table.String
+		row.TotalPrice = total.Float64
+		row.Subtotal = total.Float64
+		if subtotal.Valid {
+			row.Subtotal = subtotal.Float64
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ExportOrderLines memanggil fn untuk setiap baris ORDER_DETAILS secara berurutan
+func (s *sqlStore) ExportOrderLines(ctx context.Context, filter models.ExportFilter, fn func(models.OrderLineExportRow) error) error {
+	where, args := exportWhere(filter)
+	rows, err := s.db.QueryContext(ctx, s.q(`
+		SELECT o.created_at, o.status, d.id, d.order_id, d.product_id, d.product_name, d.unit_price, d.quantity, d.total_price, d.discount, d.service_charge, d.tax,
+			(SELECT COALESCE(SUM(ri.quantity), 0) FROM {schema}REFUND_ITEMS ri WHERE ri.order_detail_id = d.id)
+		FROM {schema}ORDER_DETAILS d
+		JOIN {schema}ORDERS o ON o.id = d.order_id
+		WHERE `+where+`
+		ORDER BY d.order_id ASC, d.id ASC`), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.OrderLineExportRow
+		var productID sql.NullInt64
+		var unitPrice, totalPrice sql.NullFloat64
+		d := &row.Detail
+		if err := rows.Scan(&row.CreatedAt, &row.Status, &d.ID, &d.OrderID, &productID, &d.ProductName, &unitPrice, &d.Quantity, &totalPrice, &d.Discount, &d.ServiceCharge, &d.Tax, &row.Refunded); err != nil {
+			return err
+		}
+		row.OrderID = d.OrderID
+		d.ProductID = int(productID.Int64)
+		d.UnitPrice = unitPrice.Float64
+		d.TotalPrice = totalPrice.Float64
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"pos-backend/models"
+)
+
+// ImportProducts menyimpan setiap baris dengan upsert: produk dicari lewat
+// SKU, lalu lewat nama jika produk itu belum punya SKU lain. Baris yang
+// gagal dilewati tanpa membatalkan baris lain. Pada dry run semua perubahan
+// di-rollback sehingga laporan tetap akurat tanpa menyimpan apa pun.
+func (s *sqlStore) ImportProducts(ctx context.Context, rows []models.ProductImportRow, dryRun bool) ([]models.ProductImportResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]models.ProductImportResult, len(rows))
+	seenSKU := map[string]int{}
+	seenName := map[string]int{}
+	for i, row := range rows {
+		p := row.Product
+		res := models.ProductImportResult{Line: row.Line, Name: p.Name, SKU: p.SKU}
+
+		// Baris ganda di file yang sama hampir pasti salah ketik
+		name := strings.ToLower(p.Name)
+		if line, ok := seenSKU[p.SKU]; ok && p.SKU != "" {
+			res.Action, res.Error = models.ImportFailed, "duplicate SKU, first seen on line "+itoa(line)
+		} else if line, ok := seenName[name]; ok {
+			res.Action, res.Error = models.ImportFailed, "duplicate name, first seen on line "+itoa(line)
+		} else {
+			if p.SKU != "" {
+				seenSKU[p.SKU] = row.Line
+			}
+			seenName[name] = row.Line
+			res.Action, res.ProductID, err = s.upsertProduct(ctx, tx, &p)
+			if errors.Is(err, ErrInvalid) || errors.Is(err, ErrConflict) {
+				res.Action, res.Error = models.ImportFailed, err.Error()
+			} else if err != nil {
+				return nil, err
+			}
+		}
+
+		if dryRun && res.Action == models.ImportCreated {
+			res.ProductID = 0
+		}
+		results[i] = res
+	}
+
+	if dryRun {
+		return results, nil
+	}
+	return results, tx.Commit()
+}
+
+// upsertProduct memperbarui produk yang cocok atau membuat produk baru.
+// Stok dan batas stok produk lama tidak diubah.
+func (s *sqlStore) upsertProduct(ctx context.Context, tx *sql.Tx, p *models.Product) (string, int, error) {
+	var existing *models.Product
+	var err error
+	if p.SKU != "" {
+		existing, err = scanProduct(tx.QueryRowContext(ctx, s.q(productSelect+" WHERE p.sku = ?"), p.SKU))
+	}
+	if p.SKU == "" || err == sql.ErrNoRows {
+		// Produk bernama sama dengan SKU lain dianggap produk berbeda
+		where := " WHERE LOWER(p.name) = LOWER(?)"
+		if p.SKU != "" {
+			where += " AND p.sku IS NULL"
+		}
+		existing, err = scanProduct(tx.QueryRowContext(ctx, s.q(productSelect+where+" ORDER BY p.id ASC "+s.dialect.limit(1)), p.Name))
+	}
+
+	if err == sql.ErrNoRows {
+		id, err := s.insertProduct(ctx, tx, p)
+		return models.ImportCreated, id, err
+	} else if err != nil {
+		return "", 0, err
+	}
+
+	p.ID = existing.ID
+	p.LowStockThreshold = existing.LowStockThreshold
+	return models.ImportUpdated, p.ID, s.updateProduct(ctx, tx, p)
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"pos-backend/models"
+)
+
+// SetItemPrepared menandai baris order sudah atau belum disiapkan dapur.
+// Jika semua baris sudah siap, order On Progress otomatis menjadi Ready.
+func (s *sqlStore) SetItemPrepared(ctx context.Context, detailID int, prepared bool, username string) (*models.OrderDetail, error) {
+	var detail *models.OrderDetail
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var orderID int
+		err := tx.QueryRowContext(ctx, s.q("SELECT order_id FROM {schema}ORDER_DETAILS WHERE id = ?"), detailID).Scan(&orderID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: order detail %d", ErrNotFound, detailID)
+		} else if err != nil {
+			return err
+		}
+
+		var status string
+		err = tx.QueryRowContext(ctx, s.q("SELECT status FROM {schema}ORDERS WHERE id = ? "+s.dialect.forUpdate()), orderID).Scan(&status)
+		if err != nil {
+			return err
+		}
+		if status != models.OrderStatusOnProgress && status != models.OrderStatusReady {
+			return fmt.Errorf("%w: order %d is %s", ErrConflict, orderID, status)
+		}
+
+		if prepared {
+			_, err = tx.ExecContext(ctx, s.q("UPDATE {schema}ORDER_DETAILS SET prepared_at = ?, prepared_by = ? WHERE id = ?"), time.Now(), nullString(username), detailID)
+		} else {
+			_, err = tx.ExecContext(ctx, s.q("UPDATE {schema}ORDER_DETAILS SET prepared_at = NULL, prepared_by = NULL WHERE id = ?"), detailID)
+		}
+		if err != nil {
+			return err
+		}
+
+		detail, err = scanOrderDetail(tx.QueryRowContext(ctx, s.q("SELECT "+detailColumns+" FROM {schema}ORDER_DETAILS WHERE id = ?"), detailID))
+		if err != nil {
+			return err
+		}
+		modifiers, err := s.modifiersByDetail(ctx, tx, []int{detailID})
+		if err != nil {
+			return err
+		}
+		detail.Modifiers = modifiers[detailID]
+		if !prepared || status != models.OrderStatusOnProgress {
+			return nil
+		}
+
+		var waiting int
+		err = tx.QueryRowContext(ctx, s.q("SELECT COUNT(*) FROM {schema}ORDER_DETAILS WHERE order_id = ? AND prepared_at IS NULL"), orderID).Scan(&waiting)
+		if err != nil || waiting > 0 {
+			return err
+		}
+		_, err = s.transitionOrder(ctx, tx, orderID, models.OrderStatusReady, username, "all items prepared")
+		return err
+	})
+	return detail, err
+}
@@ -0,0 +1,286 @@
+package store
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migration SQL per dialect, dengan nama file NNNN_nama.up.sql dan NNNN_nama.down.sql.
+// {schema} diganti dengan prefix schema seperti pada query biasa.
+//
+//go:embed migrations
+var migrationFS embed.FS
+
+const migrationsTable = "SCHEMA_MIGRATIONS"
+
+// Migration adalah satu versi skema beserta SQL up dan down-nya
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus menunjukkan apakah sebuah migration sudah dijalankan
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// Migrations mengembalikan migration untuk dialect store, urut berdasarkan versi
+func (s *Store) Migrations() ([]Migration, error) {
+	dir := path.Join("migrations", s.Dialect.Name())
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		sep := strings.Index(base, "_")
+		if sep < 0 {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+		version, err := strconv.Atoi(base[:sep])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", name, err)
+		}
+
+		body, err := fs.ReadFile(migrationFS, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: base[sep+1:]}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrate menjalankan semua migration yang belum diterapkan dan
+// mengembalikan jumlah migration yang dijalankan
+func (s *Store) Migrate(ctx context.Context) (int, error) {
+	migrations, err := s.Migrations()
+	if err != nil {
+		return 0, err
+	}
+	applied, err := s.appliedMigrations(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := s.runMigration(ctx, m, m.Up, true); err != nil {
+			return count, fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Rollback membatalkan sejumlah migration terakhir yang sudah diterapkan
+func (s *Store) Rollback(ctx context.Context, steps int) (int, error) {
+	migrations, err := s.Migrations()
+	if err != nil {
+		return 0, err
+	}
+	applied, err := s.appliedMigrations(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if m.Down == "" {
+			return count, fmt.Errorf("migration %04d_%s has no down script", m.Version, m.Name)
+		}
+		if err := s.runMigration(ctx, m, m.Down, false); err != nil {
+			return count, fmt.Errorf("rollback %04d_%s failed: %w", m.Version, m.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// MigrationStatus mengembalikan semua migration beserta waktu diterapkannya
+func (s *Store) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := s.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := applied[m.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// appliedMigrations membuat tabel SCHEMA_MIGRATIONS jika belum ada dan
+// membaca versi yang sudah diterapkan
+func (s *Store) appliedMigrations(ctx context.Context) (map[int]time.Time, error) {
+	if err := s.ensureMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.QueryContext(ctx, rebind(s.Dialect, s.Schema, "SELECT version, applied_at FROM {schema}"+migrationsTable))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+func (s *Store) ensureMigrationsTable(ctx context.Context) error {
+	exists, err := s.Dialect.tableExists(ctx, s.DB, s.Schema, migrationsTable)
+	if err != nil || exists {
+		return err
+	}
+
+	ddl := "CREATE TABLE {schema}" + migrationsTable + " (version INTEGER PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMP NOT NULL)"
+	if _, err := s.DB.ExecContext(ctx, rebind(s.Dialect, s.Schema, ddl)); err != nil {
+		return err
+	}
+
+	// Database lama yang dibuat sebelum ada migration sudah punya skema awal,
+	// jadi versi pertama dicatat tanpa dijalankan
+	legacy, err := s.Dialect.tableExists(ctx, s.DB, s.Schema, "PRODUCTS")
+	if err != nil || !legacy {
+		return err
+	}
+	migrations, err := s.Migrations()
+	if err != nil || len(migrations) == 0 {
+		return err
+	}
+	return s.recordMigration(ctx, s.DB, migrations[0], true)
+}
+
+// runMigration menjalankan satu script dan mencatat hasilnya di SCHEMA_MIGRATIONS.
+// Untuk dialect yang mendukung DDL transaksional semuanya dijalankan dalam satu transaksi.
+func (s *Store) runMigration(ctx context.Context, m Migration, script string, up bool) error {
+	statements := splitStatements(withSchema(s.Schema, script))
+
+	if !s.Dialect.transactionalDDL() {
+		for _, stmt := range statements {
+			if _, err := s.DB.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		return s.recordMigration(ctx, s.DB, m, up)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	if err := s.recordMigration(ctx, tx, m, up); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) recordMigration(ctx context.Context, q queryer, m Migration, up bool) error {
+	var err error
+	if up {
+		_, err = q.ExecContext(ctx, rebind(s.Dialect, s.Schema, "INSERT INTO {schema}"+migrationsTable+" (version, name, applied_at) VALUES (?, ?, ?)"),
+			m.Version, m.Name, time.Now())
+	} else {
+		_, err = q.ExecContext(ctx, rebind(s.Dialect, s.Schema, "DELETE FROM {schema}"+migrationsTable+" WHERE version = ?"), m.Version)
+	}
+	return err
+}
+
+// splitStatements memecah script per statement yang diakhiri ";" di akhir baris
+// dan membuang baris komentar
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		if strings.HasSuffix(trimmed, ";") {
+			current.WriteString(strings.TrimSuffix(trimmed, ";"))
+			statements = append(statements, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
@@ -0,0 +1,4 @@
+DROP TABLE {schema}USERS;
+DROP TABLE {schema}ORDER_DETAILS;
+DROP TABLE {schema}ORDERS;
+DROP TABLE {schema}PRODUCTS;
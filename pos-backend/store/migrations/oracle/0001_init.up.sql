@@ -0,0 +1,30 @@
+-- Skema awal POS: produk, order, detail order dan user
+CREATE TABLE {schema}PRODUCTS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR2(255) NOT NULL,
+    price NUMBER(12,2) NOT NULL,
+    image_url VARCHAR2(500)
+);
+
+CREATE TABLE {schema}ORDERS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    menu VARCHAR2(255),
+    status VARCHAR2(50) DEFAULT 'On Progress' NOT NULL,
+    total_price NUMBER(12,2),
+    created_at TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL
+);
+
+CREATE TABLE {schema}ORDER_DETAILS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id NUMBER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    product_name VARCHAR2(255) NOT NULL,
+    quantity NUMBER(10) NOT NULL,
+    total_price NUMBER(12,2)
+);
+
+CREATE TABLE {schema}USERS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    username VARCHAR2(100) NOT NULL UNIQUE,
+    password VARCHAR2(255) NOT NULL,
+    role VARCHAR2(50) NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE {schema}ORDER_DETAILS DROP (product_id, unit_price);
@@ -0,0 +1,5 @@
+-- Snapshot produk dan harga satuan pada setiap baris order
+ALTER TABLE {schema}ORDER_DETAILS ADD (
+    product_id NUMBER,
+    unit_price NUMBER(12,2)
+);
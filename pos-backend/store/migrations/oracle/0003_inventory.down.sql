@@ -0,0 +1,2 @@
+DROP TABLE {schema}STOCK_MOVEMENTS;
+ALTER TABLE {schema}PRODUCTS DROP (stock, low_stock_threshold);
@@ -0,0 +1,18 @@
+-- Stok per produk (NULL berarti stok tidak dilacak) dan riwayat pergerakan stok
+ALTER TABLE {schema}PRODUCTS ADD (
+    stock NUMBER(10),
+    low_stock_threshold NUMBER(10) DEFAULT 5 NOT NULL
+);
+
+CREATE TABLE {schema}STOCK_MOVEMENTS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    product_id NUMBER NOT NULL REFERENCES {schema}PRODUCTS (id) ON DELETE CASCADE,
+    quantity NUMBER(10) NOT NULL,
+    reason VARCHAR2(20) NOT NULL,
+    order_id NUMBER REFERENCES {schema}ORDERS (id) ON DELETE SET NULL,
+    note VARCHAR2(255),
+    username VARCHAR2(100),
+    created_at TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL
+);
+
+CREATE INDEX {schema}IDX_STOCK_MOVEMENTS_PRODUCT ON {schema}STOCK_MOVEMENTS (product_id);
@@ -0,0 +1,14 @@
+-- Pembayaran per order, satu baris per tender
+CREATE TABLE {schema}PAYMENTS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id NUMBER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    method VARCHAR2(20) NOT NULL,
+    amount NUMBER(12,2) NOT NULL,
+    tendered NUMBER(12,2) NOT NULL,
+    change_due NUMBER(12,2) DEFAULT 0 NOT NULL,
+    reference VARCHAR2(100),
+    username VARCHAR2(100),
+    created_at TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL
+);
+
+CREATE INDEX {schema}IDX_PAYMENTS_ORDER ON {schema}PAYMENTS (order_id);
@@ -0,0 +1,12 @@
+-- Riwayat perpindahan status order
+CREATE TABLE {schema}ORDER_STATUS_HISTORY (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id NUMBER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    from_status VARCHAR2(50),
+    to_status VARCHAR2(50) NOT NULL,
+    username VARCHAR2(100),
+    reason VARCHAR2(255),
+    created_at TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL
+);
+
+CREATE INDEX {schema}IDX_ORDER_STATUS_HISTORY_ORDER ON {schema}ORDER_STATUS_HISTORY (order_id);
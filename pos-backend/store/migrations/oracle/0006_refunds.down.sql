@@ -0,0 +1,2 @@
+DROP TABLE {schema}REFUND_ITEMS;
+DROP TABLE {schema}REFUNDS;
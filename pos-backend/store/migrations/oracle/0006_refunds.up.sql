@@ -0,0 +1,25 @@
+-- Refund untuk order yang sudah selesai, penuh atau per baris
+CREATE TABLE {schema}REFUNDS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id NUMBER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    method VARCHAR2(20) NOT NULL,
+    amount NUMBER(12,2) NOT NULL,
+    reason VARCHAR2(30) NOT NULL,
+    note VARCHAR2(255),
+    restock NUMBER(1) DEFAULT 0 NOT NULL,
+    username VARCHAR2(100),
+    approved_by VARCHAR2(100),
+    created_at TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL
+);
+
+CREATE INDEX {schema}IDX_REFUNDS_ORDER ON {schema}REFUNDS (order_id);
+
+CREATE TABLE {schema}REFUND_ITEMS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    refund_id NUMBER NOT NULL REFERENCES {schema}REFUNDS (id) ON DELETE CASCADE,
+    order_detail_id NUMBER NOT NULL REFERENCES {schema}ORDER_DETAILS (id) ON DELETE CASCADE,
+    quantity NUMBER NOT NULL,
+    amount NUMBER(12,2) NOT NULL
+);
+
+CREATE INDEX {schema}IDX_REFUND_ITEMS_REFUND ON {schema}REFUND_ITEMS (refund_id);
@@ -0,0 +1 @@
+ALTER TABLE {schema}ORDER_DETAILS DROP (prepared_at, prepared_by);
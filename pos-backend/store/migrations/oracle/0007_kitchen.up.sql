@@ -0,0 +1,5 @@
+-- Tanda baris order yang sudah disiapkan dapur
+ALTER TABLE {schema}ORDER_DETAILS ADD (
+    prepared_at TIMESTAMP,
+    prepared_by VARCHAR2(100)
+);
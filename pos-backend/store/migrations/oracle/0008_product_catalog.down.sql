@@ -0,0 +1,2 @@
+DROP INDEX {schema}IDX_PRODUCTS_SKU;
+ALTER TABLE {schema}PRODUCTS DROP (sku, category);
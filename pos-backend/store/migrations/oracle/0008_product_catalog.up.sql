@@ -0,0 +1,7 @@
+-- SKU dan kategori produk untuk import massal
+ALTER TABLE {schema}PRODUCTS ADD (
+    sku VARCHAR2(64),
+    category VARCHAR2(100)
+);
+
+CREATE UNIQUE INDEX {schema}IDX_PRODUCTS_SKU ON {schema}PRODUCTS (sku);
@@ -0,0 +1,11 @@
+ALTER TABLE {schema}PRODUCTS ADD (
+    category VARCHAR2(100)
+);
+
+UPDATE {schema}PRODUCTS p
+SET category = (SELECT c.name FROM {schema}CATEGORIES c WHERE c.id = p.category_id)
+WHERE p.category_id IS NOT NULL;
+
+DROP INDEX {schema}IDX_PRODUCTS_CATEGORY;
+ALTER TABLE {schema}PRODUCTS DROP (category_id);
+DROP TABLE {schema}CATEGORIES;
@@ -0,0 +1,24 @@
+-- Kategori menu, sort_order menentukan urutan di layar kasir
+CREATE TABLE {schema}CATEGORIES (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR2(100) NOT NULL UNIQUE,
+    sort_order NUMBER(10) DEFAULT 0 NOT NULL
+);
+
+ALTER TABLE {schema}PRODUCTS ADD (
+    category_id NUMBER REFERENCES {schema}CATEGORIES (id) ON DELETE SET NULL
+);
+
+-- Kategori teks dari import dipindah ke tabel CATEGORIES
+INSERT INTO {schema}CATEGORIES (name)
+SELECT DISTINCT category FROM {schema}PRODUCTS WHERE category IS NOT NULL;
+
+UPDATE {schema}CATEGORIES SET sort_order = id;
+
+UPDATE {schema}PRODUCTS p
+SET category_id = (SELECT c.id FROM {schema}CATEGORIES c WHERE c.name = p.category)
+WHERE p.category IS NOT NULL;
+
+ALTER TABLE {schema}PRODUCTS DROP (category);
+
+CREATE INDEX {schema}IDX_PRODUCTS_CATEGORY ON {schema}PRODUCTS (category_id);
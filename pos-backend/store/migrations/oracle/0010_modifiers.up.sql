@@ -0,0 +1,34 @@
+-- Grup modifier per produk (ukuran, level pedas, topping) dan pilihannya
+CREATE TABLE {schema}MODIFIER_GROUPS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    product_id NUMBER NOT NULL REFERENCES {schema}PRODUCTS (id) ON DELETE CASCADE,
+    name VARCHAR2(100) NOT NULL,
+    required NUMBER(1) DEFAULT 0 NOT NULL,
+    min_select NUMBER(10) DEFAULT 0 NOT NULL,
+    max_select NUMBER(10) DEFAULT 0 NOT NULL,
+    sort_order NUMBER(10) DEFAULT 0 NOT NULL
+);
+
+CREATE INDEX {schema}IDX_MODIFIER_GROUPS_PRODUCT ON {schema}MODIFIER_GROUPS (product_id);
+
+CREATE TABLE {schema}MODIFIER_OPTIONS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    group_id NUMBER NOT NULL REFERENCES {schema}MODIFIER_GROUPS (id) ON DELETE CASCADE,
+    name VARCHAR2(100) NOT NULL,
+    price_delta NUMBER(12,2) DEFAULT 0 NOT NULL,
+    sort_order NUMBER(10) DEFAULT 0 NOT NULL
+);
+
+CREATE INDEX {schema}IDX_MODIFIER_OPTIONS_GROUP ON {schema}MODIFIER_OPTIONS (group_id);
+
+-- Snapshot modifier yang dipilih pada setiap baris order
+CREATE TABLE {schema}ORDER_DETAIL_MODIFIERS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_detail_id NUMBER NOT NULL REFERENCES {schema}ORDER_DETAILS (id) ON DELETE CASCADE,
+    option_id NUMBER,
+    group_name VARCHAR2(100) NOT NULL,
+    option_name VARCHAR2(100) NOT NULL,
+    price_delta NUMBER(12,2) DEFAULT 0 NOT NULL
+);
+
+CREATE INDEX {schema}IDX_DETAIL_MODIFIERS_DETAIL ON {schema}ORDER_DETAIL_MODIFIERS (order_detail_id);
@@ -0,0 +1,4 @@
+ALTER TABLE {schema}ORDER_DETAILS DROP (discount);
+ALTER TABLE {schema}ORDERS DROP (subtotal, discount);
+DROP TABLE {schema}ORDER_PROMOTIONS;
+DROP TABLE {schema}PROMOTIONS;
@@ -0,0 +1,50 @@
+-- Aturan promo: diskon persen/nominal per baris atau per order, beli X
+-- gratis Y, kode promo dengan batas pemakaian dan jam happy hour
+CREATE TABLE {schema}PROMOTIONS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR2(100) NOT NULL,
+    type VARCHAR2(20) NOT NULL,
+    scope VARCHAR2(10) NOT NULL,
+    value NUMBER(12,2) DEFAULT 0 NOT NULL,
+    product_id NUMBER REFERENCES {schema}PRODUCTS (id) ON DELETE CASCADE,
+    category_id NUMBER REFERENCES {schema}CATEGORIES (id) ON DELETE CASCADE,
+    buy_quantity NUMBER(10) DEFAULT 0 NOT NULL,
+    get_quantity NUMBER(10) DEFAULT 0 NOT NULL,
+    min_subtotal NUMBER(12,2) DEFAULT 0 NOT NULL,
+    code VARCHAR2(50) UNIQUE,
+    usage_limit NUMBER(10) DEFAULT 0 NOT NULL,
+    usage_count NUMBER(10) DEFAULT 0 NOT NULL,
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    days VARCHAR2(20),
+    start_time VARCHAR2(5),
+    end_time VARCHAR2(5),
+    active NUMBER(1) DEFAULT 1 NOT NULL,
+    created_at TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL
+);
+
+-- Promo yang dipakai setiap order, order_detail_id kosong untuk diskon order
+CREATE TABLE {schema}ORDER_PROMOTIONS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id NUMBER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    promotion_id NUMBER,
+    order_detail_id NUMBER,
+    name VARCHAR2(100) NOT NULL,
+    code VARCHAR2(50),
+    amount NUMBER(12,2) NOT NULL
+);
+
+CREATE INDEX {schema}IDX_ORDER_PROMOTIONS_ORDER ON {schema}ORDER_PROMOTIONS (order_id);
+
+-- total_price = subtotal - discount
+ALTER TABLE {schema}ORDERS ADD (
+    subtotal NUMBER(12,2),
+    discount NUMBER(12,2) DEFAULT 0 NOT NULL
+);
+
+UPDATE {schema}ORDERS SET subtotal = total_price;
+
+-- Bagian diskon (promo baris dan bagian diskon order) pada setiap baris
+ALTER TABLE {schema}ORDER_DETAILS ADD (
+    discount NUMBER(12,2) DEFAULT 0 NOT NULL
+);
@@ -0,0 +1,4 @@
+ALTER TABLE {schema}ORDER_DETAILS DROP (service_charge, tax, tax_included);
+ALTER TABLE {schema}ORDERS DROP (service_charge, tax, tax_included);
+DROP TABLE {schema}ORDER_TAXES;
+DROP TABLE {schema}TAX_RULES;
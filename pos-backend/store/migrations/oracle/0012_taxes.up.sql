@@ -0,0 +1,44 @@
+-- Aturan pajak (PB1/PPN) dan service charge. Aturan tanpa produk dan
+-- kategori berlaku untuk semua produk. inclusive = 1 berarti harga produk
+-- sudah termasuk pajak sehingga pajak tidak ditambahkan ke total.
+CREATE TABLE {schema}TAX_RULES (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR2(100) NOT NULL,
+    type VARCHAR2(10) NOT NULL,
+    rate NUMBER(5,2) NOT NULL,
+    inclusive NUMBER(1) DEFAULT 0 NOT NULL,
+    product_id NUMBER REFERENCES {schema}PRODUCTS (id) ON DELETE CASCADE,
+    category_id NUMBER REFERENCES {schema}CATEGORIES (id) ON DELETE CASCADE,
+    active NUMBER(1) DEFAULT 1 NOT NULL,
+    created_at TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL
+);
+
+-- Pajak dan service charge yang dihitung untuk setiap order
+CREATE TABLE {schema}ORDER_TAXES (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id NUMBER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    tax_rule_id NUMBER,
+    name VARCHAR2(100) NOT NULL,
+    type VARCHAR2(10) NOT NULL,
+    rate NUMBER(5,2) NOT NULL,
+    inclusive NUMBER(1) DEFAULT 0 NOT NULL,
+    base NUMBER(12,2) NOT NULL,
+    amount NUMBER(12,2) NOT NULL
+);
+
+CREATE INDEX {schema}IDX_ORDER_TAXES_ORDER ON {schema}ORDER_TAXES (order_id);
+
+-- total_price = subtotal - discount + service_charge + tax,
+-- tax_included adalah pajak yang sudah termasuk dalam harga
+ALTER TABLE {schema}ORDERS ADD (
+    service_charge NUMBER(12,2) DEFAULT 0 NOT NULL,
+    tax NUMBER(12,2) DEFAULT 0 NOT NULL,
+    tax_included NUMBER(12,2) DEFAULT 0 NOT NULL
+);
+
+-- Bagian service charge dan pajak setiap baris, dipakai saat refund
+ALTER TABLE {schema}ORDER_DETAILS ADD (
+    service_charge NUMBER(12,2) DEFAULT 0 NOT NULL,
+    tax NUMBER(12,2) DEFAULT 0 NOT NULL,
+    tax_included NUMBER(12,2) DEFAULT 0 NOT NULL
+);
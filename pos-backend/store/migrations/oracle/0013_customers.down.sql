@@ -0,0 +1,4 @@
+DROP INDEX {schema}IDX_ORDERS_CUSTOMER;
+ALTER TABLE {schema}ORDERS DROP (customer_id, points_earned, points_redeemed);
+DROP TABLE {schema}POINT_MOVEMENTS;
+DROP TABLE {schema}CUSTOMERS;
@@ -0,0 +1,33 @@
+-- Pelanggan dan saldo poin loyalty
+CREATE TABLE {schema}CUSTOMERS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR2(100) NOT NULL,
+    phone VARCHAR2(30) UNIQUE,
+    email VARCHAR2(255),
+    points NUMBER(10) DEFAULT 0 NOT NULL,
+    created_at TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL
+);
+
+-- Riwayat poin, positif berarti poin bertambah
+CREATE TABLE {schema}POINT_MOVEMENTS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    customer_id NUMBER NOT NULL REFERENCES {schema}CUSTOMERS (id) ON DELETE CASCADE,
+    points NUMBER(10) NOT NULL,
+    reason VARCHAR2(20) NOT NULL,
+    order_id NUMBER REFERENCES {schema}ORDERS (id) ON DELETE SET NULL,
+    note VARCHAR2(255),
+    username VARCHAR2(50),
+    created_at TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL
+);
+
+CREATE INDEX {schema}IDX_POINT_MOVEMENTS_CUSTOMER ON {schema}POINT_MOVEMENTS (customer_id);
+CREATE INDEX {schema}IDX_POINT_MOVEMENTS_ORDER ON {schema}POINT_MOVEMENTS (order_id);
+
+-- points_redeemed adalah poin yang ditukar sebagai diskon order
+ALTER TABLE {schema}ORDERS ADD (
+    customer_id NUMBER REFERENCES {schema}CUSTOMERS (id) ON DELETE SET NULL,
+    points_earned NUMBER(10) DEFAULT 0 NOT NULL,
+    points_redeemed NUMBER(10) DEFAULT 0 NOT NULL
+);
+
+CREATE INDEX {schema}IDX_ORDERS_CUSTOMER ON {schema}ORDERS (customer_id);
@@ -0,0 +1,8 @@
+DROP INDEX {schema}IDX_REFUNDS_SHIFT;
+DROP INDEX {schema}IDX_PAYMENTS_SHIFT;
+DROP INDEX {schema}IDX_ORDERS_SHIFT;
+ALTER TABLE {schema}REFUNDS DROP (shift_id);
+ALTER TABLE {schema}PAYMENTS DROP (shift_id);
+ALTER TABLE {schema}ORDERS DROP (shift_id);
+DROP TABLE {schema}CASH_MOVEMENTS;
+DROP TABLE {schema}SHIFTS;
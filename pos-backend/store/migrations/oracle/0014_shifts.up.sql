@@ -0,0 +1,42 @@
+-- Shift kasir: modal awal laci, uang yang dihitung saat tutup dan selisihnya
+CREATE TABLE {schema}SHIFTS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    username VARCHAR2(50) NOT NULL,
+    opening_float NUMBER(12,2) DEFAULT 0 NOT NULL,
+    opened_at TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL,
+    closed_at TIMESTAMP,
+    closed_by VARCHAR2(50),
+    expected_cash NUMBER(12,2),
+    counted_cash NUMBER(12,2),
+    note VARCHAR2(255)
+);
+
+CREATE INDEX {schema}IDX_SHIFTS_USER ON {schema}SHIFTS (username, closed_at);
+
+-- Uang masuk/keluar laci di luar penjualan, amount selalu positif
+CREATE TABLE {schema}CASH_MOVEMENTS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    shift_id NUMBER NOT NULL REFERENCES {schema}SHIFTS (id) ON DELETE CASCADE,
+    type VARCHAR2(10) NOT NULL,
+    amount NUMBER(12,2) NOT NULL,
+    note VARCHAR2(255),
+    username VARCHAR2(50),
+    created_at TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL
+);
+
+CREATE INDEX {schema}IDX_CASH_MOVEMENTS_SHIFT ON {schema}CASH_MOVEMENTS (shift_id);
+
+-- Order, pembayaran dan refund dicatat pada shift kasir yang sedang buka
+ALTER TABLE {schema}ORDERS ADD (
+    shift_id NUMBER REFERENCES {schema}SHIFTS (id) ON DELETE SET NULL
+);
+ALTER TABLE {schema}PAYMENTS ADD (
+    shift_id NUMBER REFERENCES {schema}SHIFTS (id) ON DELETE SET NULL
+);
+ALTER TABLE {schema}REFUNDS ADD (
+    shift_id NUMBER REFERENCES {schema}SHIFTS (id) ON DELETE SET NULL
+);
+
+CREATE INDEX {schema}IDX_ORDERS_SHIFT ON {schema}ORDERS (shift_id);
+CREATE INDEX {schema}IDX_PAYMENTS_SHIFT ON {schema}PAYMENTS (shift_id);
+CREATE INDEX {schema}IDX_REFUNDS_SHIFT ON {schema}REFUNDS (shift_id);
@@ -0,0 +1,3 @@
+DROP INDEX {schema}IDX_ORDERS_TABLE;
+ALTER TABLE {schema}ORDERS DROP (order_type, table_id, table_name, delivery_address);
+DROP TABLE {schema}DINING_TABLES;
@@ -0,0 +1,19 @@
+-- Meja untuk order dine-in, status terisi/kosong dihitung dari order yang masih buka
+CREATE TABLE {schema}DINING_TABLES (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR2(50) NOT NULL UNIQUE,
+    seats NUMBER(10) DEFAULT 0 NOT NULL,
+    area VARCHAR2(50),
+    sort_order NUMBER(10) DEFAULT 0 NOT NULL
+);
+
+-- Jenis order (dine_in, takeaway, delivery); table_name disalin supaya
+-- riwayat tetap terbaca setelah meja dihapus
+ALTER TABLE {schema}ORDERS ADD (
+    order_type VARCHAR2(20) DEFAULT 'takeaway' NOT NULL,
+    table_id NUMBER REFERENCES {schema}DINING_TABLES (id) ON DELETE SET NULL,
+    table_name VARCHAR2(50),
+    delivery_address VARCHAR2(255)
+);
+
+CREATE INDEX {schema}IDX_ORDERS_TABLE ON {schema}ORDERS (table_id, status);
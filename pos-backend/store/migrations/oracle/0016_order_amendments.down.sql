@@ -0,0 +1 @@
+DROP TABLE {schema}ORDER_AMENDMENTS;
@@ -0,0 +1,18 @@
+-- Perubahan baris order yang masih berjalan (tambah, ubah jumlah, void)
+-- beserta total order sebelum dan sesudahnya
+CREATE TABLE {schema}ORDER_AMENDMENTS (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id NUMBER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    order_detail_id NUMBER,
+    action VARCHAR2(20) NOT NULL,
+    product_name VARCHAR2(255) NOT NULL,
+    old_quantity NUMBER(10) DEFAULT 0 NOT NULL,
+    new_quantity NUMBER(10) DEFAULT 0 NOT NULL,
+    old_total NUMBER(12,2) NOT NULL,
+    new_total NUMBER(12,2) NOT NULL,
+    username VARCHAR2(100),
+    reason VARCHAR2(255),
+    created_at TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL
+);
+
+CREATE INDEX {schema}IDX_ORDER_AMENDMENTS_ORDER ON {schema}ORDER_AMENDMENTS (order_id);
@@ -0,0 +1,2 @@
+DROP INDEX {schema}IDX_ORDERS_PARENT;
+ALTER TABLE {schema}ORDERS DROP (parent_order_id);
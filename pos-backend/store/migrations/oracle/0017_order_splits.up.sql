@@ -0,0 +1,6 @@
+-- Order hasil split bill menunjuk ke order asalnya
+ALTER TABLE {schema}ORDERS ADD (
+    parent_order_id NUMBER REFERENCES {schema}ORDERS (id)
+);
+
+CREATE INDEX {schema}IDX_ORDERS_PARENT ON {schema}ORDERS (parent_order_id);
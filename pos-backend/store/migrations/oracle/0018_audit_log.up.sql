@@ -0,0 +1,23 @@
+-- Log audit append-only untuk setiap request yang mengubah data. prev_hash
+-- unik supaya rantai hash tidak bercabang saat dua request menulis bersamaan.
+CREATE TABLE {schema}AUDIT_LOG (
+    id NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    actor VARCHAR2(100),
+    actor_role VARCHAR2(20),
+    action VARCHAR2(100) NOT NULL,
+    method VARCHAR2(10) NOT NULL,
+    path VARCHAR2(255) NOT NULL,
+    entity VARCHAR2(50),
+    entity_id VARCHAR2(50),
+    before_value CLOB,
+    after_value CLOB,
+    status NUMBER(3) NOT NULL,
+    ip VARCHAR2(64),
+    created_at TIMESTAMP NOT NULL,
+    prev_hash VARCHAR2(64) NOT NULL UNIQUE,
+    hash VARCHAR2(64) NOT NULL
+);
+
+CREATE INDEX {schema}IDX_AUDIT_LOG_ENTITY ON {schema}AUDIT_LOG (entity, entity_id);
+CREATE INDEX {schema}IDX_AUDIT_LOG_ACTOR ON {schema}AUDIT_LOG (actor, created_at);
+CREATE INDEX {schema}IDX_AUDIT_LOG_CREATED ON {schema}AUDIT_LOG (created_at);
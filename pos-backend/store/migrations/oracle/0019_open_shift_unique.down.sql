@@ -0,0 +1 @@
+DROP INDEX {schema}IDX_SHIFTS_OPEN_USER;
@@ -0,0 +1,3 @@
+-- Satu user hanya boleh punya satu shift terbuka. Oracle tidak punya partial
+-- index; baris yang sudah ditutup menghasilkan NULL dan tidak diindeks.
+CREATE UNIQUE INDEX {schema}IDX_SHIFTS_OPEN_USER ON {schema}SHIFTS (CASE WHEN closed_at IS NULL THEN username END);
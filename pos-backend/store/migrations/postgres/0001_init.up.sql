@@ -0,0 +1,30 @@
+-- Skema awal POS: produk, order, detail order dan user
+CREATE TABLE {schema}PRODUCTS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    price NUMERIC(12,2) NOT NULL,
+    image_url VARCHAR(500)
+);
+
+CREATE TABLE {schema}ORDERS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    menu VARCHAR(255),
+    status VARCHAR(50) NOT NULL DEFAULT 'On Progress',
+    total_price NUMERIC(12,2),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE {schema}ORDER_DETAILS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    product_name VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL,
+    total_price NUMERIC(12,2)
+);
+
+CREATE TABLE {schema}USERS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    password VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL
+);
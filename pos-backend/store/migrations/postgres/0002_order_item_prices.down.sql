@@ -0,0 +1,3 @@
+ALTER TABLE {schema}ORDER_DETAILS
+    DROP COLUMN product_id,
+    DROP COLUMN unit_price;
@@ -0,0 +1,4 @@
+-- Snapshot produk dan harga satuan pada setiap baris order
+ALTER TABLE {schema}ORDER_DETAILS
+    ADD COLUMN product_id INTEGER,
+    ADD COLUMN unit_price NUMERIC(12,2);
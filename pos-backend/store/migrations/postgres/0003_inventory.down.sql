@@ -0,0 +1,4 @@
+DROP TABLE {schema}STOCK_MOVEMENTS;
+ALTER TABLE {schema}PRODUCTS
+    DROP COLUMN stock,
+    DROP COLUMN low_stock_threshold;
@@ -0,0 +1,17 @@
+-- Stok per produk (NULL berarti stok tidak dilacak) dan riwayat pergerakan stok
+ALTER TABLE {schema}PRODUCTS
+    ADD COLUMN stock INTEGER,
+    ADD COLUMN low_stock_threshold INTEGER NOT NULL DEFAULT 5;
+
+CREATE TABLE {schema}STOCK_MOVEMENTS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES {schema}PRODUCTS (id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    order_id INTEGER REFERENCES {schema}ORDERS (id) ON DELETE SET NULL,
+    note VARCHAR(255),
+    username VARCHAR(100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_stock_movements_product ON {schema}STOCK_MOVEMENTS (product_id);
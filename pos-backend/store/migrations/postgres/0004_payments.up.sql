@@ -0,0 +1,14 @@
+-- Pembayaran per order, satu baris per tender
+CREATE TABLE {schema}PAYMENTS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL,
+    amount NUMERIC(12,2) NOT NULL,
+    tendered NUMERIC(12,2) NOT NULL,
+    change_due NUMERIC(12,2) NOT NULL DEFAULT 0,
+    reference VARCHAR(100),
+    username VARCHAR(100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_payments_order ON {schema}PAYMENTS (order_id);
@@ -0,0 +1,12 @@
+-- Riwayat perpindahan status order
+CREATE TABLE {schema}ORDER_STATUS_HISTORY (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    from_status VARCHAR(50),
+    to_status VARCHAR(50) NOT NULL,
+    username VARCHAR(100),
+    reason VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_order_status_history_order ON {schema}ORDER_STATUS_HISTORY (order_id);
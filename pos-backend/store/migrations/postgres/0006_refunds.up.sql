@@ -0,0 +1,25 @@
+-- Refund untuk order yang sudah selesai, penuh atau per baris
+CREATE TABLE {schema}REFUNDS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL,
+    amount NUMERIC(12,2) NOT NULL,
+    reason VARCHAR(30) NOT NULL,
+    note VARCHAR(255),
+    restock SMALLINT NOT NULL DEFAULT 0,
+    username VARCHAR(100),
+    approved_by VARCHAR(100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_refunds_order ON {schema}REFUNDS (order_id);
+
+CREATE TABLE {schema}REFUND_ITEMS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    refund_id INTEGER NOT NULL REFERENCES {schema}REFUNDS (id) ON DELETE CASCADE,
+    order_detail_id INTEGER NOT NULL REFERENCES {schema}ORDER_DETAILS (id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL,
+    amount NUMERIC(12,2) NOT NULL
+);
+
+CREATE INDEX idx_refund_items_refund ON {schema}REFUND_ITEMS (refund_id);
@@ -0,0 +1,3 @@
+ALTER TABLE {schema}ORDER_DETAILS
+    DROP COLUMN prepared_at,
+    DROP COLUMN prepared_by;
@@ -0,0 +1,4 @@
+-- Tanda baris order yang sudah disiapkan dapur
+ALTER TABLE {schema}ORDER_DETAILS
+    ADD COLUMN prepared_at TIMESTAMPTZ,
+    ADD COLUMN prepared_by VARCHAR(100);
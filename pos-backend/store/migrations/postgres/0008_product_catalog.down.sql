@@ -0,0 +1,4 @@
+DROP INDEX {schema}idx_products_sku;
+ALTER TABLE {schema}PRODUCTS
+    DROP COLUMN sku,
+    DROP COLUMN category;
@@ -0,0 +1,6 @@
+-- SKU dan kategori produk untuk import massal
+ALTER TABLE {schema}PRODUCTS
+    ADD COLUMN sku VARCHAR(64),
+    ADD COLUMN category VARCHAR(100);
+
+CREATE UNIQUE INDEX idx_products_sku ON {schema}PRODUCTS (sku);
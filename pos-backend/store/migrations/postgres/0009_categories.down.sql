@@ -0,0 +1,9 @@
+ALTER TABLE {schema}PRODUCTS ADD COLUMN category VARCHAR(100);
+
+UPDATE {schema}PRODUCTS p
+SET category = (SELECT c.name FROM {schema}CATEGORIES c WHERE c.id = p.category_id)
+WHERE p.category_id IS NOT NULL;
+
+DROP INDEX {schema}idx_products_category;
+ALTER TABLE {schema}PRODUCTS DROP COLUMN category_id;
+DROP TABLE {schema}CATEGORIES;
@@ -0,0 +1,23 @@
+-- Kategori menu, sort_order menentukan urutan di layar kasir
+CREATE TABLE {schema}CATEGORIES (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+ALTER TABLE {schema}PRODUCTS
+    ADD COLUMN category_id INTEGER REFERENCES {schema}CATEGORIES (id) ON DELETE SET NULL;
+
+-- Kategori teks dari import dipindah ke tabel CATEGORIES
+INSERT INTO {schema}CATEGORIES (name)
+SELECT DISTINCT category FROM {schema}PRODUCTS WHERE category IS NOT NULL;
+
+UPDATE {schema}CATEGORIES SET sort_order = id;
+
+UPDATE {schema}PRODUCTS p
+SET category_id = (SELECT c.id FROM {schema}CATEGORIES c WHERE c.name = p.category)
+WHERE p.category IS NOT NULL;
+
+ALTER TABLE {schema}PRODUCTS DROP COLUMN category;
+
+CREATE INDEX idx_products_category ON {schema}PRODUCTS (category_id);
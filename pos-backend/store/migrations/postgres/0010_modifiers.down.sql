@@ -0,0 +1,3 @@
+DROP TABLE {schema}ORDER_DETAIL_MODIFIERS;
+DROP TABLE {schema}MODIFIER_OPTIONS;
+DROP TABLE {schema}MODIFIER_GROUPS;
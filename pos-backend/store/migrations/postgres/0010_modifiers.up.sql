@@ -0,0 +1,34 @@
+-- Grup modifier per produk (ukuran, level pedas, topping) dan pilihannya
+CREATE TABLE {schema}MODIFIER_GROUPS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES {schema}PRODUCTS (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    required SMALLINT NOT NULL DEFAULT 0,
+    min_select INTEGER NOT NULL DEFAULT 0,
+    max_select INTEGER NOT NULL DEFAULT 0,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_modifier_groups_product ON {schema}MODIFIER_GROUPS (product_id);
+
+CREATE TABLE {schema}MODIFIER_OPTIONS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    group_id INTEGER NOT NULL REFERENCES {schema}MODIFIER_GROUPS (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    price_delta NUMERIC(12,2) NOT NULL DEFAULT 0,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_modifier_options_group ON {schema}MODIFIER_OPTIONS (group_id);
+
+-- Snapshot modifier yang dipilih pada setiap baris order
+CREATE TABLE {schema}ORDER_DETAIL_MODIFIERS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_detail_id INTEGER NOT NULL REFERENCES {schema}ORDER_DETAILS (id) ON DELETE CASCADE,
+    option_id INTEGER,
+    group_name VARCHAR(100) NOT NULL,
+    option_name VARCHAR(100) NOT NULL,
+    price_delta NUMERIC(12,2) NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_detail_modifiers_detail ON {schema}ORDER_DETAIL_MODIFIERS (order_detail_id);
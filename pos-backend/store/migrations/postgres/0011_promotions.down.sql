@@ -0,0 +1,6 @@
+ALTER TABLE {schema}ORDER_DETAILS DROP COLUMN discount;
+ALTER TABLE {schema}ORDERS
+    DROP COLUMN subtotal,
+    DROP COLUMN discount;
+DROP TABLE {schema}ORDER_PROMOTIONS;
+DROP TABLE {schema}PROMOTIONS;
@@ -0,0 +1,48 @@
+-- Aturan promo: diskon persen/nominal per baris atau per order, beli X
+-- gratis Y, kode promo dengan batas pemakaian dan jam happy hour
+CREATE TABLE {schema}PROMOTIONS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    scope VARCHAR(10) NOT NULL,
+    value NUMERIC(12,2) NOT NULL DEFAULT 0,
+    product_id INTEGER REFERENCES {schema}PRODUCTS (id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES {schema}CATEGORIES (id) ON DELETE CASCADE,
+    buy_quantity INTEGER NOT NULL DEFAULT 0,
+    get_quantity INTEGER NOT NULL DEFAULT 0,
+    min_subtotal NUMERIC(12,2) NOT NULL DEFAULT 0,
+    code VARCHAR(50) UNIQUE,
+    usage_limit INTEGER NOT NULL DEFAULT 0,
+    usage_count INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    days VARCHAR(20),
+    start_time VARCHAR(5),
+    end_time VARCHAR(5),
+    active SMALLINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Promo yang dipakai setiap order, order_detail_id kosong untuk diskon order
+CREATE TABLE {schema}ORDER_PROMOTIONS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    promotion_id INTEGER,
+    order_detail_id INTEGER,
+    name VARCHAR(100) NOT NULL,
+    code VARCHAR(50),
+    amount NUMERIC(12,2) NOT NULL
+);
+
+CREATE INDEX idx_order_promotions_order ON {schema}ORDER_PROMOTIONS (order_id);
+
+-- total_price = subtotal - discount
+ALTER TABLE {schema}ORDERS
+    ADD COLUMN subtotal NUMERIC(12,2),
+    ADD COLUMN discount NUMERIC(12,2) NOT NULL DEFAULT 0;
+
+UPDATE {schema}ORDERS SET subtotal = total_price;
+
+-- Bagian diskon (promo baris dan bagian diskon order) pada setiap baris
+ALTER TABLE {schema}ORDER_DETAILS
+    ADD COLUMN discount NUMERIC(12,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,10 @@
+ALTER TABLE {schema}ORDER_DETAILS
+    DROP COLUMN service_charge,
+    DROP COLUMN tax,
+    DROP COLUMN tax_included;
+ALTER TABLE {schema}ORDERS
+    DROP COLUMN service_charge,
+    DROP COLUMN tax,
+    DROP COLUMN tax_included;
+DROP TABLE {schema}ORDER_TAXES;
+DROP TABLE {schema}TAX_RULES;
@@ -0,0 +1,42 @@
+-- Aturan pajak (PB1/PPN) dan service charge. Aturan tanpa produk dan
+-- kategori berlaku untuk semua produk. inclusive = 1 berarti harga produk
+-- sudah termasuk pajak sehingga pajak tidak ditambahkan ke total.
+CREATE TABLE {schema}TAX_RULES (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(10) NOT NULL,
+    rate NUMERIC(5,2) NOT NULL,
+    inclusive SMALLINT NOT NULL DEFAULT 0,
+    product_id INTEGER REFERENCES {schema}PRODUCTS (id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES {schema}CATEGORIES (id) ON DELETE CASCADE,
+    active SMALLINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Pajak dan service charge yang dihitung untuk setiap order
+CREATE TABLE {schema}ORDER_TAXES (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    tax_rule_id INTEGER,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(10) NOT NULL,
+    rate NUMERIC(5,2) NOT NULL,
+    inclusive SMALLINT NOT NULL DEFAULT 0,
+    base NUMERIC(12,2) NOT NULL,
+    amount NUMERIC(12,2) NOT NULL
+);
+
+CREATE INDEX idx_order_taxes_order ON {schema}ORDER_TAXES (order_id);
+
+-- total_price = subtotal - discount + service_charge + tax,
+-- tax_included adalah pajak yang sudah termasuk dalam harga
+ALTER TABLE {schema}ORDERS
+    ADD COLUMN service_charge NUMERIC(12,2) NOT NULL DEFAULT 0,
+    ADD COLUMN tax NUMERIC(12,2) NOT NULL DEFAULT 0,
+    ADD COLUMN tax_included NUMERIC(12,2) NOT NULL DEFAULT 0;
+
+-- Bagian service charge dan pajak setiap baris, dipakai saat refund
+ALTER TABLE {schema}ORDER_DETAILS
+    ADD COLUMN service_charge NUMERIC(12,2) NOT NULL DEFAULT 0,
+    ADD COLUMN tax NUMERIC(12,2) NOT NULL DEFAULT 0,
+    ADD COLUMN tax_included NUMERIC(12,2) NOT NULL DEFAULT 0;
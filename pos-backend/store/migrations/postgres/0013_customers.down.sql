@@ -0,0 +1,7 @@
+DROP INDEX {schema}idx_orders_customer;
+ALTER TABLE {schema}ORDERS
+    DROP COLUMN customer_id,
+    DROP COLUMN points_earned,
+    DROP COLUMN points_redeemed;
+DROP TABLE {schema}POINT_MOVEMENTS;
+DROP TABLE {schema}CUSTOMERS;
@@ -0,0 +1,32 @@
+-- Pelanggan dan saldo poin loyalty
+CREATE TABLE {schema}CUSTOMERS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    phone VARCHAR(30) UNIQUE,
+    email VARCHAR(255),
+    points INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Riwayat poin, positif berarti poin bertambah
+CREATE TABLE {schema}POINT_MOVEMENTS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    customer_id INTEGER NOT NULL REFERENCES {schema}CUSTOMERS (id) ON DELETE CASCADE,
+    points INTEGER NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    order_id INTEGER REFERENCES {schema}ORDERS (id) ON DELETE SET NULL,
+    note VARCHAR(255),
+    username VARCHAR(50),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_point_movements_customer ON {schema}POINT_MOVEMENTS (customer_id);
+CREATE INDEX idx_point_movements_order ON {schema}POINT_MOVEMENTS (order_id);
+
+-- points_redeemed adalah poin yang ditukar sebagai diskon order
+ALTER TABLE {schema}ORDERS
+    ADD COLUMN customer_id INTEGER REFERENCES {schema}CUSTOMERS (id) ON DELETE SET NULL,
+    ADD COLUMN points_earned INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN points_redeemed INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_orders_customer ON {schema}ORDERS (customer_id);
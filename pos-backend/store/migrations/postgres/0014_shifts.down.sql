@@ -0,0 +1,8 @@
+DROP INDEX {schema}idx_refunds_shift;
+DROP INDEX {schema}idx_payments_shift;
+DROP INDEX {schema}idx_orders_shift;
+ALTER TABLE {schema}REFUNDS DROP COLUMN shift_id;
+ALTER TABLE {schema}PAYMENTS DROP COLUMN shift_id;
+ALTER TABLE {schema}ORDERS DROP COLUMN shift_id;
+DROP TABLE {schema}CASH_MOVEMENTS;
+DROP TABLE {schema}SHIFTS;
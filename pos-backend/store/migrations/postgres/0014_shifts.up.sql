@@ -0,0 +1,36 @@
+-- Shift kasir: modal awal laci, uang yang dihitung saat tutup dan selisihnya
+CREATE TABLE {schema}SHIFTS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    username VARCHAR(50) NOT NULL,
+    opening_float NUMERIC(12,2) NOT NULL DEFAULT 0,
+    opened_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    closed_at TIMESTAMPTZ,
+    closed_by VARCHAR(50),
+    expected_cash NUMERIC(12,2),
+    counted_cash NUMERIC(12,2),
+    note VARCHAR(255)
+);
+
+CREATE INDEX idx_shifts_user ON {schema}SHIFTS (username, closed_at);
+
+-- Uang masuk/keluar laci di luar penjualan, amount selalu positif
+CREATE TABLE {schema}CASH_MOVEMENTS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    shift_id INTEGER NOT NULL REFERENCES {schema}SHIFTS (id) ON DELETE CASCADE,
+    type VARCHAR(10) NOT NULL,
+    amount NUMERIC(12,2) NOT NULL,
+    note VARCHAR(255),
+    username VARCHAR(50),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_cash_movements_shift ON {schema}CASH_MOVEMENTS (shift_id);
+
+-- Order, pembayaran dan refund dicatat pada shift kasir yang sedang buka
+ALTER TABLE {schema}ORDERS ADD COLUMN shift_id INTEGER REFERENCES {schema}SHIFTS (id) ON DELETE SET NULL;
+ALTER TABLE {schema}PAYMENTS ADD COLUMN shift_id INTEGER REFERENCES {schema}SHIFTS (id) ON DELETE SET NULL;
+ALTER TABLE {schema}REFUNDS ADD COLUMN shift_id INTEGER REFERENCES {schema}SHIFTS (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_orders_shift ON {schema}ORDERS (shift_id);
+CREATE INDEX idx_payments_shift ON {schema}PAYMENTS (shift_id);
+CREATE INDEX idx_refunds_shift ON {schema}REFUNDS (shift_id);
@@ -0,0 +1,7 @@
+DROP INDEX {schema}idx_orders_table;
+ALTER TABLE {schema}ORDERS
+    DROP COLUMN order_type,
+    DROP COLUMN table_id,
+    DROP COLUMN table_name,
+    DROP COLUMN delivery_address;
+DROP TABLE {schema}DINING_TABLES;
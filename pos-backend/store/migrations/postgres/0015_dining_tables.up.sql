@@ -0,0 +1,18 @@
+-- Meja untuk order dine-in, status terisi/kosong dihitung dari order yang masih buka
+CREATE TABLE {schema}DINING_TABLES (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    seats INTEGER NOT NULL DEFAULT 0,
+    area VARCHAR(50),
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+-- Jenis order (dine_in, takeaway, delivery); table_name disalin supaya
+-- riwayat tetap terbaca setelah meja dihapus
+ALTER TABLE {schema}ORDERS
+    ADD COLUMN order_type VARCHAR(20) NOT NULL DEFAULT 'takeaway',
+    ADD COLUMN table_id INTEGER REFERENCES {schema}DINING_TABLES (id) ON DELETE SET NULL,
+    ADD COLUMN table_name VARCHAR(50),
+    ADD COLUMN delivery_address VARCHAR(255);
+
+CREATE INDEX idx_orders_table ON {schema}ORDERS (table_id, status);
@@ -0,0 +1,18 @@
+-- Perubahan baris order yang masih berjalan (tambah, ubah jumlah, void)
+-- beserta total order sebelum dan sesudahnya
+CREATE TABLE {schema}ORDER_AMENDMENTS (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES {schema}ORDERS (id) ON DELETE CASCADE,
+    order_detail_id INTEGER,
+    action VARCHAR(20) NOT NULL,
+    product_name VARCHAR(255) NOT NULL,
+    old_quantity INTEGER NOT NULL DEFAULT 0,
+    new_quantity INTEGER NOT NULL DEFAULT 0,
+    old_total NUMERIC(12,2) NOT NULL,
+    new_total NUMERIC(12,2) NOT NULL,
+    username VARCHAR(100),
+    reason VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_order_amendments_order ON {schema}ORDER_AMENDMENTS (order_id);
@@ -0,0 +1,2 @@
+DROP INDEX {schema}idx_orders_parent;
+ALTER TABLE {schema}ORDERS DROP COLUMN parent_order_id;
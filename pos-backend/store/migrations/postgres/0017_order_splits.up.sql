@@ -0,0 +1,5 @@
+-- Order hasil split bill menunjuk ke order asalnya
+ALTER TABLE {schema}ORDERS
+    ADD COLUMN parent_order_id INTEGER REFERENCES {schema}ORDERS (id);
+
+CREATE INDEX idx_orders_parent ON {schema}ORDERS (parent_order_id);
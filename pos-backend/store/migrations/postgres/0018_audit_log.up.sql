@@ -0,0 +1,25 @@
+-- Log audit append-only untuk setiap request yang mengubah data. prev_hash
+-- unik supaya rantai hash tidak bercabang saat dua request menulis bersamaan.
+-- Nilai sebelum/sesudah disimpan sebagai TEXT (bukan JSONB) agar byte yang
+-- di-hash tidak berubah.
+CREATE TABLE {schema}AUDIT_LOG (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    actor VARCHAR(100),
+    actor_role VARCHAR(20),
+    action VARCHAR(100) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(255) NOT NULL,
+    entity VARCHAR(50),
+    entity_id VARCHAR(50),
+    before_value TEXT,
+    after_value TEXT,
+    status INTEGER NOT NULL,
+    ip VARCHAR(64),
+    created_at TIMESTAMPTZ NOT NULL,
+    prev_hash VARCHAR(64) NOT NULL UNIQUE,
+    hash VARCHAR(64) NOT NULL
+);
+
+CREATE INDEX idx_audit_log_entity ON {schema}AUDIT_LOG (entity, entity_id);
+CREATE INDEX idx_audit_log_actor ON {schema}AUDIT_LOG (actor, created_at);
+CREATE INDEX idx_audit_log_created ON {schema}AUDIT_LOG (created_at);
@@ -0,0 +1 @@
+DROP INDEX {schema}idx_shifts_open_user;
@@ -0,0 +1,2 @@
+-- Satu user hanya boleh punya satu shift terbuka
+CREATE UNIQUE INDEX idx_shifts_open_user ON {schema}SHIFTS (username) WHERE closed_at IS NULL;
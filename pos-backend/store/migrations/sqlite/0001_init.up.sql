@@ -0,0 +1,30 @@
+-- Skema awal POS: produk, order, detail order dan user
+CREATE TABLE {schema}PRODUCTS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    price REAL NOT NULL,
+    image_url TEXT
+);
+
+CREATE TABLE {schema}ORDERS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    menu TEXT,
+    status TEXT NOT NULL DEFAULT 'On Progress',
+    total_price REAL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE {schema}ORDER_DETAILS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL REFERENCES ORDERS (id) ON DELETE CASCADE,
+    product_name TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    total_price REAL
+);
+
+CREATE TABLE {schema}USERS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username TEXT NOT NULL UNIQUE,
+    password TEXT NOT NULL,
+    role TEXT NOT NULL
+);
@@ -0,0 +1,2 @@
+ALTER TABLE {schema}ORDER_DETAILS DROP COLUMN unit_price;
+ALTER TABLE {schema}ORDER_DETAILS DROP COLUMN product_id;
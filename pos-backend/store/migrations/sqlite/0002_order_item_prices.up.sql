@@ -0,0 +1,3 @@
+-- Snapshot produk dan harga satuan pada setiap baris order
+ALTER TABLE {schema}ORDER_DETAILS ADD COLUMN product_id INTEGER;
+ALTER TABLE {schema}ORDER_DETAILS ADD COLUMN unit_price REAL;
@@ -0,0 +1,3 @@
+DROP TABLE {schema}STOCK_MOVEMENTS;
+ALTER TABLE {schema}PRODUCTS DROP COLUMN low_stock_threshold;
+ALTER TABLE {schema}PRODUCTS DROP COLUMN stock;
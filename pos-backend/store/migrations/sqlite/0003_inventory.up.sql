@@ -0,0 +1,16 @@
+-- Stok per produk (NULL berarti stok tidak dilacak) dan riwayat pergerakan stok
+ALTER TABLE {schema}PRODUCTS ADD COLUMN stock INTEGER;
+ALTER TABLE {schema}PRODUCTS ADD COLUMN low_stock_threshold INTEGER NOT NULL DEFAULT 5;
+
+CREATE TABLE {schema}STOCK_MOVEMENTS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INTEGER NOT NULL REFERENCES PRODUCTS (id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    order_id INTEGER REFERENCES ORDERS (id) ON DELETE SET NULL,
+    note TEXT,
+    username TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX {schema}idx_stock_movements_product ON STOCK_MOVEMENTS (product_id);
@@ -0,0 +1,14 @@
+-- Pembayaran per order, satu baris per tender
+CREATE TABLE {schema}PAYMENTS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL REFERENCES ORDERS (id) ON DELETE CASCADE,
+    method TEXT NOT NULL,
+    amount REAL NOT NULL,
+    tendered REAL NOT NULL,
+    change_due REAL NOT NULL DEFAULT 0,
+    reference TEXT,
+    username TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX {schema}idx_payments_order ON PAYMENTS (order_id);
@@ -0,0 +1,12 @@
+-- Riwayat perpindahan status order
+CREATE TABLE {schema}ORDER_STATUS_HISTORY (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL REFERENCES ORDERS (id) ON DELETE CASCADE,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    username TEXT,
+    reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX {schema}idx_order_status_history_order ON ORDER_STATUS_HISTORY (order_id);
@@ -0,0 +1,25 @@
+-- Refund untuk order yang sudah selesai, penuh atau per baris
+CREATE TABLE {schema}REFUNDS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL REFERENCES ORDERS (id) ON DELETE CASCADE,
+    method TEXT NOT NULL,
+    amount REAL NOT NULL,
+    reason TEXT NOT NULL,
+    note TEXT,
+    restock INTEGER NOT NULL DEFAULT 0,
+    username TEXT,
+    approved_by TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX {schema}idx_refunds_order ON REFUNDS (order_id);
+
+CREATE TABLE {schema}REFUND_ITEMS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    refund_id INTEGER NOT NULL REFERENCES REFUNDS (id) ON DELETE CASCADE,
+    order_detail_id INTEGER NOT NULL REFERENCES ORDER_DETAILS (id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL,
+    amount REAL NOT NULL
+);
+
+CREATE INDEX {schema}idx_refund_items_refund ON REFUND_ITEMS (refund_id);
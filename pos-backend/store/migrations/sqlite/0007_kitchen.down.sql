@@ -0,0 +1,2 @@
+ALTER TABLE {schema}ORDER_DETAILS DROP COLUMN prepared_by;
+ALTER TABLE {schema}ORDER_DETAILS DROP COLUMN prepared_at;
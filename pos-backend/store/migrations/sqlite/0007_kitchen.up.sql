@@ -0,0 +1,3 @@
+-- Tanda baris order yang sudah disiapkan dapur
+ALTER TABLE {schema}ORDER_DETAILS ADD COLUMN prepared_at TIMESTAMP;
+ALTER TABLE {schema}ORDER_DETAILS ADD COLUMN prepared_by TEXT;
@@ -0,0 +1,3 @@
+DROP INDEX {schema}idx_products_sku;
+ALTER TABLE {schema}PRODUCTS DROP COLUMN category;
+ALTER TABLE {schema}PRODUCTS DROP COLUMN sku;
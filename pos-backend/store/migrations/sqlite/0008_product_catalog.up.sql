@@ -0,0 +1,5 @@
+-- SKU dan kategori produk untuk import massal
+ALTER TABLE {schema}PRODUCTS ADD COLUMN sku TEXT;
+ALTER TABLE {schema}PRODUCTS ADD COLUMN category TEXT;
+
+CREATE UNIQUE INDEX {schema}idx_products_sku ON PRODUCTS (sku);
@@ -0,0 +1,9 @@
+ALTER TABLE {schema}PRODUCTS ADD COLUMN category TEXT;
+
+UPDATE {schema}PRODUCTS
+SET category = (SELECT c.name FROM CATEGORIES c WHERE c.id = PRODUCTS.category_id)
+WHERE category_id IS NOT NULL;
+
+DROP INDEX {schema}idx_products_category;
+ALTER TABLE {schema}PRODUCTS DROP COLUMN category_id;
+DROP TABLE {schema}CATEGORIES;
@@ -0,0 +1,22 @@
+-- Kategori menu, sort_order menentukan urutan di layar kasir
+CREATE TABLE {schema}CATEGORIES (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+ALTER TABLE {schema}PRODUCTS ADD COLUMN category_id INTEGER;
+
+-- Kategori teks dari import dipindah ke tabel CATEGORIES
+INSERT INTO {schema}CATEGORIES (name)
+SELECT DISTINCT category FROM PRODUCTS WHERE category IS NOT NULL;
+
+UPDATE {schema}CATEGORIES SET sort_order = id;
+
+UPDATE {schema}PRODUCTS
+SET category_id = (SELECT c.id FROM CATEGORIES c WHERE c.name = PRODUCTS.category)
+WHERE category IS NOT NULL;
+
+ALTER TABLE {schema}PRODUCTS DROP COLUMN category;
+
+CREATE INDEX {schema}idx_products_category ON PRODUCTS (category_id);
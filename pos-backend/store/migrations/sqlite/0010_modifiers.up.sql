@@ -0,0 +1,34 @@
+-- Grup modifier per produk (ukuran, level pedas, topping) dan pilihannya
+CREATE TABLE {schema}MODIFIER_GROUPS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INTEGER NOT NULL REFERENCES PRODUCTS (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    required INTEGER NOT NULL DEFAULT 0,
+    min_select INTEGER NOT NULL DEFAULT 0,
+    max_select INTEGER NOT NULL DEFAULT 0,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX {schema}idx_modifier_groups_product ON MODIFIER_GROUPS (product_id);
+
+CREATE TABLE {schema}MODIFIER_OPTIONS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    group_id INTEGER NOT NULL REFERENCES MODIFIER_GROUPS (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    price_delta REAL NOT NULL DEFAULT 0,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX {schema}idx_modifier_options_group ON MODIFIER_OPTIONS (group_id);
+
+-- Snapshot modifier yang dipilih pada setiap baris order
+CREATE TABLE {schema}ORDER_DETAIL_MODIFIERS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_detail_id INTEGER NOT NULL REFERENCES ORDER_DETAILS (id) ON DELETE CASCADE,
+    option_id INTEGER,
+    group_name TEXT NOT NULL,
+    option_name TEXT NOT NULL,
+    price_delta REAL NOT NULL DEFAULT 0
+);
+
+CREATE INDEX {schema}idx_detail_modifiers_detail ON ORDER_DETAIL_MODIFIERS (order_detail_id);
@@ -0,0 +1,46 @@
+-- Aturan promo: diskon persen/nominal per baris atau per order, beli X
+-- gratis Y, kode promo dengan batas pemakaian dan jam happy hour
+CREATE TABLE {schema}PROMOTIONS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    scope TEXT NOT NULL,
+    value REAL NOT NULL DEFAULT 0,
+    product_id INTEGER REFERENCES PRODUCTS (id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES CATEGORIES (id) ON DELETE CASCADE,
+    buy_quantity INTEGER NOT NULL DEFAULT 0,
+    get_quantity INTEGER NOT NULL DEFAULT 0,
+    min_subtotal REAL NOT NULL DEFAULT 0,
+    code TEXT UNIQUE,
+    usage_limit INTEGER NOT NULL DEFAULT 0,
+    usage_count INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    days TEXT,
+    start_time TEXT,
+    end_time TEXT,
+    active INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Promo yang dipakai setiap order, order_detail_id kosong untuk diskon order
+CREATE TABLE {schema}ORDER_PROMOTIONS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL REFERENCES ORDERS (id) ON DELETE CASCADE,
+    promotion_id INTEGER,
+    order_detail_id INTEGER,
+    name TEXT NOT NULL,
+    code TEXT,
+    amount REAL NOT NULL
+);
+
+CREATE INDEX {schema}idx_order_promotions_order ON ORDER_PROMOTIONS (order_id);
+
+-- total_price = subtotal - discount
+ALTER TABLE {schema}ORDERS ADD COLUMN subtotal REAL;
+ALTER TABLE {schema}ORDERS ADD COLUMN discount REAL NOT NULL DEFAULT 0;
+
+UPDATE {schema}ORDERS SET subtotal = total_price;
+
+-- Bagian diskon (promo baris dan bagian diskon order) pada setiap baris
+ALTER TABLE {schema}ORDER_DETAILS ADD COLUMN discount REAL NOT NULL DEFAULT 0;
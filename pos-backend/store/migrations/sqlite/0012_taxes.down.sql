@@ -0,0 +1,8 @@
+ALTER TABLE {schema}ORDER_DETAILS DROP COLUMN tax_included;
+ALTER TABLE {schema}ORDER_DETAILS DROP COLUMN tax;
+ALTER TABLE {schema}ORDER_DETAILS DROP COLUMN service_charge;
+ALTER TABLE {schema}ORDERS DROP COLUMN tax_included;
+ALTER TABLE {schema}ORDERS DROP COLUMN tax;
+ALTER TABLE {schema}ORDERS DROP COLUMN service_charge;
+DROP TABLE {schema}ORDER_TAXES;
+DROP TABLE {schema}TAX_RULES;
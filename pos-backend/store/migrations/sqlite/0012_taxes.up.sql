@@ -0,0 +1,40 @@
+-- Aturan pajak (PB1/PPN) dan service charge. Aturan tanpa produk dan
+-- kategori berlaku untuk semua produk. inclusive = 1 berarti harga produk
+-- sudah termasuk pajak sehingga pajak tidak ditambahkan ke total.
+CREATE TABLE {schema}TAX_RULES (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    rate REAL NOT NULL,
+    inclusive INTEGER NOT NULL DEFAULT 0,
+    product_id INTEGER REFERENCES PRODUCTS (id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES CATEGORIES (id) ON DELETE CASCADE,
+    active INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Pajak dan service charge yang dihitung untuk setiap order
+CREATE TABLE {schema}ORDER_TAXES (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL REFERENCES ORDERS (id) ON DELETE CASCADE,
+    tax_rule_id INTEGER,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    rate REAL NOT NULL,
+    inclusive INTEGER NOT NULL DEFAULT 0,
+    base REAL NOT NULL,
+    amount REAL NOT NULL
+);
+
+CREATE INDEX {schema}idx_order_taxes_order ON ORDER_TAXES (order_id);
+
+-- total_price = subtotal - discount + service_charge + tax,
+-- tax_included adalah pajak yang sudah termasuk dalam harga
+ALTER TABLE {schema}ORDERS ADD COLUMN service_charge REAL NOT NULL DEFAULT 0;
+ALTER TABLE {schema}ORDERS ADD COLUMN tax REAL NOT NULL DEFAULT 0;
+ALTER TABLE {schema}ORDERS ADD COLUMN tax_included REAL NOT NULL DEFAULT 0;
+
+-- Bagian service charge dan pajak setiap baris, dipakai saat refund
+ALTER TABLE {schema}ORDER_DETAILS ADD COLUMN service_charge REAL NOT NULL DEFAULT 0;
+ALTER TABLE {schema}ORDER_DETAILS ADD COLUMN tax REAL NOT NULL DEFAULT 0;
+ALTER TABLE {schema}ORDER_DETAILS ADD COLUMN tax_included REAL NOT NULL DEFAULT 0;
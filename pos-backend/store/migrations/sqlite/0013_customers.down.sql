@@ -0,0 +1,6 @@
+DROP INDEX {schema}idx_orders_customer;
+ALTER TABLE {schema}ORDERS DROP COLUMN points_redeemed;
+ALTER TABLE {schema}ORDERS DROP COLUMN points_earned;
+ALTER TABLE {schema}ORDERS DROP COLUMN customer_id;
+DROP TABLE {schema}POINT_MOVEMENTS;
+DROP TABLE {schema}CUSTOMERS;
@@ -0,0 +1,31 @@
+-- Pelanggan dan saldo poin loyalty
+CREATE TABLE {schema}CUSTOMERS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    phone TEXT UNIQUE,
+    email TEXT,
+    points INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Riwayat poin, positif berarti poin bertambah
+CREATE TABLE {schema}POINT_MOVEMENTS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    customer_id INTEGER NOT NULL REFERENCES CUSTOMERS (id) ON DELETE CASCADE,
+    points INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    order_id INTEGER REFERENCES ORDERS (id) ON DELETE SET NULL,
+    note TEXT,
+    username TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX {schema}idx_point_movements_customer ON POINT_MOVEMENTS (customer_id);
+CREATE INDEX {schema}idx_point_movements_order ON POINT_MOVEMENTS (order_id);
+
+-- points_redeemed adalah poin yang ditukar sebagai diskon order
+ALTER TABLE {schema}ORDERS ADD COLUMN customer_id INTEGER;
+ALTER TABLE {schema}ORDERS ADD COLUMN points_earned INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE {schema}ORDERS ADD COLUMN points_redeemed INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX {schema}idx_orders_customer ON ORDERS (customer_id);
@@ -0,0 +1,36 @@
+-- Shift kasir: modal awal laci, uang yang dihitung saat tutup dan selisihnya
+CREATE TABLE {schema}SHIFTS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username TEXT NOT NULL,
+    opening_float REAL NOT NULL DEFAULT 0,
+    opened_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP,
+    closed_by TEXT,
+    expected_cash REAL,
+    counted_cash REAL,
+    note TEXT
+);
+
+CREATE INDEX {schema}idx_shifts_user ON SHIFTS (username, closed_at);
+
+-- Uang masuk/keluar laci di luar penjualan, amount selalu positif
+CREATE TABLE {schema}CASH_MOVEMENTS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    shift_id INTEGER NOT NULL REFERENCES SHIFTS (id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    amount REAL NOT NULL,
+    note TEXT,
+    username TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX {schema}idx_cash_movements_shift ON CASH_MOVEMENTS (shift_id);
+
+-- Order, pembayaran dan refund dicatat pada shift kasir yang sedang buka
+ALTER TABLE {schema}ORDERS ADD COLUMN shift_id INTEGER;
+ALTER TABLE {schema}PAYMENTS ADD COLUMN shift_id INTEGER;
+ALTER TABLE {schema}REFUNDS ADD COLUMN shift_id INTEGER;
+
+CREATE INDEX {schema}idx_orders_shift ON ORDERS (shift_id);
+CREATE INDEX {schema}idx_payments_shift ON PAYMENTS (shift_id);
+CREATE INDEX {schema}idx_refunds_shift ON REFUNDS (shift_id);
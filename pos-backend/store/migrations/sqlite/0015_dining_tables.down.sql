@@ -0,0 +1,6 @@
+DROP INDEX {schema}idx_orders_table;
+ALTER TABLE {schema}ORDERS DROP COLUMN delivery_address;
+ALTER TABLE {schema}ORDERS DROP COLUMN table_name;
+ALTER TABLE {schema}ORDERS DROP COLUMN table_id;
+ALTER TABLE {schema}ORDERS DROP COLUMN order_type;
+DROP TABLE {schema}DINING_TABLES;
@@ -0,0 +1,17 @@
+-- Meja untuk order dine-in, status terisi/kosong dihitung dari order yang masih buka
+CREATE TABLE {schema}DINING_TABLES (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    seats INTEGER NOT NULL DEFAULT 0,
+    area TEXT,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+-- Jenis order (dine_in, takeaway, delivery); table_name disalin supaya
+-- riwayat tetap terbaca setelah meja dihapus
+ALTER TABLE {schema}ORDERS ADD COLUMN order_type TEXT NOT NULL DEFAULT 'takeaway';
+ALTER TABLE {schema}ORDERS ADD COLUMN table_id INTEGER;
+ALTER TABLE {schema}ORDERS ADD COLUMN table_name TEXT;
+ALTER TABLE {schema}ORDERS ADD COLUMN delivery_address TEXT;
+
+CREATE INDEX {schema}idx_orders_table ON ORDERS (table_id, status);
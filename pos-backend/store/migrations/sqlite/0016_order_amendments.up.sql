@@ -0,0 +1,18 @@
+-- Perubahan baris order yang masih berjalan (tambah, ubah jumlah, void)
+-- beserta total order sebelum dan sesudahnya
+CREATE TABLE {schema}ORDER_AMENDMENTS (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL REFERENCES ORDERS (id) ON DELETE CASCADE,
+    order_detail_id INTEGER,
+    action TEXT NOT NULL,
+    product_name TEXT NOT NULL,
+    old_quantity INTEGER NOT NULL DEFAULT 0,
+    new_quantity INTEGER NOT NULL DEFAULT 0,
+    old_total REAL NOT NULL,
+    new_total REAL NOT NULL,
+    username TEXT,
+    reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX {schema}idx_order_amendments_order ON ORDER_AMENDMENTS (order_id);
@@ -0,0 +1,4 @@
+-- Order hasil split bill menunjuk ke order asalnya
+ALTER TABLE {schema}ORDERS ADD COLUMN parent_order_id INTEGER;
+
+CREATE INDEX {schema}idx_orders_parent ON ORDERS (parent_order_id);
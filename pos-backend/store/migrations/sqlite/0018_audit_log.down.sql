@@ -0,0 +1 @@
+DROP TABLE {schema}AUDIT_LOG;
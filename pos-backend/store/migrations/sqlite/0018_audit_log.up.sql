@@ -0,0 +1,23 @@
+-- Log audit append-only untuk setiap request yang mengubah data. prev_hash
+-- unik supaya rantai hash tidak bercabang saat dua request menulis bersamaan.
+CREATE TABLE {schema}AUDIT_LOG (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor TEXT,
+    actor_role TEXT,
+    action TEXT NOT NULL,
+    method TEXT NOT NULL,
+    path TEXT NOT NULL,
+    entity TEXT,
+    entity_id TEXT,
+    before_value TEXT,
+    after_value TEXT,
+    status INTEGER NOT NULL,
+    ip TEXT,
+    created_at TIMESTAMP NOT NULL,
+    prev_hash TEXT NOT NULL UNIQUE,
+    hash TEXT NOT NULL
+);
+
+CREATE INDEX {schema}idx_audit_log_entity ON AUDIT_LOG (entity, entity_id);
+CREATE INDEX {schema}idx_audit_log_actor ON AUDIT_LOG (actor, created_at);
+CREATE INDEX {schema}idx_audit_log_created ON AUDIT_LOG (created_at);
@@ -0,0 +1,355 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"pos-backend/models"
+)
+
+func (s *sqlStore) ModifierGroups(ctx context.Context, productID int) ([]models.ModifierGroup, error) {
+	groups, err := s.modifierGroupsByProduct(ctx, s.db, []int{productID})
+	if err != nil {
+		return nil, err
+	}
+	if groups[productID] == nil {
+		return []models.ModifierGroup{}, nil
+	}
+	return groups[productID], nil
+}
+
+// modifierGroupsByProduct memuat grup beserta pilihannya untuk banyak produk sekaligus
+func (s *sqlStore) modifierGroupsByProduct(ctx context.Context, q queryer, productIDs []int) (map[int][]models.ModifierGroup, error) {
+	var groups []models.ModifierGroup
+	err := forEachChunk(productIDs, func(marks string, args []interface{}) error {
+		rows, err := q.QueryContext(ctx, s.q(`
+			SELECT id, product_id, name, required, min_select, max_select, sort_order
+			FROM {schema}MODIFIER_GROUPS
+			WHERE product_id IN (`+marks+`)
+			ORDER BY sort_order ASC, id ASC`), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var g models.ModifierGroup
+			var required int
+			if err := rows.Scan(&g.ID, &g.ProductID, &g.Name, &required, &g.MinSelect, &g.MaxSelect, &g.SortOrder); err != nil {
+				return err
+			}
+			g.Required = required != 0
+			g.Options = []models.ModifierOption{}
+			groups = append(groups, g)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groupIDs := make([]int, len(groups))
+	index := make(map[int]int, len(groups))
+	for i, g := range groups {
+		groupIDs[i] = g.ID
+		index[g.ID] = i
+	}
+	err = forEachChunk(groupIDs, func(marks string, args []interface{}) error {
+		rows, err := q.QueryContext(ctx, s.q(`
+			SELECT id, group_id, name, price_delta, sort_order
+			FROM {schema}MODIFIER_OPTIONS
+			WHERE group_id IN (`+marks+`)
+			ORDER BY sort_order ASC, id ASC`), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var o models.ModifierOption
+			if err := rows.Scan(&o.ID, &o.GroupID, &o.Name, &o.PriceDelta, &o.SortOrder); err != nil {
+				return err
+			}
+			g := &groups[index[o.GroupID]]
+			g.Options = append(g.Options, o)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byProduct := map[int][]models.ModifierGroup{}
+	for _, g := range groups {
+		byProduct[g.ProductID] = append(byProduct[g.ProductID], g)
+	}
+	return byProduct, nil
+}
+
+// attachModifierGroups mengisi ModifierGroups setiap produk
+func (s *sqlStore) attachModifierGroups(ctx context.Context, products []models.Product) error {
+	ids := make([]int, len(products))
+	for i := range products {
+		ids[i] = products[i].ID
+	}
+	groups, err := s.modifierGroupsByProduct(ctx, s.db, ids)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		products[i].ModifierGroups = groups[products[i].ID]
+	}
+	return nil
+}
+
+// SaveModifierGroup membuat grup baru (ID 0) atau memperbarui grup yang ada.
+// Pilihan disinkronkan: pilihan ber-ID diperbarui, tanpa ID ditambahkan dan
+// yang tidak dikirim lagi dihapus.
+func (s *sqlStore) SaveModifierGroup(ctx context.Context, group *models.ModifierGroup) error {
+	if err := validateModifierGroup(group); err != nil {
+		return err
+	}
+
+	required := 0
+	if group.Required {
+		required = 1
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		var exists int
+		err := tx.QueryRowContext(ctx, s.q("SELECT id FROM {schema}PRODUCTS WHERE id = ?"), group.ProductID).Scan(&exists)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: product %d not found", ErrInvalid, group.ProductID)
+		} else if err != nil {
+			return err
+		}
+
+		if group.ID == 0 {
+			group.ID, err = s.dialect.insertReturningID(ctx, tx,
+				s.q("INSERT INTO {schema}MODIFIER_GROUPS (product_id, name, required, min_select, max_select, sort_order) VALUES (?, ?, ?, ?, ?, ?)"),
+				group.ProductID, group.Name, required, group.MinSelect, group.MaxSelect, group.SortOrder)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = expectRows(tx.ExecContext(ctx, s.q(`
+				UPDATE {schema}MODIFIER_GROUPS
+				SET name = ?, required = ?, min_select = ?, max_select = ?, sort_order = ?
+				WHERE id = ? AND product_id = ?`),
+				group.Name, required, group.MinSelect, group.MaxSelect, group.SortOrder, group.ID, group.ProductID))
+			if err != nil {
+				return err
+			}
+		}
+		return s.syncModifierOptions(ctx, tx, group)
+	})
+}
+
+func (s *sqlStore) syncModifierOptions(ctx context.Context, tx *sql.Tx, group *models.ModifierGroup) error {
+	rows, err := tx.QueryContext(ctx, s.q("SELECT id FROM {schema}MODIFIER_OPTIONS WHERE group_id = ?"), group.ID)
+	if err != nil {
+		return err
+	}
+	existing := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range group.Options {
+		o := &group.Options[i]
+		o.GroupID = group.ID
+		if o.ID == 0 {
+			o.ID, err = s.dialect.insertReturningID(ctx, tx,
+				s.q("INSERT INTO {schema}MODIFIER_OPTIONS (group_id, name, price_delta, sort_order) VALUES (?, ?, ?, ?)"),
+				group.ID, o.Name, o.PriceDelta, o.SortOrder)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if !existing[o.ID] {
+			return fmt.Errorf("%w: option %d does not belong to group %d", ErrInvalid, o.ID, group.ID)
+		}
+		delete(existing, o.ID)
+		if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}MODIFIER_OPTIONS SET name = ?, price_delta = ?, sort_order = ? WHERE id = ?"),
+			o.Name, o.PriceDelta, o.SortOrder, o.ID); err != nil {
+			return err
+		}
+	}
+
+	// Order lama tidak terpengaruh karena pilihan disimpan sebagai snapshot
+	for id := range existing {
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}MODIFIER_OPTIONS WHERE id = ?"), id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sqlStore) DeleteModifierGroup(ctx context.Context, id int) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}MODIFIER_OPTIONS WHERE group_id = ?"), id); err != nil {
+			return err
+		}
+		return expectRows(tx.ExecContext(ctx, s.q("DELETE FROM {schema}MODIFIER_GROUPS WHERE id = ?"), id))
+	})
+}
+
+// validateModifierGroup memastikan batas pilihan masuk akal sebelum disimpan
+func validateModifierGroup(group *models.ModifierGroup) error {
+	group.Name = strings.TrimSpace(group.Name)
+	switch {
+	case group.Name == "":
+		return fmt.Errorf("%w: modifier group name is required", ErrInvalid)
+	case len(group.Options) == 0:
+		return fmt.Errorf("%w: modifier group %s has no options", ErrInvalid, group.Name)
+	case group.MinSelect < 0 || group.MaxSelect < 0:
+		return fmt.Errorf("%w: min_select and max_select must not be negative", ErrInvalid)
+	case group.MaxSelect != 0 && group.MaxSelect < group.MinRequired():
+		return fmt.Errorf("%w: max_select must be at least %d", ErrInvalid, group.MinRequired())
+	case group.MinRequired() > len(group.Options):
+		return fmt.Errorf("%w: modifier group %s needs at least %d options", ErrInvalid, group.Name, group.MinRequired())
+	}
+	for i := range group.Options {
+		group.Options[i].Name = strings.TrimSpace(group.Options[i].Name)
+		if group.Options[i].Name == "" {
+			return fmt.Errorf("%w: option %d has no name", ErrInvalid, i+1)
+		}
+	}
+	return nil
+}
+
+// priceItem menghitung satu baris order dari harga produk dan modifier yang
+// dipilih, lalu memeriksa aturan wajib/min/max setiap grup. n adalah nomor
+// baris untuk pesan error.
+func (s *sqlStore) priceItem(ctx context.Context, tx *sql.Tx, n int, item models.OrderItem) (models.OrderDetail, error) {
+	detail := models.OrderDetail{ProductID: item.ProductID, Quantity: item.Quantity}
+	if item.ProductID <= 0 {
+		return detail, fmt.Errorf("%w: item %d has no product_id", ErrInvalid, n)
+	}
+	if item.Quantity <= 0 {
+		return detail, fmt.Errorf("%w: item %d has invalid quantity %d", ErrInvalid, n, item.Quantity)
+	}
+
+	err := tx.QueryRowContext(ctx, s.q("SELECT name, price FROM {schema}PRODUCTS WHERE id = ?"), item.ProductID).
+		Scan(&detail.ProductName, &detail.UnitPrice)
+	if err == sql.ErrNoRows {
+		return detail, fmt.Errorf("%w: product %d not found", ErrInvalid, item.ProductID)
+	} else if err != nil {
+		return detail, fmt.Errorf("failed to find product %d: %w", item.ProductID, err)
+	}
+
+	groups, err := s.modifierGroupsByProduct(ctx, tx, []int{item.ProductID})
+	if err != nil {
+		return detail, err
+	}
+
+	chosen := make(map[int]bool, len(item.ModifierOptionIDs))
+	for _, id := range item.ModifierOptionIDs {
+		if chosen[id] {
+			return detail, fmt.Errorf("%w: option %d is chosen twice for %s", ErrInvalid, id, detail.ProductName)
+		}
+		chosen[id] = true
+	}
+
+	// Modifier disimpan sesuai urutan grup dan pilihan di menu
+	for _, g := range groups[item.ProductID] {
+		count := 0
+		for _, o := range g.Options {
+			if !chosen[o.ID] {
+				continue
+			}
+			delete(chosen, o.ID)
+			count++
+			detail.UnitPrice += o.PriceDelta
+			detail.Modifiers = append(detail.Modifiers, models.OrderDetailModifier{
+				OptionID:   o.ID,
+				GroupName:  g.Name,
+				OptionName: o.Name,
+				PriceDelta: o.PriceDelta,
+			})
+		}
+		if count < g.MinRequired() {
+			return detail, fmt.Errorf("%w: %s needs at least %d choice(s) for %s", ErrInvalid, detail.ProductName, g.MinRequired(), g.Name)
+		}
+		if g.MaxSelect > 0 && count > g.MaxSelect {
+			return detail, fmt.Errorf("%w: %s allows at most %d choice(s) for %s", ErrInvalid, detail.ProductName, g.MaxSelect, g.Name)
+		}
+	}
+	for id := range chosen {
+		return detail, fmt.Errorf("%w: option %d is not available for %s", ErrInvalid, id, detail.ProductName)
+	}
+	if detail.UnitPrice < 0 {
+		return detail, fmt.Errorf("%w: price of %s with modifiers is negative", ErrInvalid, detail.ProductName)
+	}
+
+	detail.TotalPrice = detail.UnitPrice * float64(item.Quantity)
+	if item.TotalPrice != 0 && !moneyEqual(item.TotalPrice, detail.TotalPrice) {
+		return detail, fmt.Errorf("%w: total for %s is %.2f, expected %.2f", ErrInvalid, detail.ProductName, item.TotalPrice, detail.TotalPrice)
+	}
+	return detail, nil
+}
+
+// insertDetail menyimpan baris order beserta snapshot modifier-nya
+func (s *sqlStore) insertDetail(ctx context.Context, tx *sql.Tx, detail *models.OrderDetail) error {
+	var err error
+	detail.ID, err = s.dialect.insertReturningID(ctx, tx, s.q(`
+		INSERT INTO {schema}ORDER_DETAILS (order_id, product_id, product_name, unit_price, quantity, total_price, discount, service_charge, tax, tax_included)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		detail.OrderID, detail.ProductID, detail.ProductName, detail.UnitPrice, detail.Quantity, detail.TotalPrice,
+		detail.Discount, detail.ServiceCharge, detail.Tax, detail.TaxIncluded)
+	if err != nil {
+		return fmt.Errorf("failed to create order details: %w", err)
+	}
+
+	for i := range detail.Modifiers {
+		m := &detail.Modifiers[i]
+		m.OrderDetailID = detail.ID
+		m.ID, err = s.dialect.insertReturningID(ctx, tx,
+			s.q("INSERT INTO {schema}ORDER_DETAIL_MODIFIERS (order_detail_id, option_id, group_name, option_name, price_delta) VALUES (?, ?, ?, ?, ?)"),
+			m.OrderDetailID, m.OptionID, m.GroupName, m.OptionName, m.PriceDelta)
+		if err != nil {
+			return fmt.Errorf("failed to create order detail modifiers: %w", err)
+		}
+	}
+	return nil
+}
+
+// modifiersByDetail memuat snapshot modifier untuk banyak baris order sekaligus
+func (s *sqlStore) modifiersByDetail(ctx context.Context, q queryer, detailIDs []int) (map[int][]models.OrderDetailModifier, error) {
+	modifiers := map[int][]models.OrderDetailModifier{}
+	err := forEachChunk(detailIDs, func(marks string, args []interface{}) error {
+		rows, err := q.QueryContext(ctx, s.q(`
+			SELECT id, order_detail_id, option_id, group_name, option_name, price_delta
+			FROM {schema}ORDER_DETAIL_MODIFIERS
+			WHERE order_detail_id IN (`+marks+`)
+			ORDER BY id ASC`), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var m models.OrderDetailModifier
+			var optionID sql.NullInt64
+			if err := rows.Scan(&m.ID, &m.OrderDetailID, &optionID, &m.GroupName, &m.OptionName, &m.PriceDelta); err != nil {
+				return err
+			}
+			m.OptionID = int(optionID.Int64)
+			modifiers[m.OrderDetailID] = append(modifiers[m.OrderDetailID], m)
+		}
+		return rows.Err()
+	})
+	return modifiers, err
+}
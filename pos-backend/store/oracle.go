@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	_ "github.com/godror/godror"
+)
+
+type oracleDialect struct{}
+
+// Oracle memakai godror, bind :1 dan RETURNING ... INTO
+var Oracle Dialect = oracleDialect{}
+
+func (oracleDialect) Name() string       { return "oracle" }
+func (oracleDialect) driverName() string { return "godror" }
+
+func (oracleDialect) connString(dsn string) string { return dsn }
+
+func (oracleDialect) placeholder(n int) string {
+	return ":" + itoa(n)
+}
+
+func (d oracleDialect) insertReturningID(ctx context.Context, q queryer, query string, args ...interface{}) (int, error) {
+	var id int
+	query += " RETURNING id INTO " + d.placeholder(len(args)+1)
+	_, err := q.ExecContext(ctx, query, append(args, sql.Out{Dest: &id})...)
+	return id, err
+}
+
+func (oracleDialect) limit(n int) string {
+	return "FETCH FIRST " + itoa(n) + " ROWS ONLY"
+}
+
+func (oracleDialect) tableExists(ctx context.Context, q queryer, schema, table string) (bool, error) {
+	var n int
+	var err error
+	if schema == "" {
+		err = q.QueryRowContext(ctx, "SELECT COUNT(*) FROM user_tables WHERE table_name = :1", strings.ToUpper(table)).Scan(&n)
+	} else {
+		err = q.QueryRowContext(ctx, "SELECT COUNT(*) FROM all_tables WHERE owner = :1 AND table_name = :2", strings.ToUpper(schema), strings.ToUpper(table)).Scan(&n)
+	}
+	return n > 0, err
+}
+
+// DDL di Oracle selalu auto-commit
+func (oracleDialect) transactionalDDL() bool { return false }
+
+func (oracleDialect) forUpdate() string { return "FOR UPDATE" }
+
+func (oracleDialect) dateBucket(column, granularity string) string {
+	format := map[string]string{"week": "IW", "month": "MM"}[granularity]
+	if format == "" {
+		format = "DD"
+	}
+	return "TO_CHAR(TRUNC(" + column + ", '" + format + "'), 'YYYY-MM-DD')"
+}
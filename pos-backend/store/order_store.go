@@ -0,0 +1,444 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"pos-backend/models"
+)
+
+func (s *sqlStore) ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error) {
+	var where []string
+	var args []interface{}
+	if len(filter.Statuses) > 0 {
+		marks := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			marks[i] = "?"
+			args = append(args, status)
+		}
+		where = append(where, "status IN ("+strings.Join(marks, ", ")+")")
+	}
+	if filter.Type != "" {
+		where = append(where, "order_type = ?")
+		args = append(args, filter.Type)
+	}
+	if filter.Table != "" {
+		where = append(where, "LOWER(table_name) = LOWER(?)")
+		args = append(args, strings.TrimSpace(filter.Table))
+	}
+	query := "SELECT " + orderColumns + " FROM {schema}ORDERS"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id ASC"
+
+	rows, err := s.db.QueryContext(ctx, s.q(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []models.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadOrderLines(ctx, orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (s *sqlStore) GetOrder(ctx context.Context, id int) (*models.Order, error) {
+	order, err := scanOrder(s.db.QueryRowContext(ctx, s.q("SELECT "+orderColumns+" FROM {schema}ORDERS WHERE id = ?"), id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	orders := []models.Order{*order}
+	if err := s.loadOrderLines(ctx, orders); err != nil {
+		return nil, err
+	}
+	return &orders[0], nil
+}
+
+// loadOrderLines mengisi detail, pembayaran dan refund semua order
+// dengan satu query per tabel, bukan per order
+func (s *sqlStore) loadOrderLines(ctx context.Context, orders []models.Order) error {
+	ids := make([]int, len(orders))
+	for i := range orders {
+		ids[i] = orders[i].ID
+	}
+
+	details, err := s.detailsByOrder(ctx, s.db, ids)
+	if err != nil {
+		return err
+	}
+	payments, err := s.paymentsByOrder(ctx, s.db, ids)
+	if err != nil {
+		return err
+	}
+	refunds, err := s.refundsByOrder(ctx, s.db, ids)
+	if err != nil {
+		return err
+	}
+	promotions, err := s.promotionsByOrder(ctx, s.db, ids)
+	if err != nil {
+		return err
+	}
+	taxes, err := s.taxesByOrder(ctx, s.db, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range orders {
+		orders[i].Details = details[orders[i].ID]
+		orders[i].Payments = payments[orders[i].ID]
+		orders[i].Refunds = refunds[orders[i].ID]
+		orders[i].Promotions = promotions[orders[i].ID]
+		orders[i].Taxes = taxes[orders[i].ID]
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+const orderColumns = "id, menu, status, subtotal, discount, service_charge, tax, tax_included, total_price, customer_id, points_earned, points_redeemed, shift_id, order_type, table_id, table_name, delivery_address, parent_order_id, created_at"
+
+func scanOrder(row rowScanner) (*models.Order, error) {
+	var order models.Order
+	var menu sql.NullString
+	var subtotal, totalPrice sql.NullFloat64
+	var customerID, shiftID, tableID, parentID sql.NullInt64
+	var tableName, address sql.NullString
+	if err := row.Scan(&order.ID, &menu, &order.Status, &subtotal, &order.Discount, &order.ServiceCharge, &order.Tax, &order.TaxIncluded, &totalPrice,
+		&customerID, &order.PointsEarned, &order.RedeemPoints, &shiftID, &order.Type, &tableID, &tableName, &address, &parentID, &order.CreatedAt); err != nil {
+		return nil, err
+	}
+	order.Menu = menu.String
+	if customerID.Valid {
+		id := int(customerID.Int64)
+		order.CustomerID = &id
+	}
+	if shiftID.Valid {
+		id := int(shiftID.Int64)
+		order.ShiftID = &id
+	}
+	if tableID.Valid {
+		id := int(tableID.Int64)
+		order.TableID = &id
+	}
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		order.ParentOrderID = &id
+	}
+	order.TableName = tableName.String
+	order.DeliveryAddress = address.String
+	if totalPrice.Valid {
+		order.TotalPrice = &totalPrice.Float64
+	}
+	// Order sebelum promo tidak punya subtotal, nilainya sama dengan total
+	order.Subtotal = totalPrice.Float64
+	if subtotal.Valid {
+		order.Subtotal = subtotal.Float64
+	}
+	return &order, nil
+}
+
+const detailColumns = "id, order_id, product_id, product_name, unit_price, quantity, total_price, discount, service_charge, tax, tax_included, prepared_at, prepared_by"
+
+func scanOrderDetail(row rowScanner) (*models.OrderDetail, error) {
+	var detail models.OrderDetail
+	var productID sql.NullInt64
+	var unitPrice, totalPrice sql.NullFloat64
+	var preparedAt sql.NullTime
+	var preparedBy sql.NullString
+	if err := row.Scan(&detail.ID, &detail.OrderID, &productID, &detail.ProductName, &unitPrice, &detail.Quantity, &totalPrice, &detail.Discount, &detail.ServiceCharge, &detail.Tax, &detail.TaxIncluded, &preparedAt, &preparedBy); err != nil {
+		return nil, err
+	}
+	detail.ProductID = int(productID.Int64)
+	detail.UnitPrice = unitPrice.Float64
+	detail.TotalPrice = totalPrice.Float64
+	if preparedAt.Valid {
+		detail.PreparedAt = &preparedAt.Time
+	}
+	detail.PreparedBy = preparedBy.String
+	return &detail, nil
+}
+
+func (s *sqlStore) orderDetails(ctx context.Context, q queryer, orderID int) ([]models.OrderDetail, error) {
+	details, err := s.detailsByOrder(ctx, q, []int{orderID})
+	return details[orderID], err
+}
+
+func (s *sqlStore) detailsByOrder(ctx context.Context, q queryer, orderIDs []int) (map[int][]models.OrderDetail, error) {
+	details := map[int][]models.OrderDetail{}
+	err := forEachChunk(orderIDs, func(marks string, args []interface{}) error {
+		rows, err := q.QueryContext(ctx, s.q("SELECT "+detailColumns+" FROM {schema}ORDER_DETAILS WHERE order_id IN ("+marks+") ORDER BY id ASC"), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			detail, err := scanOrderDetail(rows)
+			if err != nil {
+				return err
+			}
+			details[detail.OrderID] = append(details[detail.OrderID], *detail)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var detailIDs []int
+	for _, lines := range details {
+		for _, d := range lines {
+			detailIDs = append(detailIDs, d.ID)
+		}
+	}
+	modifiers, err := s.modifiersByDetail(ctx, q, detailIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, lines := range details {
+		for i := range lines {
+			lines[i].Modifiers = modifiers[lines[i].ID]
+		}
+	}
+	return details, nil
+}
+
+func (s *sqlStore) CreateOrder(ctx context.Context, order *models.Order, username string) (int, error) {
+	if len(order.Items) == 0 {
+		return 0, fmt.Errorf("%w: order has no items", ErrInvalid)
+	}
+
+	// Order baru langsung diproses kecuali client meminta Draft
+	status := models.OrderStatusOnProgress
+	if order.Status == models.OrderStatusDraft {
+		status = models.OrderStatusDraft
+	}
+
+	var orderID int
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		if err := s.resolveOrderType(ctx, tx, order); err != nil {
+			return err
+		}
+		pricing, err := s.priceOrder(ctx, tx, order, time.Now())
+		if err != nil {
+			return err
+		}
+		clientTotal := order.TotalPrice
+		orderTotals(order, pricing)
+		total := *order.TotalPrice
+		// Total dari client harus sama dengan total setelah diskon, service charge dan pajak
+		if clientTotal != nil && !moneyEqual(*clientTotal, total) {
+			return fmt.Errorf("%w: order total is %.2f, expected %.2f", ErrInvalid, *clientTotal, total)
+		}
+
+		order.ShiftID, err = s.openShiftID(ctx, tx, username)
+		if err != nil {
+			return err
+		}
+		orderID, err = s.dialect.insertReturningID(ctx, tx, s.q(`
+			INSERT INTO {schema}ORDERS (subtotal, discount, service_charge, tax, tax_included, total_price, status, customer_id, points_redeemed, shift_id,
+				order_type, table_id, table_name, delivery_address)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+			order.Subtotal, order.Discount, order.ServiceCharge, order.Tax, order.TaxIncluded, total, status, nullInt(order.CustomerID), order.RedeemPoints, nullInt(order.ShiftID),
+			order.Type, nullInt(order.TableID), nullString(order.TableName), nullString(order.DeliveryAddress))
+		if err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+		if err := s.recordStatus(ctx, tx, orderID, "", status, username, ""); err != nil {
+			return err
+		}
+
+		details := pricing.details
+		for i := range details {
+			details[i].OrderID = orderID
+			if err := s.insertDetail(ctx, tx, &details[i]); err != nil {
+				return err
+			}
+			if err := s.takeStock(ctx, tx, orderID, details[i]); err != nil {
+				return err
+			}
+		}
+
+		// Hitung ulang supaya promo baris mendapat ID detail yang baru dibuat
+		order.ID = orderID
+		order.Status = status
+		orderTotals(order, pricing)
+		if err := s.saveAppliedPromotions(ctx, tx, order); err != nil {
+			return err
+		}
+		if order.RedeemPoints > 0 {
+			err := s.movePoints(ctx, tx, models.PointMovement{
+				CustomerID: *order.CustomerID,
+				Points:     -order.RedeemPoints,
+				Reason:     models.PointsRedeem,
+				OrderID:    &orderID,
+				Note:       "discount",
+				Username:   username,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return s.saveAppliedTaxes(ctx, tx, order)
+	})
+	return orderID, err
+}
+
+func (s *sqlStore) CancelOrder(ctx context.Context, id int, username, reason string) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := s.transitionOrder(ctx, tx, id, models.OrderStatusCanceled, username, reason); err != nil {
+			return err
+		}
+		if err := s.checkNoPayments(ctx, tx, id); err != nil {
+			return err
+		}
+		if err := s.releasePromoUsage(ctx, tx, id); err != nil {
+			return err
+		}
+		if err := s.returnRedeemedPoints(ctx, tx, id, username, "order canceled"); err != nil {
+			return err
+		}
+		return s.restoreStock(ctx, tx, id)
+	})
+}
+
+// checkNoPayments menolak membatalkan atau menghapus order yang sudah
+// menerima pembayaran, karena uangnya tetap tercatat di laporan dan shift.
+// Order seperti itu diselesaikan lalu di-refund.
+func (s *sqlStore) checkNoPayments(ctx context.Context, tx *sql.Tx, id int) error {
+	payments, err := s.orderPayments(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if len(payments) > 0 {
+		return fmt.Errorf("%w: order %d already has payments, complete and refund it instead", ErrConflict, id)
+	}
+	return nil
+}
+
+func (s *sqlStore) DeleteOrder(ctx context.Context, id int) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		var status string
+		err := tx.QueryRowContext(ctx, s.q("SELECT status FROM {schema}ORDERS WHERE id = ? "+s.dialect.forUpdate()), id).Scan(&status)
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		} else if err != nil {
+			return err
+		}
+		// Order yang sudah dibayar tercatat di pendapatan dan refund, jadi
+		// hanya order yang masih berjalan yang boleh dihapus
+		if !models.IsOpenOrderStatus(status) {
+			return fmt.Errorf("%w: order %d is %s, only open orders can be deleted; refund completed orders instead", ErrConflict, id, status)
+		}
+		if err := s.checkNoPayments(ctx, tx, id); err != nil {
+			return err
+		}
+		if err := s.restoreStock(ctx, tx, id); err != nil {
+			return err
+		}
+		// Kuota kode promo dan poin yang ditukar dikembalikan seperti saat
+		// order dibatalkan
+		if err := s.releasePromoUsage(ctx, tx, id); err != nil {
+			return err
+		}
+		if err := s.returnRedeemedPoints(ctx, tx, id, "", "order deleted"); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}ORDER_PROMOTIONS WHERE order_id = ?"), id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}ORDER_TAXES WHERE order_id = ?"), id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}POINT_MOVEMENTS SET order_id = NULL WHERE order_id = ?"), id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}ORDER_AMENDMENTS WHERE order_id = ?"), id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}ORDERS SET parent_order_id = NULL WHERE parent_order_id = ?"), id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}ORDER_DETAIL_MODIFIERS WHERE order_detail_id IN (SELECT id FROM {schema}ORDER_DETAILS WHERE order_id = ?)"), id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}ORDER_DETAILS WHERE order_id = ?"), id); err != nil {
+			return err
+		}
+		return expectRows(tx.ExecContext(ctx, s.q("DELETE FROM {schema}ORDERS WHERE id = ?"), id))
+	})
+}
+
+func (s *sqlStore) CountOrders(ctx context.Context, status string) (int, error) {
+	return s.count(ctx, "SELECT COUNT(*) FROM {schema}ORDERS WHERE status = ?", status)
+}
+
+// TopSellers mengurutkan produk dari order yang selesai atau yang semua bagiannya sudah dibayar berdasarkan jumlah terjual
+func (s *sqlStore) TopSellers(ctx context.Context, limit int) ([]models.TopSeller, error) {
+	return s.topSellers(ctx, limit, soldLineFilter, soldLineArgs()...)
+}
+
+func (s *sqlStore) topSellers(ctx context.Context, limit int, filter string, args ...interface{}) ([]models.TopSeller, error) {
+	query := `
+		SELECT d.product_name, SUM(d.quantity) AS total_sold, SUM(d.total_price - d.discount) AS revenue
+		FROM {schema}ORDER_DETAILS d
+		JOIN {schema}ORDERS o ON o.id = d.order_id
+		WHERE ` + filter + `
+		GROUP BY d.product_name
+		ORDER BY total_sold DESC, d.product_name
+		` + s.dialect.limit(limit)
+	rows, err := s.db.QueryContext(ctx, s.q(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sellers := []models.TopSeller{}
+	for rows.Next() {
+		var seller models.TopSeller
+		var revenue sql.NullFloat64
+		if err := rows.Scan(&seller.ProductName, &seller.TotalSold, &revenue); err != nil {
+			return nil, err
+		}
+		seller.Revenue = revenue.Float64
+		sellers = append(sellers, seller)
+	}
+	return sellers, rows.Err()
+}
+
+// TotalRevenue adalah total order yang selesai dikurangi semua refund
+func (s *sqlStore) TotalRevenue(ctx context.Context) (float64, error) {
+	var total float64
+	err := s.db.QueryRowContext(ctx, s.q(`
+		SELECT COALESCE(SUM(total_price), 0) - (
+			SELECT COALESCE(SUM(r.amount), 0)
+			FROM {schema}REFUNDS r
+			JOIN {schema}ORDERS ro ON ro.id = r.order_id
+			WHERE ro.status IN (?, ?)
+		)
+		FROM {schema}ORDERS
+		WHERE status IN (?, ?)
+	`), models.OrderStatusCompleted, models.OrderStatusRefunded, models.OrderStatusCompleted, models.OrderStatusRefunded).Scan(&total)
+	return total, err
+}
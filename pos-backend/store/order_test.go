@@ -0,0 +1,164 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"pos-backend/models"
+)
+
+func TestCreateOrderRejectsTamperedTotal(t *testing.T) {
+	st, ctx := openTestStore(t)
+	kopi := createTestProduct(t, ctx, st, models.Product{Name: "Kopi", Price: 20000})
+	if err := st.Taxes.SaveTaxRule(ctx, &models.TaxRule{Name: "PB1", Type: models.TaxTax, Rate: 10, Active: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, total := range []float64{22000, 20000, 1} {
+		total := total
+		_, err := st.Orders.CreateOrder(ctx, &models.Order{TotalPrice: &total, Items: []models.OrderItem{{ProductID: kopi, Quantity: 1}}}, "kasir")
+		if total == 22000 && err != nil {
+			t.Errorf("total %.2f: %v", total, err)
+		}
+		if total != 22000 && !errors.Is(err, ErrInvalid) {
+			t.Errorf("total %.2f: got %v, want ErrInvalid", total, err)
+		}
+	}
+}
+
+func TestDeleteOrder(t *testing.T) {
+	st, ctx := openTestStore(t)
+	stock := 10
+	kopi := createTestProduct(t, ctx, st, models.Product{Name: "Kopi", Price: 10000, Stock: &stock})
+
+	open, err := st.Orders.CreateOrder(ctx, &models.Order{Items: []models.OrderItem{{ProductID: kopi, Quantity: 2}}}, "kasir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Orders.DeleteOrder(ctx, open); err != nil {
+		t.Fatalf("delete open order: %v", err)
+	}
+	product, err := st.Products.GetProduct(ctx, kopi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *product.Stock != 10 {
+		t.Errorf("stock is %d after deleting an open order, want 10", *product.Stock)
+	}
+
+	completed := completeTestOrder(t, ctx, st, []models.OrderItem{{ProductID: kopi, Quantity: 1}})
+	refund := &models.Refund{OrderID: completed.ID, Reason: models.RefundReasonOther}
+	if err := st.Orders.RefundOrder(ctx, refund, 1e9); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Orders.DeleteOrder(ctx, completed.ID); !errors.Is(err, ErrConflict) {
+		t.Fatalf("delete refunded order: got %v, want ErrConflict", err)
+	}
+
+	// Refund yatim dari database lama tanpa foreign key tidak ikut dihitung
+	if _, err := st.DB.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.DB.ExecContext(ctx, "INSERT INTO REFUNDS (order_id, method, amount, reason) VALUES (999, 'cash', 5000, 'other')"); err != nil {
+		t.Fatal(err)
+	}
+	revenue, err := st.Orders.TotalRevenue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !moneyEqual(revenue, 0) {
+		t.Errorf("revenue is %.2f, want 0", revenue)
+	}
+}
+
+func TestCancelOrderWithPayments(t *testing.T) {
+	st, ctx := openTestStore(t)
+	kopi := createTestProduct(t, ctx, st, models.Product{Name: "Kopi", Price: 10000})
+
+	paid, err := st.Orders.CreateOrder(ctx, &models.Order{Items: []models.OrderItem{{ProductID: kopi, Quantity: 2}}}, "kasir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Orders.AddPayments(ctx, paid, []models.Payment{{Method: models.PaymentCash, Amount: 5000}}, "kasir"); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Orders.CancelOrder(ctx, paid, "kasir", ""); !errors.Is(err, ErrConflict) {
+		t.Fatalf("cancel paid order: got %v, want ErrConflict", err)
+	}
+	if err := st.Orders.DeleteOrder(ctx, paid); !errors.Is(err, ErrConflict) {
+		t.Fatalf("delete paid order: got %v, want ErrConflict", err)
+	}
+	order, err := st.Orders.GetOrder(ctx, paid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != models.OrderStatusOnProgress || len(order.Payments) != 1 {
+		t.Errorf("order is %s with %d payments, want %s with 1", order.Status, len(order.Payments), models.OrderStatusOnProgress)
+	}
+
+	unpaid, err := st.Orders.CreateOrder(ctx, &models.Order{Items: []models.OrderItem{{ProductID: kopi, Quantity: 1}}}, "kasir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Orders.CancelOrder(ctx, unpaid, "kasir", ""); err != nil {
+		t.Fatalf("cancel unpaid order: %v", err)
+	}
+}
+
+func TestDeleteOrderReleasesPromoCode(t *testing.T) {
+	st, ctx := openTestStore(t)
+	kopi := createTestProduct(t, ctx, st, models.Product{Name: "Kopi", Price: 10000})
+	promo := &models.Promotion{Name: "Hemat 10", Type: models.PromoPercent, Scope: models.PromoScopeOrder, Value: 10, Code: "HEMAT10", UsageLimit: 1, Active: true}
+	if err := st.Promotions.SavePromotion(ctx, promo); err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := st.Orders.CreateOrder(ctx, &models.Order{PromoCode: "HEMAT10", Items: []models.OrderItem{{ProductID: kopi, Quantity: 1}}}, "kasir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Orders.DeleteOrder(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	got, err := st.Promotions.GetPromotion(ctx, promo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UsageCount != 0 {
+		t.Errorf("usage count is %d after deleting the order, want 0", got.UsageCount)
+	}
+}
+
+func TestDeleteOrderReturnsRedeemedPoints(t *testing.T) {
+	st, ctx := openTestStore(t)
+	// Satu poin per 1000 belanja, satu poin bernilai 100
+	st.SetLoyalty(1000, 100)
+	kopi := createTestProduct(t, ctx, st, models.Product{Name: "Kopi", Price: 10000})
+	customer := &models.Customer{Name: "Budi", Phone: "0811"}
+	if err := st.Customers.CreateCustomer(ctx, customer); err != nil {
+		t.Fatal(err)
+	}
+
+	earned, err := st.Orders.CreateOrder(ctx, &models.Order{CustomerID: &customer.ID, Items: []models.OrderItem{{ProductID: kopi, Quantity: 1}}}, "kasir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Orders.CompleteOrder(ctx, earned, []models.Payment{{Method: models.PaymentCash, Amount: 10000}}, "kasir"); err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := st.Orders.CreateOrder(ctx, &models.Order{CustomerID: &customer.ID, RedeemPoints: 5, Items: []models.OrderItem{{ProductID: kopi, Quantity: 1}}}, "kasir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Orders.DeleteOrder(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	got, err := st.Customers.GetCustomer(ctx, customer.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Points != 10 {
+		t.Errorf("customer has %d points after deleting the order, want 10", got.Points)
+	}
+}
@@ -0,0 +1,246 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"pos-backend/models"
+)
+
+func (s *sqlStore) AddPayments(ctx context.Context, orderID int, payments []models.Payment, username string) (*models.PaymentSummary, error) {
+	var summary *models.PaymentSummary
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		summary, err = s.addPayments(ctx, tx, orderID, payments, username)
+		return err
+	})
+	return summary, err
+}
+
+func (s *sqlStore) CompleteOrder(ctx context.Context, orderID int, payments []models.Payment, username string) (*models.PaymentSummary, error) {
+	var summary *models.PaymentSummary
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		summary, err = s.addPayments(ctx, tx, orderID, payments, username)
+		if err != nil {
+			return err
+		}
+		if summary.AmountDue > 0 {
+			return fmt.Errorf("%w: payment is short by %.2f", ErrInvalid, summary.AmountDue)
+		}
+
+		if _, err = s.transitionOrder(ctx, tx, orderID, models.OrderStatusCompleted, username, ""); err != nil {
+			return err
+		}
+		return s.earnPoints(ctx, tx, orderID, username)
+	})
+	return summary, err
+}
+
+func (s *sqlStore) PaymentSummary(ctx context.Context, orderID int) (*models.PaymentSummary, error) {
+	var total sql.NullFloat64
+	err := s.db.QueryRowContext(ctx, s.q("SELECT total_price FROM {schema}ORDERS WHERE id = ?"), orderID).Scan(&total)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.orderPayments(ctx, s.db, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return summarizePayments(orderID, total.Float64, payments), nil
+}
+
+// RevenueByMethod menjumlahkan pembayaran order yang selesai per metode,
+// dikurangi refund yang dibayarkan lewat metode yang sama
+func (s *sqlStore) RevenueByMethod(ctx context.Context) ([]models.PaymentMethodTotal, error) {
+	query := `
+		SELECT p.method, COUNT(*), COALESCE(SUM(p.amount), 0)
+		FROM {schema}PAYMENTS p
+		JOIN {schema}ORDERS o ON o.id = p.order_id
+		WHERE o.status IN (?, ?)
+		GROUP BY p.method
+		ORDER BY p.method
+	`
+	rows, err := s.db.QueryContext(ctx, s.q(query), models.OrderStatusCompleted, models.OrderStatusRefunded)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []models.PaymentMethodTotal
+	index := map[string]int{}
+	for rows.Next() {
+		var t models.PaymentMethodTotal
+		if err := rows.Scan(&t.Method, &t.Count, &t.Total); err != nil {
+			return nil, err
+		}
+		index[t.Method] = len(totals)
+		totals = append(totals, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	refunds, err := s.db.QueryContext(ctx, s.q(`
+		SELECT r.method, COALESCE(SUM(r.amount), 0)
+		FROM {schema}REFUNDS r
+		JOIN {schema}ORDERS o ON o.id = r.order_id
+		WHERE o.status IN (?, ?)
+		GROUP BY r.method
+	`), models.OrderStatusCompleted, models.OrderStatusRefunded)
+	if err != nil {
+		return nil, err
+	}
+	defer refunds.Close()
+
+	for refunds.Next() {
+		var method string
+		var amount float64
+		if err := refunds.Scan(&method, &amount); err != nil {
+			return nil, err
+		}
+		// Order lama tanpa catatan pembayaran bisa di-refund lewat metode yang belum ada
+		i, ok := index[method]
+		if !ok {
+			i = len(totals)
+			index[method] = i
+			totals = append(totals, models.PaymentMethodTotal{Method: method})
+		}
+		totals[i].Refunded += amount
+		totals[i].Total -= amount
+	}
+	return totals, refunds.Err()
+}
+
+// addPayments menyimpan tender baru untuk order yang masih berjalan.
+// Tender non-tunai tidak boleh melebihi sisa tagihan; kelebihan tunai
+// menjadi kembalian.
+func (s *sqlStore) addPayments(ctx context.Context, tx *sql.Tx, orderID int, payments []models.Payment, username string) (*models.PaymentSummary, error) {
+	var status string
+	var total sql.NullFloat64
+	var customerID sql.NullInt64
+	// Kunci order supaya dua pembayaran bersamaan tidak melihat sisa tagihan yang sama
+	err := tx.QueryRowContext(ctx, s.q("SELECT status, total_price, customer_id FROM {schema}ORDERS WHERE id = ? "+s.dialect.forUpdate()), orderID).Scan(&status, &total, &customerID)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if !models.IsOpenOrderStatus(status) {
+		return nil, fmt.Errorf("%w: order %d is %s", ErrConflict, orderID, status)
+	}
+
+	existing, err := s.orderPayments(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	due := summarizePayments(orderID, total.Float64, existing).AmountDue
+	shiftID, err := s.openShiftID(ctx, tx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range payments {
+		if !models.IsValidPaymentMethod(p.Method) {
+			return nil, fmt.Errorf("%w: unknown payment method %q", ErrInvalid, p.Method)
+		}
+		if p.Amount <= 0 {
+			return nil, fmt.Errorf("%w: payment amount must be positive", ErrInvalid)
+		}
+		if due <= 0 {
+			return nil, fmt.Errorf("%w: order %d is already fully paid", ErrInvalid, orderID)
+		}
+
+		p.OrderID = orderID
+		p.Tendered = p.Amount
+		p.Username = username
+		if p.Method == models.PaymentCash {
+			if p.Amount > due {
+				p.Change = p.Amount - due
+				p.Amount = due
+			}
+		} else if p.Amount > due && !moneyEqual(p.Amount, due) {
+			return nil, fmt.Errorf("%w: %s payment of %.2f exceeds amount due %.2f", ErrInvalid, p.Method, p.Amount, due)
+		}
+		due -= p.Amount
+
+		if p.Method == models.PaymentPoints {
+			var customer *int
+			if customerID.Valid {
+				id := int(customerID.Int64)
+				customer = &id
+			}
+			points, err := s.payWithPoints(ctx, tx, orderID, customer, p)
+			if err != nil {
+				return nil, err
+			}
+			if p.Reference == "" {
+				p.Reference = fmt.Sprintf("%d points", points)
+			}
+		}
+
+		p.ID, err = s.dialect.insertReturningID(ctx, tx,
+			s.q("INSERT INTO {schema}PAYMENTS (order_id, method, amount, tendered, change_due, reference, username, shift_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"),
+			p.OrderID, p.Method, p.Amount, p.Tendered, p.Change, nullString(p.Reference), nullString(p.Username), nullInt(shiftID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to record payment: %w", err)
+		}
+		existing = append(existing, p)
+	}
+
+	return summarizePayments(orderID, total.Float64, existing), nil
+}
+
+func (s *sqlStore) orderPayments(ctx context.Context, q queryer, orderID int) ([]models.Payment, error) {
+	payments, err := s.paymentsByOrder(ctx, q, []int{orderID})
+	return payments[orderID], err
+}
+
+func (s *sqlStore) paymentsByOrder(ctx context.Context, q queryer, orderIDs []int) (map[int][]models.Payment, error) {
+	payments := map[int][]models.Payment{}
+	err := forEachChunk(orderIDs, func(marks string, args []interface{}) error {
+		rows, err := q.QueryContext(ctx, s.q("SELECT id, order_id, method, amount, tendered, change_due, reference, username, created_at FROM {schema}PAYMENTS WHERE order_id IN ("+marks+") ORDER BY id ASC"), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var p models.Payment
+			var reference, username sql.NullString
+			if err := rows.Scan(&p.ID, &p.OrderID, &p.Method, &p.Amount, &p.Tendered, &p.Change, &reference, &username, &p.CreatedAt); err != nil {
+				return err
+			}
+			p.Reference = reference.String
+			p.Username = username.String
+			payments[p.OrderID] = append(payments[p.OrderID], p)
+		}
+		return rows.Err()
+	})
+	return payments, err
+}
+
+func summarizePayments(orderID int, total float64, payments []models.Payment) *models.PaymentSummary {
+	summary := &models.PaymentSummary{
+		OrderID:    orderID,
+		TotalPrice: total,
+		Payments:   payments,
+	}
+	for _, p := range payments {
+		summary.AmountPaid += p.Amount
+		summary.ChangeDue += p.Change
+	}
+	summary.AmountDue = total - summary.AmountPaid
+	if summary.AmountDue < 0 || moneyEqual(summary.AmountDue, 0) {
+		summary.AmountDue = 0
+	}
+	if summary.Payments == nil {
+		summary.Payments = []models.Payment{}
+	}
+	return summary
+}
@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+type postgresDialect struct{}
+
+// Postgres memakai lib/pq dengan bind $1
+var Postgres Dialect = postgresDialect{}
+
+func (postgresDialect) Name() string       { return "postgres" }
+func (postgresDialect) driverName() string { return "postgres" }
+
+func (postgresDialect) connString(dsn string) string { return dsn }
+
+func (postgresDialect) placeholder(n int) string {
+	return "$" + itoa(n)
+}
+
+func (postgresDialect) insertReturningID(ctx context.Context, q queryer, query string, args ...interface{}) (int, error) {
+	var id int
+	err := q.QueryRowContext(ctx, query+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+func (postgresDialect) limit(n int) string {
+	return "LIMIT " + itoa(n)
+}
+
+func (postgresDialect) tableExists(ctx context.Context, q queryer, schema, table string) (bool, error) {
+	if schema == "" {
+		schema = "public"
+	}
+	var n int
+	err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = $1 AND table_name = $2",
+		strings.ToLower(schema), strings.ToLower(table)).Scan(&n)
+	return n > 0, err
+}
+
+func (postgresDialect) transactionalDDL() bool { return true }
+
+func (postgresDialect) forUpdate() string { return "FOR UPDATE" }
+
+func (postgresDialect) dateBucket(column, granularity string) string {
+	if granularity != "week" && granularity != "month" {
+		granularity = "day"
+	}
+	return "to_char(date_trunc('" + granularity + "', " + column + "), 'YYYY-MM-DD')"
+}
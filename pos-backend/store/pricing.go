@@ -0,0 +1,187 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"pos-backend/models"
+)
+
+// orderPricing adalah hasil perhitungan baris, promo dan pajak sebuah order
+// sebelum disimpan
+type orderPricing struct {
+	details []models.OrderDetail
+	promos  []appliedPromo
+	taxes   []appliedTax
+}
+
+// priceOrder menghitung semua baris, promo dan pajak order tanpa menyimpan apa pun
+func (s *sqlStore) priceOrder(ctx context.Context, tx *sql.Tx, order *models.Order, now time.Time) (*orderPricing, error) {
+	if len(order.Items) == 0 {
+		return nil, fmt.Errorf("%w: order has no items", ErrInvalid)
+	}
+
+	// Hitung ulang setiap baris dari harga produk dan modifier saat ini
+	details := make([]models.OrderDetail, len(order.Items))
+	for i, item := range order.Items {
+		detail, err := s.priceItem(ctx, tx, i+1, item)
+		if err != nil {
+			return nil, err
+		}
+		details[i] = detail
+	}
+	return s.adjustDetails(ctx, tx, order, details, now)
+}
+
+// pointsPromoName adalah nama diskon dari penukaran poin di ORDER_PROMOTIONS
+const pointsPromoName = "Tukar poin"
+
+// adjustDetails menerapkan promo, penukaran poin, lalu pajak dan service
+// charge pada baris order yang harganya sudah dihitung. Kode promo, pelanggan
+// dan poin yang ditukar diambil dari order. Untuk order yang sudah tersimpan
+// (ID bukan 0) kuota kode dan saldo poin tidak dicek ulang karena sudah
+// terpakai saat order dibuat.
+func (s *sqlStore) adjustDetails(ctx context.Context, tx *sql.Tx, order *models.Order, details []models.OrderDetail, now time.Time) (*orderPricing, error) {
+	if order.CustomerID != nil {
+		if _, err := s.customerPoints(ctx, tx, *order.CustomerID); err != nil {
+			return nil, err
+		}
+	}
+	promos, err := s.activePromotions(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.activeTaxRules(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.productCategories(ctx, tx, details)
+	if err != nil {
+		return nil, err
+	}
+
+	code := strings.TrimSpace(order.PromoCode)
+	if order.ID != 0 && code != "" {
+		// Kode yang sudah tidak berlaku dilepas dari order
+		code = ""
+		for i := range promos {
+			if strings.EqualFold(promos[i].Code, order.PromoCode) && promos[i].ActiveAt(now) {
+				promos[i].UsageLimit = 0
+				code = promos[i].Code
+			}
+		}
+		order.PromoCode = code
+	}
+
+	pricing := &orderPricing{details: details}
+	pricing.promos, err = applyPromotions(promos, details, categories, code, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Poin ditukar setelah promo sebagai diskon order
+	if order.RedeemPoints != 0 {
+		var net float64
+		for _, d := range details {
+			net += d.NetTotal()
+		}
+		amount, err := s.redeemDiscount(ctx, tx, order, roundMoney(net))
+		if err != nil {
+			return nil, err
+		}
+		spreadOrderDiscount(details, amount, net)
+		pricing.promos = append(pricing.promos, appliedPromo{promo: models.Promotion{Name: pointsPromoName}, line: -1, amount: amount})
+	}
+	pricing.taxes = applyTaxes(rules, details, categories)
+	return pricing, nil
+}
+
+// productCategories memetakan produk ke kategorinya untuk target promo dan pajak
+func (s *sqlStore) productCategories(ctx context.Context, q queryer, details []models.OrderDetail) (map[int]int, error) {
+	ids := make([]int, 0, len(details))
+	for _, d := range details {
+		ids = append(ids, d.ProductID)
+	}
+	categories := map[int]int{}
+	err := forEachChunk(ids, func(marks string, args []interface{}) error {
+		rows, err := q.QueryContext(ctx, s.q("SELECT id, category_id FROM {schema}PRODUCTS WHERE category_id IS NOT NULL AND id IN ("+marks+")"), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id, categoryID int
+			if err := rows.Scan(&id, &categoryID); err != nil {
+				return err
+			}
+			categories[id] = categoryID
+		}
+		return rows.Err()
+	})
+	return categories, err
+}
+
+// orderTotals mengisi total, detail, promo dan pajak order dari hasil perhitungan
+func orderTotals(order *models.Order, pricing *orderPricing) {
+	details := pricing.details
+	order.Subtotal, order.Discount, order.ServiceCharge, order.Tax, order.TaxIncluded = 0, 0, 0, 0, 0
+	for _, d := range details {
+		order.Subtotal += d.TotalPrice
+		order.Discount += d.Discount
+		order.ServiceCharge += d.ServiceCharge
+		order.Tax += d.Tax
+		order.TaxIncluded += d.TaxIncluded
+	}
+	order.Subtotal = roundMoney(order.Subtotal)
+	order.Discount = roundMoney(order.Discount)
+	order.ServiceCharge = roundMoney(order.ServiceCharge)
+	order.Tax = roundMoney(order.Tax)
+	order.TaxIncluded = roundMoney(order.TaxIncluded)
+	total := roundMoney(order.Subtotal - order.Discount + order.ServiceCharge + order.Tax)
+	order.TotalPrice = &total
+	order.Details = details
+
+	order.Promotions = nil
+	for _, a := range pricing.promos {
+		ap := models.AppliedPromotion{OrderID: order.ID, PromotionID: a.promo.ID, Name: a.promo.Name, Code: a.promo.Code, Amount: a.amount}
+		if a.line >= 0 && details[a.line].ID != 0 {
+			id := details[a.line].ID
+			ap.OrderDetailID = &id
+		}
+		order.Promotions = append(order.Promotions, ap)
+	}
+
+	order.Taxes = nil
+	for _, t := range pricing.taxes {
+		order.Taxes = append(order.Taxes, models.AppliedTax{
+			OrderID:   order.ID,
+			TaxRuleID: t.rule.ID,
+			Name:      t.rule.Name,
+			Type:      t.rule.Type,
+			Rate:      t.rule.Rate,
+			Inclusive: t.rule.Inclusive,
+			Base:      t.base,
+			Amount:    t.amount,
+		})
+	}
+}
+
+// PriceOrder menghitung harga, diskon, pajak dan total order tanpa menyimpannya
+func (s *sqlStore) PriceOrder(ctx context.Context, order *models.Order) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	pricing, err := s.priceOrder(ctx, tx, order, time.Now())
+	if err != nil {
+		return err
+	}
+	orderTotals(order, pricing)
+	return nil
+}
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"testing"
+
+	"pos-backend/models"
+)
+
+func TestPromotionStacking(t *testing.T) {
+	// kopi 20000 dan roti 10000
+	tests := []struct {
+		name     string
+		promos   func(kopi, roti int) []models.Promotion
+		code     string
+		items    func(kopi, roti int) []models.OrderItem
+		discount float64
+		total    float64
+	}{
+		{
+			name: "line and order promotions stack",
+			promos: func(kopi, roti int) []models.Promotion {
+				return []models.Promotion{
+					{Name: "Kopi 10%", Type: models.PromoPercent, Scope: models.PromoScopeLine, Value: 10, ProductID: &kopi, Active: true},
+					{Name: "Hemat 10%", Type: models.PromoPercent, Scope: models.PromoScopeOrder, Value: 10, Active: true},
+				}
+			},
+			items: func(kopi, roti int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: kopi, Quantity: 2}}
+			},
+			// 40000 - 4000 baris - 3600 order
+			discount: 7600,
+			total:    32400,
+		},
+		{
+			name: "largest line promotion wins",
+			promos: func(kopi, roti int) []models.Promotion {
+				return []models.Promotion{
+					{Name: "Kopi 10%", Type: models.PromoPercent, Scope: models.PromoScopeLine, Value: 10, ProductID: &kopi, Active: true},
+					{Name: "Kopi 3000", Type: models.PromoFixed, Scope: models.PromoScopeLine, Value: 3000, ProductID: &kopi, Active: true},
+				}
+			},
+			items: func(kopi, roti int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: kopi, Quantity: 2}}
+			},
+			discount: 6000,
+			total:    34000,
+		},
+		{
+			name: "line code beats larger automatic promotion",
+			promos: func(kopi, roti int) []models.Promotion {
+				return []models.Promotion{
+					{Name: "Kopi 25%", Type: models.PromoPercent, Scope: models.PromoScopeLine, Value: 25, ProductID: &kopi, Active: true},
+					{Name: "Kopi 5%", Type: models.PromoPercent, Scope: models.PromoScopeLine, Value: 5, ProductID: &kopi, Code: "KOPI5", Active: true},
+				}
+			},
+			code: "kopi5",
+			items: func(kopi, roti int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: kopi, Quantity: 2}}
+			},
+			discount: 2000,
+			total:    38000,
+		},
+		{
+			name: "largest order promotion wins",
+			promos: func(kopi, roti int) []models.Promotion {
+				return []models.Promotion{
+					{Name: "Hemat 10%", Type: models.PromoPercent, Scope: models.PromoScopeOrder, Value: 10, Active: true},
+					{Name: "Hemat 5000", Type: models.PromoFixed, Scope: models.PromoScopeOrder, Value: 5000, Active: true},
+					{Name: "Hemat 8000", Type: models.PromoFixed, Scope: models.PromoScopeOrder, Value: 8000, MinSubtotal: 50000, Active: true},
+				}
+			},
+			items: func(kopi, roti int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: kopi, Quantity: 2}}
+			},
+			discount: 5000,
+			total:    35000,
+		},
+		{
+			name: "buy x get y lines skip other line promotions",
+			promos: func(kopi, roti int) []models.Promotion {
+				return []models.Promotion{
+					{Name: "Kopi B1G1", Type: models.PromoBuyXGetY, BuyQuantity: 1, GetQuantity: 1, ProductID: &kopi, Active: true},
+					{Name: "Semua 10%", Type: models.PromoPercent, Scope: models.PromoScopeLine, Value: 10, Active: true},
+				}
+			},
+			items: func(kopi, roti int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: kopi, Quantity: 2}, {ProductID: roti, Quantity: 1}}
+			},
+			// kopi gratis satu, roti 10%
+			discount: 21000,
+			total:    29000,
+		},
+		{
+			name: "buy x get y then order code",
+			promos: func(kopi, roti int) []models.Promotion {
+				return []models.Promotion{
+					{Name: "Kopi B1G1", Type: models.PromoBuyXGetY, BuyQuantity: 1, GetQuantity: 1, ProductID: &kopi, Active: true},
+					{Name: "Hemat 10", Type: models.PromoPercent, Scope: models.PromoScopeOrder, Value: 10, Code: "HEMAT10", Active: true},
+				}
+			},
+			code: "HEMAT10",
+			items: func(kopi, roti int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: kopi, Quantity: 2}, {ProductID: roti, Quantity: 1}}
+			},
+			// 50000 - 20000 gratis - 3000 order
+			discount: 23000,
+			total:    27000,
+		},
+		{
+			name: "buy x get y with a large quantity",
+			promos: func(kopi, roti int) []models.Promotion {
+				return []models.Promotion{
+					{Name: "Kopi B1G1", Type: models.PromoBuyXGetY, BuyQuantity: 1, GetQuantity: 1, ProductID: &kopi, Active: true},
+				}
+			},
+			items: func(kopi, roti int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: kopi, Quantity: 2000000000}}
+			},
+			discount: 2e13,
+			total:    2e13,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ctx := openTestStore(t)
+			kopi := createTestProduct(t, ctx, st, models.Product{Name: "Kopi", Price: 20000})
+			roti := createTestProduct(t, ctx, st, models.Product{Name: "Roti", Price: 10000})
+			for _, p := range tt.promos(kopi, roti) {
+				if err := st.Promotions.SavePromotion(ctx, &p); err != nil {
+					t.Fatalf("save promotion %s: %v", p.Name, err)
+				}
+			}
+
+			order := &models.Order{PromoCode: tt.code, Items: tt.items(kopi, roti)}
+			if err := st.Orders.PriceOrder(ctx, order); err != nil {
+				t.Fatal(err)
+			}
+			if !moneyEqual(order.Discount, tt.discount) || !moneyEqual(*order.TotalPrice, tt.total) {
+				t.Errorf("discount %.2f total %.2f, want %.2f and %.2f", order.Discount, *order.TotalPrice, tt.discount, tt.total)
+			}
+		})
+	}
+}
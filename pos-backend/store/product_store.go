@@ -0,0 +1,346 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"pos-backend/models"
+)
+
+// productSelect mengambil produk beserta nama kategorinya
+const productSelect = `
+	SELECT p.id, p.name, p.price, p.image_url, p.sku, p.category_id, c.name, p.stock, p.low_stock_threshold
+	FROM {schema}PRODUCTS p
+	LEFT JOIN {schema}CATEGORIES c ON c.id = p.category_id`
+
+// productOrder menaruh produk tanpa kategori di akhir daftar
+const productOrder = " ORDER BY CASE WHEN c.id IS NULL THEN 1 ELSE 0 END, c.sort_order ASC, c.id ASC, p.id ASC"
+
+func scanProduct(row rowScanner) (*models.Product, error) {
+	var product models.Product
+	var imageURL, sku, category sql.NullString
+	var categoryID, stock sql.NullInt64
+	if err := row.Scan(&product.ID, &product.Name, &product.Price, &imageURL, &sku, &categoryID, &category, &stock, &product.LowStockThreshold); err != nil {
+		return nil, err
+	}
+	product.ImageURL = imageURL.String
+	product.SKU = sku.String
+	product.Category = category.String
+	if categoryID.Valid {
+		id := int(categoryID.Int64)
+		product.CategoryID = &id
+	}
+	if stock.Valid {
+		n := int(stock.Int64)
+		product.Stock = &n
+	}
+	return &product, nil
+}
+
+func (s *sqlStore) queryProducts(ctx context.Context, query string, args ...interface{}) ([]models.Product, error) {
+	rows, err := s.db.QueryContext(ctx, s.q(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []models.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, *product)
+	}
+	return products, rows.Err()
+}
+
+func (s *sqlStore) ListProducts(ctx context.Context, categoryID int) ([]models.Product, error) {
+	var products []models.Product
+	var err error
+	if categoryID != 0 {
+		products, err = s.queryProducts(ctx, productSelect+" WHERE p.category_id = ?"+productOrder, categoryID)
+	} else {
+		products, err = s.queryProducts(ctx, productSelect+productOrder)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return products, s.attachModifierGroups(ctx, products)
+}
+
+func (s *sqlStore) GetProduct(ctx context.Context, id int) (*models.Product, error) {
+	row := s.db.QueryRowContext(ctx, s.q(productSelect+" WHERE p.id = ?"), id)
+	product, err := scanProduct(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	products := []models.Product{*product}
+	if err := s.attachModifierGroups(ctx, products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+func (s *sqlStore) CreateProduct(ctx context.Context, product *models.Product) (int, error) {
+	var id int
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		id, err = s.insertProduct(ctx, tx, product)
+		return err
+	})
+	return id, err
+}
+
+// insertProduct menyimpan produk baru beserta stok awalnya di dalam tx
+func (s *sqlStore) insertProduct(ctx context.Context, tx *sql.Tx, product *models.Product) (int, error) {
+	if err := s.checkSKU(ctx, tx, product.SKU, 0); err != nil {
+		return 0, err
+	}
+	if err := s.resolveCategory(ctx, tx, product); err != nil {
+		return 0, err
+	}
+
+	id, err := s.dialect.insertReturningID(ctx, tx,
+		s.q("INSERT INTO {schema}PRODUCTS (name, price, image_url, sku, category_id, stock, low_stock_threshold) VALUES (?, ?, ?, ?, ?, ?, ?)"),
+		product.Name, product.Price, product.ImageURL, nullString(product.SKU), nullInt(product.CategoryID), nullInt(product.Stock), product.LowStockThreshold)
+	if err != nil {
+		return 0, err
+	}
+
+	// Stok awal dicatat sebagai restock supaya riwayat stok lengkap
+	if product.Stock != nil && *product.Stock != 0 {
+		err = s.insertStockMovement(ctx, tx, models.StockMovement{
+			ProductID: id,
+			Quantity:  *product.Stock,
+			Reason:    models.StockReasonRestock,
+			Note:      "stok awal",
+		})
+	}
+	return id, err
+}
+
+// checkSKU menolak SKU yang sudah dipakai produk lain
+func (s *sqlStore) checkSKU(ctx context.Context, q queryer, sku string, productID int) error {
+	if sku == "" {
+		return nil
+	}
+	var id int
+	err := q.QueryRowContext(ctx, s.q("SELECT id FROM {schema}PRODUCTS WHERE sku = ?"), sku).Scan(&id)
+	if err == sql.ErrNoRows || (err == nil && id == productID) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: SKU %s is already used by product %d", ErrConflict, sku, id)
+}
+
+func (s *sqlStore) UpdateProduct(ctx context.Context, product *models.Product) error {
+	if err := s.checkSKU(ctx, s.db, product.SKU, product.ID); err != nil {
+		return err
+	}
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return s.updateProduct(ctx, tx, product)
+	})
+}
+
+// updateProduct menyimpan perubahan produk; SKU, kategori dan gambar yang
+// kosong tetap memakai nilai lama
+func (s *sqlStore) updateProduct(ctx context.Context, q queryer, product *models.Product) error {
+	if err := s.resolveCategory(ctx, q, product); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE {schema}PRODUCTS
+		SET name = ?, price = ?, image_url = COALESCE(NULLIF(?, ''), image_url),
+			sku = COALESCE(?, sku), category_id = COALESCE(?, category_id), low_stock_threshold = ?
+		WHERE id = ?
+	`
+	return expectRows(q.ExecContext(ctx, s.q(query), product.Name, product.Price, product.ImageURL,
+		nullString(product.SKU), nullInt(product.CategoryID), product.LowStockThreshold, product.ID))
+}
+
+func (s *sqlStore) DeleteProduct(ctx context.Context, id int) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		// Modifier, promo dan aturan pajak produk ikut dihapus walaupun
+		// foreign key SQLite tidak aktif
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}PROMOTIONS WHERE product_id = ?"), id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}TAX_RULES WHERE product_id = ?"), id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}MODIFIER_OPTIONS WHERE group_id IN (SELECT id FROM {schema}MODIFIER_GROUPS WHERE product_id = ?)"), id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}MODIFIER_GROUPS WHERE product_id = ?"), id); err != nil {
+			return err
+		}
+		return expectRows(tx.ExecContext(ctx, s.q("DELETE FROM {schema}PRODUCTS WHERE id = ?"), id))
+	})
+}
+
+func (s *sqlStore) CountProducts(ctx context.Context) (int, error) {
+	return s.count(ctx, "SELECT COUNT(*) FROM {schema}PRODUCTS")
+}
+
+func (s *sqlStore) LowStockProducts(ctx context.Context) ([]models.Product, error) {
+	return s.queryProducts(ctx, productSelect+" WHERE p.stock IS NOT NULL AND p.stock <= p.low_stock_threshold ORDER BY p.stock ASC, p.id ASC")
+}
+
+func (s *sqlStore) AdjustStock(ctx context.Context, adj models.StockAdjustment, username string) (*models.StockMovement, error) {
+	if adj.Quantity < 0 {
+		return nil, fmt.Errorf("%w: quantity must not be negative", ErrInvalid)
+	}
+
+	movement := models.StockMovement{
+		ProductID: adj.ProductID,
+		Reason:    adj.Reason,
+		Note:      adj.Note,
+		Username:  username,
+	}
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var stock sql.NullInt64
+		// Kunci produk supaya penjualan bersamaan tidak tertimpa stok absolut di bawah
+		err := tx.QueryRowContext(ctx, s.q("SELECT stock FROM {schema}PRODUCTS WHERE id = ? "+s.dialect.forUpdate()), adj.ProductID).Scan(&stock)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: product %d", ErrNotFound, adj.ProductID)
+		} else if err != nil {
+			return err
+		}
+		current := int(stock.Int64)
+
+		switch adj.Reason {
+		case models.StockReasonRestock:
+			movement.Quantity = adj.Quantity
+		case models.StockReasonWaste:
+			if adj.Quantity > current {
+				return fmt.Errorf("%w: cannot waste %d, only %d in stock", ErrInvalid, adj.Quantity, current)
+			}
+			movement.Quantity = -adj.Quantity
+		case models.StockReasonCorrection:
+			movement.Quantity = adj.Quantity - current
+		default:
+			return fmt.Errorf("%w: unknown reason %q", ErrInvalid, adj.Reason)
+		}
+
+		// Stok yang belum dilacak mulai dilacak sejak penyesuaian pertama
+		_, err = tx.ExecContext(ctx, s.q("UPDATE {schema}PRODUCTS SET stock = ? WHERE id = ?"), current+movement.Quantity, adj.ProductID)
+		if err != nil {
+			return err
+		}
+		return s.insertStockMovement(ctx, tx, movement)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &movement, nil
+}
+
+func (s *sqlStore) StockMovements(ctx context.Context, productID int) ([]models.StockMovement, error) {
+	rows, err := s.db.QueryContext(ctx, s.q("SELECT id, product_id, quantity, reason, order_id, note, username, created_at FROM {schema}STOCK_MOVEMENTS WHERE product_id = ? ORDER BY id DESC"), productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []models.StockMovement
+	for rows.Next() {
+		var m models.StockMovement
+		var orderID sql.NullInt64
+		var note, username sql.NullString
+		if err := rows.Scan(&m.ID, &m.ProductID, &m.Quantity, &m.Reason, &orderID, &note, &username, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		if orderID.Valid {
+			id := int(orderID.Int64)
+			m.OrderID = &id
+		}
+		m.Note = note.String
+		m.Username = username.String
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+func (s *sqlStore) insertStockMovement(ctx context.Context, q queryer, m models.StockMovement) error {
+	_, err := q.ExecContext(ctx, s.q("INSERT INTO {schema}STOCK_MOVEMENTS (product_id, quantity, reason, order_id, note, username) VALUES (?, ?, ?, ?, ?, ?)"),
+		m.ProductID, m.Quantity, m.Reason, nullInt(m.OrderID), nullString(m.Note), nullString(m.Username))
+	return err
+}
+
+// takeStock mengurangi stok untuk penjualan. Produk yang stoknya tidak
+// dilacak dilewati; stok yang tidak cukup menghasilkan ErrInvalid.
+func (s *sqlStore) takeStock(ctx context.Context, tx *sql.Tx, orderID int, detail models.OrderDetail) error {
+	result, err := tx.ExecContext(ctx, s.q("UPDATE {schema}PRODUCTS SET stock = stock - ? WHERE id = ? AND stock IS NOT NULL AND stock >= ?"),
+		detail.Quantity, detail.ProductID, detail.Quantity)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 1 {
+		return s.insertStockMovement(ctx, tx, models.StockMovement{
+			ProductID: detail.ProductID,
+			Quantity:  -detail.Quantity,
+			Reason:    models.StockReasonSale,
+			OrderID:   &orderID,
+		})
+	}
+
+	var stock sql.NullInt64
+	if err := tx.QueryRowContext(ctx, s.q("SELECT stock FROM {schema}PRODUCTS WHERE id = ?"), detail.ProductID).Scan(&stock); err != nil {
+		return err
+	}
+	if !stock.Valid {
+		return nil
+	}
+	return fmt.Errorf("%w: insufficient stock for %s (%d left, %d ordered)", ErrInvalid, detail.ProductName, stock.Int64, detail.Quantity)
+}
+
+// restoreStock mengembalikan stok yang diambil oleh penjualan sebuah order
+func (s *sqlStore) restoreStock(ctx context.Context, tx *sql.Tx, orderID int) error {
+	rows, err := tx.QueryContext(ctx, s.q("SELECT product_id, SUM(quantity) FROM {schema}STOCK_MOVEMENTS WHERE order_id = ? AND reason IN (?, ?, ?) GROUP BY product_id"),
+		orderID, models.StockReasonSale, models.StockReasonCancel, models.StockReasonTransfer)
+	if err != nil {
+		return err
+	}
+	type taken struct{ productID, quantity int }
+	var items []taken
+	for rows.Next() {
+		var t taken
+		if err := rows.Scan(&t.productID, &t.quantity); err != nil {
+			rows.Close()
+			return err
+		}
+		if t.quantity < 0 {
+			items = append(items, t)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, t := range items {
+		if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}PRODUCTS SET stock = stock + ? WHERE id = ?"), -t.quantity, t.productID); err != nil {
+			return err
+		}
+		err := s.insertStockMovement(ctx, tx, models.StockMovement{
+			ProductID: t.productID,
+			Quantity:  -t.quantity,
+			Reason:    models.StockReasonCancel,
+			OrderID:   &orderID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package store
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"pos-backend/models"
+)
+
+// appliedPromo adalah hasil perhitungan promo sebelum baris order punya ID.
+// line -1 berarti diskon order.
+type appliedPromo struct {
+	promo  models.Promotion
+	line   int
+	amount float64
+}
+
+// roundMoney membulatkan ke sen supaya diskon tersimpan sama di semua database
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// promoMatches mengecek apakah produk pada baris termasuk target promo
+func promoMatches(p models.Promotion, d models.OrderDetail, categories map[int]int) bool {
+	if p.ProductID != nil && *p.ProductID != d.ProductID {
+		return false
+	}
+	if p.CategoryID != nil && *p.CategoryID != categories[d.ProductID] {
+		return false
+	}
+	return true
+}
+
+// applyPromotions menghitung diskon untuk details dan mengisi Discount setiap
+// baris. Aturannya:
+//   - setiap baris mendapat paling banyak satu promo baris; promo dari kode
+//     didahulukan, lalu beli X gratis Y, lalu diskon baris terbesar
+//   - paling banyak satu diskon order (kode didahulukan, selain itu yang
+//     terbesar), dihitung dari nilai setelah diskon baris dan dibagi ke
+//     semua baris sesuai porsinya
+func applyPromotions(promos []models.Promotion, details []models.OrderDetail, categories map[int]int, code string, now time.Time) ([]appliedPromo, error) {
+	var codePromo *models.Promotion
+	var lineAuto, bxgyAuto, orderAuto []models.Promotion
+	for i := range promos {
+		p := promos[i]
+		if p.Code != "" {
+			if code != "" && strings.EqualFold(p.Code, code) {
+				codePromo = &promos[i]
+			}
+			continue
+		}
+		if !p.ActiveAt(now) {
+			continue
+		}
+		switch {
+		case p.Type == models.PromoBuyXGetY:
+			bxgyAuto = append(bxgyAuto, p)
+		case p.Scope == models.PromoScopeOrder:
+			orderAuto = append(orderAuto, p)
+		default:
+			lineAuto = append(lineAuto, p)
+		}
+	}
+
+	if code != "" {
+		switch {
+		case codePromo == nil || !codePromo.ActiveAt(now):
+			return nil, fmt.Errorf("%w: promo code %s is not valid", ErrInvalid, code)
+		case codePromo.UsageLimit > 0 && codePromo.UsageCount >= codePromo.UsageLimit:
+			return nil, fmt.Errorf("%w: promo code %s has been used up", ErrInvalid, code)
+		}
+	}
+
+	for i := range details {
+		details[i].Discount = 0
+	}
+	var applied []appliedPromo
+	discounted := make([]bool, len(details))
+
+	// Promo baris dari kode selalu dipakai walaupun ada promo otomatis yang lebih besar
+	if codePromo != nil && codePromo.Type == models.PromoBuyXGetY {
+		applied = append(applied, applyBuyXGetY(*codePromo, details, categories, discounted)...)
+	} else if codePromo != nil && codePromo.Scope == models.PromoScopeLine {
+		for i, d := range details {
+			if promoMatches(*codePromo, d, categories) {
+				if amount := lineDiscount(*codePromo, d); amount > 0 {
+					details[i].Discount += amount
+					discounted[i] = true
+					applied = append(applied, appliedPromo{promo: *codePromo, line: i, amount: amount})
+				}
+			}
+		}
+	}
+
+	for _, p := range bxgyAuto {
+		applied = append(applied, applyBuyXGetY(p, details, categories, discounted)...)
+	}
+
+	for i, d := range details {
+		if discounted[i] {
+			continue
+		}
+		var best *models.Promotion
+		var bestAmount float64
+		for j := range lineAuto {
+			if !promoMatches(lineAuto[j], d, categories) {
+				continue
+			}
+			if amount := lineDiscount(lineAuto[j], d); amount > bestAmount {
+				best, bestAmount = &lineAuto[j], amount
+			}
+		}
+		if best != nil {
+			details[i].Discount += bestAmount
+			discounted[i] = true
+			applied = append(applied, appliedPromo{promo: *best, line: i, amount: bestAmount})
+		}
+	}
+
+	var net float64
+	for _, d := range details {
+		net += d.NetTotal()
+	}
+
+	var orderPromo *models.Promotion
+	var orderAmount float64
+	if codePromo != nil && codePromo.Scope == models.PromoScopeOrder && codePromo.Type != models.PromoBuyXGetY {
+		if net < codePromo.MinSubtotal {
+			return nil, fmt.Errorf("%w: promo code %s needs a minimum purchase of %.2f", ErrInvalid, code, codePromo.MinSubtotal)
+		}
+		orderPromo, orderAmount = codePromo, orderDiscount(*codePromo, net)
+	} else {
+		for j := range orderAuto {
+			if net < orderAuto[j].MinSubtotal {
+				continue
+			}
+			if amount := orderDiscount(orderAuto[j], net); amount > orderAmount {
+				orderPromo, orderAmount = &orderAuto[j], amount
+			}
+		}
+	}
+	if orderPromo != nil && orderAmount > 0 {
+		spreadOrderDiscount(details, orderAmount, net)
+		applied = append(applied, appliedPromo{promo: *orderPromo, line: -1, amount: orderAmount})
+	}
+
+	if codePromo != nil {
+		used := false
+		for _, a := range applied {
+			used = used || a.promo.ID == codePromo.ID
+		}
+		if !used {
+			return nil, fmt.Errorf("%w: promo code %s does not apply to this order", ErrInvalid, code)
+		}
+	}
+	return applied, nil
+}
+
+// lineDiscount menghitung diskon persen atau nominal per unit untuk satu baris
+func lineDiscount(p models.Promotion, d models.OrderDetail) float64 {
+	var amount float64
+	switch p.Type {
+	case models.PromoPercent:
+		amount = d.TotalPrice * p.Value / 100
+	case models.PromoFixed:
+		amount = p.Value * float64(d.Quantity)
+	}
+	return roundMoney(math.Min(amount, d.NetTotal()))
+}
+
+// orderDiscount menghitung diskon order dari nilai order setelah diskon baris
+func orderDiscount(p models.Promotion, net float64) float64 {
+	var amount float64
+	switch p.Type {
+	case models.PromoPercent:
+		amount = net * p.Value / 100
+	case models.PromoFixed:
+		amount = p.Value
+	}
+	return roundMoney(math.Min(amount, net))
+}
+
+// applyBuyXGetY memberi diskon pada unit termurah dari setiap kelompok
+// BuyQuantity+GetQuantity unit yang memenuhi syarat. Baris yang ikut
+// dihitung tidak mendapat promo baris lain.
+func applyBuyXGetY(p models.Promotion, details []models.OrderDetail, categories map[int]int, discounted []bool) []appliedPromo {
+	// Unit dikelompokkan per baris supaya jumlah yang besar tidak membuat
+	// satu elemen per unit
+	type lineUnits struct {
+		line     int
+		price    float64
+		quantity int
+	}
+	var units []lineUnits
+	var lines []int
+	count := 0
+	for i, d := range details {
+		if discounted[i] || d.Quantity <= 0 || !promoMatches(p, d, categories) {
+			continue
+		}
+		lines = append(lines, i)
+		units = append(units, lineUnits{line: i, price: d.TotalPrice / float64(d.Quantity), quantity: d.Quantity})
+		count += d.Quantity
+	}
+
+	group := p.BuyQuantity + p.GetQuantity
+	if p.BuyQuantity <= 0 || p.GetQuantity <= 0 || count < group {
+		return nil
+	}
+	free := count / group * p.GetQuantity
+	sort.SliceStable(units, func(i, j int) bool { return units[i].price < units[j].price })
+
+	percent := p.Value
+	if percent <= 0 || percent > 100 {
+		percent = 100
+	}
+	amounts := map[int]float64{}
+	for _, u := range units {
+		if free == 0 {
+			break
+		}
+		n := u.quantity
+		if n > free {
+			n = free
+		}
+		amounts[u.line] += u.price * float64(n) * percent / 100
+		free -= n
+	}
+
+	var applied []appliedPromo
+	for _, i := range lines {
+		discounted[i] = true
+		if amount := roundMoney(amounts[i]); amount > 0 {
+			details[i].Discount += amount
+			applied = append(applied, appliedPromo{promo: p, line: i, amount: amount})
+		}
+	}
+	return applied
+}
+
+// spreadOrderDiscount membagi diskon order ke setiap baris sesuai nilai
+// bersihnya supaya refund per baris tetap tepat. Sisa pembulatan masuk ke
+// baris terakhir yang bernilai.
+func spreadOrderDiscount(details []models.OrderDetail, amount, net float64) {
+	last := -1
+	for i, d := range details {
+		if d.NetTotal() > 0 {
+			last = i
+		}
+	}
+	remaining := amount
+	for i := range details {
+		share := details[i].NetTotal()
+		if share <= 0 {
+			continue
+		}
+		if i == last {
+			details[i].Discount = roundMoney(details[i].Discount + remaining)
+			return
+		}
+		portion := roundMoney(amount * share / net)
+		details[i].Discount = roundMoney(details[i].Discount + portion)
+		remaining -= portion
+	}
+}
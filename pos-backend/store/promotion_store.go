@@ -0,0 +1,332 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"pos-backend/models"
+)
+
+const promotionColumns = `id, name, type, scope, value, product_id, category_id, buy_quantity, get_quantity,
+	min_subtotal, code, usage_limit, usage_count, starts_at, ends_at, days, start_time, end_time, active, created_at`
+
+func scanPromotion(row rowScanner) (*models.Promotion, error) {
+	var p models.Promotion
+	var productID, categoryID sql.NullInt64
+	var code, days, startTime, endTime sql.NullString
+	var startsAt, endsAt sql.NullTime
+	var active int
+	err := row.Scan(&p.ID, &p.Name, &p.Type, &p.Scope, &p.Value, &productID, &categoryID, &p.BuyQuantity, &p.GetQuantity,
+		&p.MinSubtotal, &code, &p.UsageLimit, &p.UsageCount, &startsAt, &endsAt, &days, &startTime, &endTime, &active, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if productID.Valid {
+		id := int(productID.Int64)
+		p.ProductID = &id
+	}
+	if categoryID.Valid {
+		id := int(categoryID.Int64)
+		p.CategoryID = &id
+	}
+	if startsAt.Valid {
+		p.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		p.EndsAt = &endsAt.Time
+	}
+	p.Code = code.String
+	p.Days = models.ParseDays(days.String)
+	p.StartTime = startTime.String
+	p.EndTime = endTime.String
+	p.Active = active != 0
+	return &p, nil
+}
+
+func (s *sqlStore) queryPromotions(ctx context.Context, q queryer, query string, args ...interface{}) ([]models.Promotion, error) {
+	rows, err := q.QueryContext(ctx, s.q(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := []models.Promotion{}
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, *p)
+	}
+	return promotions, rows.Err()
+}
+
+func (s *sqlStore) ListPromotions(ctx context.Context) ([]models.Promotion, error) {
+	return s.queryPromotions(ctx, s.db, "SELECT "+promotionColumns+" FROM {schema}PROMOTIONS ORDER BY id ASC")
+}
+
+// activePromotions memuat semua promo aktif; masa berlaku dan jam happy hour
+// diperiksa di Go karena format waktu berbeda di setiap database
+func (s *sqlStore) activePromotions(ctx context.Context, q queryer) ([]models.Promotion, error) {
+	return s.queryPromotions(ctx, q, "SELECT "+promotionColumns+" FROM {schema}PROMOTIONS WHERE active = 1 ORDER BY id ASC")
+}
+
+// SavePromotion membuat promo baru (ID 0) atau memperbarui promo yang ada.
+// Jumlah pemakaian kode tidak ikut diubah.
+func (s *sqlStore) SavePromotion(ctx context.Context, promo *models.Promotion) error {
+	if err := validatePromotion(promo); err != nil {
+		return err
+	}
+
+	active := 0
+	if promo.Active {
+		active = 1
+	}
+	args := []interface{}{
+		promo.Name, promo.Type, promo.Scope, promo.Value, nullInt(promo.ProductID), nullInt(promo.CategoryID),
+		promo.BuyQuantity, promo.GetQuantity, promo.MinSubtotal, nullString(promo.Code), promo.UsageLimit,
+		nullTime(promo.StartsAt), nullTime(promo.EndsAt), nullString(models.FormatDays(promo.Days)),
+		nullString(promo.StartTime), nullString(promo.EndTime), active,
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if err := s.checkPromotionTarget(ctx, tx, promo); err != nil {
+			return err
+		}
+
+		var err error
+		if promo.ID == 0 {
+			promo.ID, err = s.dialect.insertReturningID(ctx, tx, s.q(`
+				INSERT INTO {schema}PROMOTIONS (name, type, scope, value, product_id, category_id, buy_quantity, get_quantity,
+					min_subtotal, code, usage_limit, starts_at, ends_at, days, start_time, end_time, active)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`), args...)
+		} else {
+			err = expectRows(tx.ExecContext(ctx, s.q(`
+				UPDATE {schema}PROMOTIONS
+				SET name = ?, type = ?, scope = ?, value = ?, product_id = ?, category_id = ?, buy_quantity = ?, get_quantity = ?,
+					min_subtotal = ?, code = ?, usage_limit = ?, starts_at = ?, ends_at = ?, days = ?, start_time = ?, end_time = ?, active = ?
+				WHERE id = ?`), append(args, promo.ID)...))
+		}
+		if err != nil {
+			return err
+		}
+
+		saved, err := scanPromotion(tx.QueryRowContext(ctx, s.q("SELECT "+promotionColumns+" FROM {schema}PROMOTIONS WHERE id = ?"), promo.ID))
+		if err != nil {
+			return err
+		}
+		*promo = *saved
+		return nil
+	})
+}
+
+// checkPromotionTarget memastikan produk, kategori dan kode promo valid
+func (s *sqlStore) checkPromotionTarget(ctx context.Context, tx *sql.Tx, promo *models.Promotion) error {
+	if err := s.checkRuleTarget(ctx, tx, promo.ProductID, promo.CategoryID); err != nil {
+		return err
+	}
+	if promo.Code != "" {
+		var id int
+		err := tx.QueryRowContext(ctx, s.q("SELECT id FROM {schema}PROMOTIONS WHERE LOWER(code) = LOWER(?)"), promo.Code).Scan(&id)
+		if err == nil && id != promo.ID {
+			return fmt.Errorf("%w: promo code %s is already used by promotion %d", ErrConflict, promo.Code, id)
+		} else if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+	}
+	return nil
+}
+
+var clockPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+func validatePromotion(p *models.Promotion) error {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Code = strings.TrimSpace(p.Code)
+	if p.Type == models.PromoBuyXGetY {
+		p.Scope = models.PromoScopeLine
+	}
+
+	switch {
+	case p.Name == "":
+		return fmt.Errorf("%w: promotion name is required", ErrInvalid)
+	case p.Type != models.PromoPercent && p.Type != models.PromoFixed && p.Type != models.PromoBuyXGetY:
+		return fmt.Errorf("%w: unknown promotion type %q", ErrInvalid, p.Type)
+	case p.Scope != models.PromoScopeLine && p.Scope != models.PromoScopeOrder:
+		return fmt.Errorf("%w: unknown promotion scope %q", ErrInvalid, p.Scope)
+	case p.Type == models.PromoPercent && (p.Value <= 0 || p.Value > 100):
+		return fmt.Errorf("%w: percent value must be between 0 and 100", ErrInvalid)
+	case p.Type == models.PromoFixed && p.Value <= 0:
+		return fmt.Errorf("%w: fixed value must be positive", ErrInvalid)
+	case p.Type == models.PromoBuyXGetY && (p.BuyQuantity < 1 || p.GetQuantity < 1):
+		return fmt.Errorf("%w: buy_quantity and get_quantity must be at least 1", ErrInvalid)
+	case p.Type == models.PromoBuyXGetY && (p.Value < 0 || p.Value > 100):
+		return fmt.Errorf("%w: buy_x_get_y value must be between 0 and 100", ErrInvalid)
+	case p.Scope == models.PromoScopeOrder && (p.ProductID != nil || p.CategoryID != nil):
+		return fmt.Errorf("%w: order promotions cannot target a product or category", ErrInvalid)
+	case p.MinSubtotal < 0 || p.UsageLimit < 0:
+		return fmt.Errorf("%w: min_subtotal and usage_limit must not be negative", ErrInvalid)
+	case len(p.Code) > 50:
+		return fmt.Errorf("%w: promo code is longer than 50 characters", ErrInvalid)
+	case p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt):
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalid)
+	case (p.StartTime == "") != (p.EndTime == ""):
+		return fmt.Errorf("%w: start_time and end_time must be set together", ErrInvalid)
+	case p.StartTime != "" && (!clockPattern.MatchString(p.StartTime) || !clockPattern.MatchString(p.EndTime)):
+		return fmt.Errorf("%w: start_time and end_time must be HH:MM", ErrInvalid)
+	}
+	for _, d := range p.Days {
+		if d < 1 || d > 7 {
+			return fmt.Errorf("%w: days must be between 1 (Monday) and 7 (Sunday)", ErrInvalid)
+		}
+	}
+	return nil
+}
+
+func (s *sqlStore) GetPromotion(ctx context.Context, id int) (*models.Promotion, error) {
+	p, err := scanPromotion(s.db.QueryRowContext(ctx, s.q("SELECT "+promotionColumns+" FROM {schema}PROMOTIONS WHERE id = ?"), id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return p, err
+}
+
+// DeletePromotion menghapus aturan promo; order lama tetap menyimpan nama dan nilainya
+func (s *sqlStore) DeletePromotion(ctx context.Context, id int) error {
+	return expectRows(s.db.ExecContext(ctx, s.q("DELETE FROM {schema}PROMOTIONS WHERE id = ?"), id))
+}
+
+// saveAppliedPromotions menyimpan promo order dan memakai satu kuota kode promo
+func (s *sqlStore) saveAppliedPromotions(ctx context.Context, tx *sql.Tx, order *models.Order) error {
+	codes, err := s.insertAppliedPromotions(ctx, tx, order)
+	if err != nil {
+		return err
+	}
+
+	// Kuota dicek ulang di UPDATE supaya dua kasir tidak bisa memakai sisa kuota yang sama
+	for id, code := range codes {
+		err := expectRows(tx.ExecContext(ctx, s.q(`
+			UPDATE {schema}PROMOTIONS SET usage_count = usage_count + 1
+			WHERE id = ? AND (usage_limit = 0 OR usage_count < usage_limit)`), id))
+		if err == ErrNotFound {
+			return fmt.Errorf("%w: promo code %s has been used up", ErrConflict, code)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertAppliedPromotions menyimpan promo order tanpa menambah kuota dan
+// mengembalikan kode promo yang dipakai per promotion_id
+func (s *sqlStore) insertAppliedPromotions(ctx context.Context, tx *sql.Tx, order *models.Order) (map[int]string, error) {
+	codes := map[int]string{}
+	for i := range order.Promotions {
+		p := &order.Promotions[i]
+		p.OrderID = order.ID
+		var err error
+		p.ID, err = s.dialect.insertReturningID(ctx, tx,
+			s.q("INSERT INTO {schema}ORDER_PROMOTIONS (order_id, promotion_id, order_detail_id, name, code, amount) VALUES (?, ?, ?, ?, ?, ?)"),
+			p.OrderID, nullPromotionID(p.PromotionID), nullInt(p.OrderDetailID), p.Name, nullString(p.Code), p.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record promotions: %w", err)
+		}
+		if p.Code != "" {
+			codes[p.PromotionID] = p.Code
+		}
+	}
+	return codes, nil
+}
+
+// nullPromotionID menyimpan diskon tanpa aturan promo (tukar poin) dengan promotion_id NULL
+func nullPromotionID(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
+
+// releasePromoUsage mengembalikan kuota kode promo saat order dibatalkan
+func (s *sqlStore) releasePromoUsage(ctx context.Context, tx *sql.Tx, orderID int) error {
+	_, err := tx.ExecContext(ctx, s.q(`
+		UPDATE {schema}PROMOTIONS SET usage_count = usage_count - 1
+		WHERE usage_count > 0 AND id IN (
+			SELECT promotion_id FROM {schema}ORDER_PROMOTIONS WHERE order_id = ? AND code IS NOT NULL
+		)`), orderID)
+	return err
+}
+
+func (s *sqlStore) promotionsByOrder(ctx context.Context, q queryer, orderIDs []int) (map[int][]models.AppliedPromotion, error) {
+	promotions := map[int][]models.AppliedPromotion{}
+	err := forEachChunk(orderIDs, func(marks string, args []interface{}) error {
+		rows, err := q.QueryContext(ctx, s.q(`
+			SELECT id, order_id, promotion_id, order_detail_id, name, code, amount
+			FROM {schema}ORDER_PROMOTIONS
+			WHERE order_id IN (`+marks+`)
+			ORDER BY id ASC`), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var p models.AppliedPromotion
+			var promotionID, detailID sql.NullInt64
+			var code sql.NullString
+			if err := rows.Scan(&p.ID, &p.OrderID, &promotionID, &detailID, &p.Name, &code, &p.Amount); err != nil {
+				return err
+			}
+			p.PromotionID = int(promotionID.Int64)
+			if detailID.Valid {
+				id := int(detailID.Int64)
+				p.OrderDetailID = &id
+			}
+			p.Code = code.String
+			promotions[p.OrderID] = append(promotions[p.OrderID], p)
+		}
+		return rows.Err()
+	})
+	return promotions, err
+}
+
+// PromotionReport menjumlahkan diskon per promo pada order selesai antara from dan to
+func (s *sqlStore) PromotionReport(ctx context.Context, from, to time.Time) (*models.PromotionReport, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: to must be after from", ErrInvalid)
+	}
+	report := &models.PromotionReport{From: from, To: to, Promotions: []models.PromotionUsage{}}
+	args := []interface{}{models.OrderStatusCompleted, models.OrderStatusRefunded, from, to}
+	const orderFilter = "o.status IN (?, ?) AND o.created_at >= ? AND o.created_at < ?"
+
+	err := s.db.QueryRowContext(ctx, s.q(`
+		SELECT COALESCE(SUM(o.subtotal), 0), COALESCE(SUM(o.discount), 0)
+		FROM {schema}ORDERS o
+		WHERE `+orderFilter), args...).Scan(&report.GrossRevenue, &report.TotalDiscount)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.q(`
+		SELECT p.promotion_id, p.name, COALESCE(p.code, ''), COUNT(DISTINCT p.order_id), COALESCE(SUM(p.amount), 0)
+		FROM {schema}ORDER_PROMOTIONS p
+		JOIN {schema}ORDERS o ON o.id = p.order_id
+		WHERE `+orderFilter+`
+		GROUP BY p.promotion_id, p.name, COALESCE(p.code, '')
+		ORDER BY COALESCE(SUM(p.amount), 0) DESC`), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u models.PromotionUsage
+		// Penukaran poin loyalty disimpan tanpa promotion_id
+		var promotionID sql.NullInt64
+		if err := rows.Scan(&promotionID, &u.Name, &u.Code, &u.OrderCount, &u.Discount); err != nil {
+			return nil, err
+		}
+		u.PromotionID = int(promotionID.Int64)
+		report.Promotions = append(report.Promotions, u)
+	}
+	return report, rows.Err()
+}
@@ -0,0 +1,373 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"pos-backend/models"
+)
+
+// RefundOrder mencatat refund penuh atau per baris untuk order yang sudah
+// selesai. Refund yang membuat total refund order melewati approvalThreshold
+// membutuhkan ApprovedBy supaya batas tidak bisa diakali dengan refund
+// sebagian berkali-kali. Jika
+// seluruh nilai order sudah dikembalikan, status order menjadi Refunded.
+func (s *sqlStore) RefundOrder(ctx context.Context, refund *models.Refund, approvalThreshold float64) error {
+	if !models.IsValidRefundReason(refund.Reason) {
+		return fmt.Errorf("%w: unknown refund reason %q", ErrInvalid, refund.Reason)
+	}
+	if refund.Method == "" {
+		refund.Method = models.PaymentCash
+	}
+	if !models.IsValidPaymentMethod(refund.Method) {
+		return fmt.Errorf("%w: unknown payment method %q", ErrInvalid, refund.Method)
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		var status string
+		var total sql.NullFloat64
+		err := tx.QueryRowContext(ctx, s.q("SELECT status, total_price FROM {schema}ORDERS WHERE id = ? "+s.dialect.forUpdate()), refund.OrderID).Scan(&status, &total)
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		} else if err != nil {
+			return err
+		}
+		if status != models.OrderStatusCompleted {
+			return fmt.Errorf("%w: order %d is %s, only completed orders can be refunded", ErrConflict, refund.OrderID, status)
+		}
+
+		details, err := s.orderDetails(ctx, tx, refund.OrderID)
+		if err != nil {
+			return err
+		}
+		if len(details) == 0 {
+			// Bagian dari order yang dibagi rata tidak punya baris, jadi
+			// yang dikembalikan adalah sisa nilai order tersebut
+			if err := s.shareRefund(ctx, tx, refund, total.Float64); err != nil {
+				return err
+			}
+		} else {
+			remaining, refundedAmounts, err := s.refundableQuantities(ctx, tx, refund.OrderID, details)
+			if err != nil {
+				return err
+			}
+
+			items, err := refundItems(refund.Items, details, remaining, refundedAmounts)
+			if err != nil {
+				return err
+			}
+			refund.Items = items
+			refund.Amount = 0
+			for _, item := range items {
+				refund.Amount += item.Amount
+			}
+		}
+
+		if err := s.checkRefundMethod(ctx, tx, refund); err != nil {
+			return err
+		}
+		var previous float64
+		err = tx.QueryRowContext(ctx, s.q("SELECT COALESCE(SUM(amount), 0) FROM {schema}REFUNDS WHERE order_id = ?"), refund.OrderID).Scan(&previous)
+		if err != nil {
+			return err
+		}
+		refunded := previous + refund.Amount
+		if refunded > approvalThreshold && !moneyEqual(refunded, approvalThreshold) && refund.ApprovedBy == "" {
+			if previous > 0 {
+				return fmt.Errorf("%w: refunds on order %d would total %.2f, above %.2f", ErrApprovalRequired, refund.OrderID, refunded, approvalThreshold)
+			}
+			return fmt.Errorf("%w: refund of %.2f is above %.2f", ErrApprovalRequired, refund.Amount, approvalThreshold)
+		}
+
+		restock := 0
+		if refund.Restock {
+			restock = 1
+		}
+		shiftID, err := s.openShiftID(ctx, tx, refund.Username)
+		if err != nil {
+			return err
+		}
+		refund.ID, err = s.dialect.insertReturningID(ctx, tx,
+			s.q("INSERT INTO {schema}REFUNDS (order_id, method, amount, reason, note, restock, username, approved_by, shift_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"),
+			refund.OrderID, refund.Method, refund.Amount, refund.Reason, nullString(refund.Note), restock, nullString(refund.Username), nullString(refund.ApprovedBy), nullInt(shiftID))
+		if err != nil {
+			return fmt.Errorf("failed to record refund: %w", err)
+		}
+
+		for i := range refund.Items {
+			item := &refund.Items[i]
+			item.RefundID = refund.ID
+			item.ID, err = s.dialect.insertReturningID(ctx, tx,
+				s.q("INSERT INTO {schema}REFUND_ITEMS (refund_id, order_detail_id, quantity, amount) VALUES (?, ?, ?, ?)"),
+				item.RefundID, item.OrderDetailID, item.Quantity, item.Amount)
+			if err != nil {
+				return fmt.Errorf("failed to record refund items: %w", err)
+			}
+			if refund.Restock {
+				if err := s.returnStock(ctx, tx, refund, *item); err != nil {
+					return err
+				}
+			}
+		}
+
+		full := refunded > total.Float64 || moneyEqual(refunded, total.Float64)
+		if err := s.refundPoints(ctx, tx, refund, refunded, total.Float64, full); err != nil {
+			return err
+		}
+		if full {
+			_, err = s.transitionOrder(ctx, tx, refund.OrderID, models.OrderStatusRefunded, refund.Username, refund.Reason)
+			return err
+		}
+		return nil
+	})
+}
+
+// shareRefund mengisi refund untuk order tanpa baris (bagian dari order
+// yang dibagi rata) dengan sisa nilai order yang belum di-refund. Stok tidak
+// dikembalikan karena barangnya tercatat di order induk.
+func (s *sqlStore) shareRefund(ctx context.Context, tx *sql.Tx, refund *models.Refund, total float64) error {
+	if len(refund.Items) > 0 {
+		return fmt.Errorf("%w: order %d has no items, refund it without items", ErrInvalid, refund.OrderID)
+	}
+	var refunded float64
+	err := tx.QueryRowContext(ctx, s.q("SELECT COALESCE(SUM(amount), 0) FROM {schema}REFUNDS WHERE order_id = ?"), refund.OrderID).Scan(&refunded)
+	if err != nil {
+		return err
+	}
+	refund.Amount = roundMoney(total - refunded)
+	if refund.Amount <= 0 || moneyEqual(refund.Amount, 0) {
+		return fmt.Errorf("%w: nothing left to refund", ErrConflict)
+	}
+	refund.Items = []models.RefundItem{}
+	refund.Restock = false
+	return nil
+}
+
+// refundableQuantities menghitung sisa jumlah per baris order yang belum
+// di-refund dan nilai yang sudah dikembalikan per baris
+func (s *sqlStore) refundableQuantities(ctx context.Context, tx *sql.Tx, orderID int, details []models.OrderDetail) (map[int]int, map[int]float64, error) {
+	remaining := make(map[int]int, len(details))
+	for _, d := range details {
+		remaining[d.ID] = d.Quantity
+	}
+
+	rows, err := tx.QueryContext(ctx, s.q(`
+		SELECT ri.order_detail_id, SUM(ri.quantity), SUM(ri.amount)
+		FROM {schema}REFUND_ITEMS ri
+		JOIN {schema}REFUNDS r ON r.id = ri.refund_id
+		WHERE r.order_id = ?
+		GROUP BY ri.order_detail_id
+	`), orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	refunded := map[int]float64{}
+	for rows.Next() {
+		var detailID, quantity int
+		var amount float64
+		if err := rows.Scan(&detailID, &quantity, &amount); err != nil {
+			return nil, nil, err
+		}
+		remaining[detailID] -= quantity
+		refunded[detailID] = amount
+	}
+	return remaining, refunded, rows.Err()
+}
+
+// refundItems melengkapi baris refund dari client dengan harga baris order.
+// Tanpa baris berarti semua sisa yang belum di-refund. refunded adalah nilai
+// yang sudah dikembalikan per baris dari refund sebelumnya.
+func refundItems(requested []models.RefundItem, details []models.OrderDetail, remaining map[int]int, refunded map[int]float64) ([]models.RefundItem, error) {
+	byID := make(map[int]models.OrderDetail, len(details))
+	for _, d := range details {
+		byID[d.ID] = d
+	}
+
+	if len(requested) == 0 {
+		for _, d := range details {
+			if remaining[d.ID] > 0 {
+				requested = append(requested, models.RefundItem{OrderDetailID: d.ID, Quantity: remaining[d.ID]})
+			}
+		}
+		if len(requested) == 0 {
+			return nil, fmt.Errorf("%w: nothing left to refund", ErrConflict)
+		}
+	}
+
+	items := make([]models.RefundItem, 0, len(requested))
+	for i, req := range requested {
+		detail, ok := byID[req.OrderDetailID]
+		if !ok {
+			return nil, fmt.Errorf("%w: item %d refers to order detail %d which is not on this order", ErrInvalid, i+1, req.OrderDetailID)
+		}
+		if req.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: item %d has invalid quantity %d", ErrInvalid, i+1, req.Quantity)
+		}
+		if req.Quantity > remaining[detail.ID] {
+			return nil, fmt.Errorf("%w: only %d of %s can still be refunded", ErrInvalid, remaining[detail.ID], detail.ProductName)
+		}
+		remaining[detail.ID] -= req.Quantity
+
+		// Nilai refund mengikuti harga setelah diskon promo ditambah service
+		// charge dan pajak. Order lama belum menyimpan unit_price, jadi
+		// dihitung dari total baris. Refund yang menghabiskan baris
+		// mengembalikan sisa nilainya supaya selisih pembulatan tidak tertinggal.
+		amount := detail.UnitPrice * float64(req.Quantity)
+		if remaining[detail.ID] == 0 {
+			amount = roundMoney(detail.ChargedTotal() - refunded[detail.ID])
+		} else if detail.ChargedTotal() != detail.TotalPrice || (detail.UnitPrice == 0 && detail.Quantity > 0) {
+			amount = roundMoney(detail.ChargedTotal() * float64(req.Quantity) / float64(detail.Quantity))
+		}
+		refunded[detail.ID] += amount
+		items = append(items, models.RefundItem{
+			OrderDetailID: detail.ID,
+			ProductID:     detail.ProductID,
+			ProductName:   detail.ProductName,
+			Quantity:      req.Quantity,
+			Amount:        amount,
+		})
+	}
+	return items, nil
+}
+
+// checkRefundMethod menolak refund yang melebihi uang yang masuk lewat
+// metode tersebut. Order lama tanpa catatan pembayaran tidak diperiksa.
+func (s *sqlStore) checkRefundMethod(ctx context.Context, tx *sql.Tx, refund *models.Refund) error {
+	payments, err := s.orderPayments(ctx, tx, refund.OrderID)
+	if err != nil || len(payments) == 0 {
+		return err
+	}
+
+	var paid, refunded float64
+	for _, p := range payments {
+		if p.Method == refund.Method {
+			paid += p.Amount
+		}
+	}
+	err = tx.QueryRowContext(ctx, s.q("SELECT COALESCE(SUM(amount), 0) FROM {schema}REFUNDS WHERE order_id = ? AND method = ?"), refund.OrderID, refund.Method).Scan(&refunded)
+	if err != nil {
+		return err
+	}
+
+	available := paid - refunded
+	if refund.Amount > available && !moneyEqual(refund.Amount, available) {
+		return fmt.Errorf("%w: refund of %.2f exceeds %.2f paid by %s", ErrInvalid, refund.Amount, available, refund.Method)
+	}
+	return nil
+}
+
+// returnStock menambah stok untuk barang yang dikembalikan. Produk yang
+// stoknya tidak dilacak atau sudah dihapus dilewati.
+func (s *sqlStore) returnStock(ctx context.Context, tx *sql.Tx, refund *models.Refund, item models.RefundItem) error {
+	if item.ProductID == 0 {
+		return nil
+	}
+	result, err := tx.ExecContext(ctx, s.q("UPDATE {schema}PRODUCTS SET stock = stock + ? WHERE id = ? AND stock IS NOT NULL"), item.Quantity, item.ProductID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return err
+	}
+	return s.insertStockMovement(ctx, tx, models.StockMovement{
+		ProductID: item.ProductID,
+		Quantity:  item.Quantity,
+		Reason:    models.StockReasonRefund,
+		OrderID:   &refund.OrderID,
+		Note:      refund.Reason,
+		Username:  refund.Username,
+	})
+}
+
+func (s *sqlStore) OrderRefunds(ctx context.Context, orderID int) ([]models.Refund, error) {
+	if n, err := s.count(ctx, "SELECT COUNT(*) FROM {schema}ORDERS WHERE id = ?", orderID); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrNotFound
+	}
+	refunds, err := s.refundsByOrder(ctx, s.db, []int{orderID})
+	if err != nil {
+		return nil, err
+	}
+	if refunds[orderID] == nil {
+		return []models.Refund{}, nil
+	}
+	return refunds[orderID], nil
+}
+
+func (s *sqlStore) refundsByOrder(ctx context.Context, q queryer, orderIDs []int) (map[int][]models.Refund, error) {
+	var all []models.Refund
+	err := forEachChunk(orderIDs, func(marks string, args []interface{}) error {
+		rows, err := q.QueryContext(ctx, s.q("SELECT id, order_id, method, amount, reason, note, restock, username, approved_by, created_at FROM {schema}REFUNDS WHERE order_id IN ("+marks+") ORDER BY id ASC"), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var r models.Refund
+			var note, username, approvedBy sql.NullString
+			var restock int
+			if err := rows.Scan(&r.ID, &r.OrderID, &r.Method, &r.Amount, &r.Reason, &note, &restock, &username, &approvedBy, &r.CreatedAt); err != nil {
+				return err
+			}
+			r.Note = note.String
+			r.Restock = restock != 0
+			r.Username = username.String
+			r.ApprovedBy = approvedBy.String
+			all = append(all, r)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refundIDs := make([]int, len(all))
+	for i := range all {
+		refundIDs[i] = all[i].ID
+	}
+	items, err := s.itemsByRefund(ctx, q, refundIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds := map[int][]models.Refund{}
+	for _, r := range all {
+		r.Items = items[r.ID]
+		refunds[r.OrderID] = append(refunds[r.OrderID], r)
+	}
+	return refunds, nil
+}
+
+func (s *sqlStore) itemsByRefund(ctx context.Context, q queryer, refundIDs []int) (map[int][]models.RefundItem, error) {
+	items := map[int][]models.RefundItem{}
+	err := forEachChunk(refundIDs, func(marks string, args []interface{}) error {
+		rows, err := q.QueryContext(ctx, s.q(`
+			SELECT ri.id, ri.refund_id, ri.order_detail_id, d.product_id, d.product_name, ri.quantity, ri.amount
+			FROM {schema}REFUND_ITEMS ri
+			JOIN {schema}ORDER_DETAILS d ON d.id = ri.order_detail_id
+			WHERE ri.refund_id IN (`+marks+`)
+			ORDER BY ri.id ASC
+		`), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item models.RefundItem
+			var productID sql.NullInt64
+			if err := rows.Scan(&item.ID, &item.RefundID, &item.OrderDetailID, &productID, &item.ProductName, &item.Quantity, &item.Amount); err != nil {
+				return err
+			}
+			item.ProductID = int(productID.Int64)
+			items[item.RefundID] = append(items[item.RefundID], item)
+		}
+		return rows.Err()
+	})
+	return items, err
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"pos-backend/models"
+)
+
+func TestRefundSequence(t *testing.T) {
+	tests := []struct {
+		name  string
+		price float64
+		tax   bool
+		promo bool
+		// jumlah unit per refund, 0 berarti semua sisa
+		steps   []int
+		amounts []float64
+	}{
+		{
+			name:    "one unit at a time with tax rounding",
+			price:   30303.03,
+			tax:     true,
+			steps:   []int{1, 1, 1},
+			amounts: []float64{33333.33, 33333.33, 33333.34},
+		},
+		{
+			name:    "partial then the rest",
+			price:   10000,
+			steps:   []int{1, 0},
+			amounts: []float64{10000, 20000},
+		},
+		{
+			name:    "discounted line",
+			price:   10000,
+			promo:   true,
+			steps:   []int{2, 1},
+			amounts: []float64{18000, 9000},
+		},
+		{
+			name:    "whole order",
+			price:   12345.67,
+			tax:     true,
+			steps:   []int{0},
+			amounts: []float64{40740.71},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ctx := openTestStore(t)
+			product := createTestProduct(t, ctx, st, models.Product{Name: "Kopi", Price: tt.price})
+			if tt.tax {
+				if err := st.Taxes.SaveTaxRule(ctx, &models.TaxRule{Name: "PB1", Type: models.TaxTax, Rate: 10, Active: true}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.promo {
+				promo := &models.Promotion{Name: "Kopi 10%", Type: models.PromoPercent, Scope: models.PromoScopeLine, Value: 10, Active: true}
+				if err := st.Promotions.SavePromotion(ctx, promo); err != nil {
+					t.Fatal(err)
+				}
+			}
+			order := completeTestOrder(t, ctx, st, []models.OrderItem{{ProductID: product, Quantity: 3}})
+
+			var refunded float64
+			for i, quantity := range tt.steps {
+				refund := &models.Refund{OrderID: order.ID, Reason: models.RefundReasonOther, Username: "kasir"}
+				if quantity > 0 {
+					refund.Items = []models.RefundItem{{OrderDetailID: order.Details[0].ID, Quantity: quantity}}
+				}
+				if err := st.Orders.RefundOrder(ctx, refund, *order.TotalPrice); err != nil {
+					t.Fatalf("refund %d: %v", i+1, err)
+				}
+				if !moneyEqual(refund.Amount, tt.amounts[i]) {
+					t.Errorf("refund %d is %.2f, want %.2f", i+1, refund.Amount, tt.amounts[i])
+				}
+				refunded += refund.Amount
+			}
+
+			got, err := st.Orders.GetOrder(ctx, order.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != models.OrderStatusRefunded {
+				t.Errorf("status %s after refunding %.2f of %.2f, want %s", got.Status, refunded, *order.TotalPrice, models.OrderStatusRefunded)
+			}
+			err = st.Orders.RefundOrder(ctx, &models.Refund{OrderID: order.ID, Reason: models.RefundReasonOther}, *order.TotalPrice)
+			if !errors.Is(err, ErrConflict) {
+				t.Errorf("refund after Refunded: got %v, want ErrConflict", err)
+			}
+		})
+	}
+}
+
+func TestRefundApprovalThreshold(t *testing.T) {
+	st, ctx := openTestStore(t)
+	product := createTestProduct(t, ctx, st, models.Product{Name: "Kopi", Price: 10000})
+	order := completeTestOrder(t, ctx, st, []models.OrderItem{{ProductID: product, Quantity: 2}})
+
+	refund := &models.Refund{OrderID: order.ID, Reason: models.RefundReasonOther}
+	if err := st.Orders.RefundOrder(ctx, refund, 15000); !errors.Is(err, ErrApprovalRequired) {
+		t.Fatalf("got %v, want ErrApprovalRequired", err)
+	}
+	refund.ApprovedBy = "manager"
+	if err := st.Orders.RefundOrder(ctx, refund, 15000); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefundApprovalThresholdIsCumulative(t *testing.T) {
+	st, ctx := openTestStore(t)
+	product := createTestProduct(t, ctx, st, models.Product{Name: "Kopi", Price: 10000})
+	order := completeTestOrder(t, ctx, st, []models.OrderItem{{ProductID: product, Quantity: 2}})
+	item := []models.RefundItem{{OrderDetailID: order.Details[0].ID, Quantity: 1}}
+
+	if err := st.Orders.RefundOrder(ctx, &models.Refund{OrderID: order.ID, Reason: models.RefundReasonOther, Items: item}, 15000); err != nil {
+		t.Fatal(err)
+	}
+	// Refund kedua sendiri di bawah batas, tapi totalnya 20000
+	refund := &models.Refund{OrderID: order.ID, Reason: models.RefundReasonOther, Items: item}
+	if err := st.Orders.RefundOrder(ctx, refund, 15000); !errors.Is(err, ErrApprovalRequired) {
+		t.Fatalf("got %v, want ErrApprovalRequired", err)
+	}
+	refund.ApprovedBy = "manager"
+	if err := st.Orders.RefundOrder(ctx, refund, 15000); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,225 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"pos-backend/models"
+)
+
+// maxReportBuckets membatasi panjang laporan, misalnya 3 tahun harian
+const maxReportBuckets = 1100
+
+// soldLineFilter memilih baris order yang terjual. Baris order yang
+// tagihannya dibagi rata tetap di order induk (Order Split) sedangkan order
+// anaknya hanya membawa nilai uang, jadi baris itu baru dihitung setelah
+// semua bagian tanpa baris selesai dibayar. Argumennya dari soldLineArgs.
+const soldLineFilter = `(o.status IN (?, ?) OR (o.status = ? AND NOT EXISTS (
+	SELECT 1 FROM {schema}ORDERS c
+	WHERE c.parent_order_id = o.id AND c.status NOT IN (?, ?)
+		AND NOT EXISTS (SELECT 1 FROM {schema}ORDER_DETAILS cd WHERE cd.order_id = c.id))))`
+
+func soldLineArgs() []interface{} {
+	return []interface{}{models.OrderStatusCompleted, models.OrderStatusRefunded, models.OrderStatusSplit,
+		models.OrderStatusCompleted, models.OrderStatusRefunded}
+}
+
+// SalesReport menghitung penjualan order yang selesai per periode di database.
+// Diskon, service charge dan pajak dilaporkan terpisah dari GrossRevenue;
+// refund dikurangkan pada periode refund itu dibuat.
+func (s *sqlStore) SalesReport(ctx context.Context, from, to time.Time, granularity string, top int) (*models.SalesReport, error) {
+	if !models.IsValidGranularity(granularity) {
+		return nil, fmt.Errorf("%w: unknown granularity %q", ErrInvalid, granularity)
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: to must be after from", ErrInvalid)
+	}
+
+	report := &models.SalesReport{From: from, To: to, Granularity: granularity}
+	index := map[string]int{}
+	for start := bucketStart(from, granularity); start.Before(to); start = nextBucket(start, granularity) {
+		if len(report.Buckets) == maxReportBuckets {
+			return nil, fmt.Errorf("%w: report has more than %d periods", ErrInvalid, maxReportBuckets)
+		}
+		key := start.Format("2006-01-02")
+		index[key] = len(report.Buckets)
+		report.Buckets = append(report.Buckets, models.SalesBucket{Start: key, TopProducts: []models.TopSeller{}})
+	}
+	bucket := func(key string) *models.SalesBucket {
+		if i, ok := index[key]; ok {
+			return &report.Buckets[i]
+		}
+		// Batas periode di database bisa berbeda zona waktu dengan server
+		index[key] = len(report.Buckets)
+		report.Buckets = append(report.Buckets, models.SalesBucket{Start: key, TopProducts: []models.TopSeller{}})
+		return &report.Buckets[len(report.Buckets)-1]
+	}
+
+	orderBucket := s.dialect.dateBucket("o.created_at", granularity)
+	args := []interface{}{models.OrderStatusCompleted, models.OrderStatusRefunded, from, to}
+	const orderFilter = "o.status IN (?, ?) AND o.created_at >= ? AND o.created_at < ?"
+	lineArgs := append(soldLineArgs(), from, to)
+	const lineFilter = soldLineFilter + " AND o.created_at >= ? AND o.created_at < ?"
+
+	rows, err := s.db.QueryContext(ctx, s.q(`
+		SELECT `+orderBucket+`, COUNT(*), COALESCE(SUM(COALESCE(o.subtotal, o.total_price)), 0), COALESCE(SUM(o.discount), 0),
+			COALESCE(SUM(o.service_charge), 0), COALESCE(SUM(o.tax), 0)
+		FROM {schema}ORDERS o
+		WHERE `+orderFilter+`
+		GROUP BY `+orderBucket), args...)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var key string
+		var count int
+		var gross, discounts, service, tax float64
+		if err := rows.Scan(&key, &count, &gross, &discounts, &service, &tax); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		b := bucket(key)
+		b.OrderCount = count
+		b.GrossRevenue = gross
+		b.Discounts = discounts
+		b.ServiceCharge = service
+		b.Tax = tax
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.db.QueryContext(ctx, s.q(`
+		SELECT `+orderBucket+`, COALESCE(SUM(d.quantity), 0)
+		FROM {schema}ORDER_DETAILS d
+		JOIN {schema}ORDERS o ON o.id = d.order_id
+		WHERE `+lineFilter+`
+		GROUP BY `+orderBucket), lineArgs...)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var key string
+		var items int
+		if err := rows.Scan(&key, &items); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		bucket(key).ItemsSold = items
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	refundBucket := s.dialect.dateBucket("r.created_at", granularity)
+	rows, err = s.db.QueryContext(ctx, s.q(`
+		SELECT `+refundBucket+`, COALESCE(SUM(r.amount), 0)
+		FROM {schema}REFUNDS r
+		WHERE r.created_at >= ? AND r.created_at < ?
+		GROUP BY `+refundBucket), from, to)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var key string
+		var amount float64
+		if err := rows.Scan(&key, &amount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		bucket(key).Refunds = amount
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if top > 0 {
+		// Peringkat produk per periode dihitung dengan window function
+		rows, err = s.db.QueryContext(ctx, s.q(`
+			SELECT bucket, product_name, total_sold, revenue FROM (
+				SELECT `+orderBucket+` AS bucket, d.product_name, SUM(d.quantity) AS total_sold, COALESCE(SUM(d.total_price - d.discount), 0) AS revenue,
+					ROW_NUMBER() OVER (PARTITION BY `+orderBucket+` ORDER BY SUM(d.quantity) DESC, d.product_name) AS rn
+				FROM {schema}ORDER_DETAILS d
+				JOIN {schema}ORDERS o ON o.id = d.order_id
+				WHERE `+lineFilter+`
+				GROUP BY `+orderBucket+`, d.product_name
+			) ranked
+			WHERE rn <= ?
+			ORDER BY bucket, rn`), append(lineArgs, top)...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var key string
+			var seller models.TopSeller
+			if err := rows.Scan(&key, &seller.ProductName, &seller.TotalSold, &seller.Revenue); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			b := bucket(key)
+			b.TopProducts = append(b.TopProducts, seller)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		report.Total.TopProducts, err = s.topSellers(ctx, top, lineFilter, lineArgs...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(report.Buckets, func(i, j int) bool { return report.Buckets[i].Start < report.Buckets[j].Start })
+	report.Total.Start = from.Format("2006-01-02")
+	for i := range report.Buckets {
+		b := &report.Buckets[i]
+		b.Revenue = b.GrossRevenue - b.Discounts + b.ServiceCharge + b.Tax - b.Refunds
+		if b.OrderCount > 0 {
+			b.AverageTicket = (b.GrossRevenue - b.Discounts + b.ServiceCharge + b.Tax) / float64(b.OrderCount)
+		}
+		report.Total.GrossRevenue += b.GrossRevenue
+		report.Total.Discounts += b.Discounts
+		report.Total.ServiceCharge += b.ServiceCharge
+		report.Total.Tax += b.Tax
+		report.Total.Refunds += b.Refunds
+		report.Total.OrderCount += b.OrderCount
+		report.Total.ItemsSold += b.ItemsSold
+	}
+	t := &report.Total
+	t.Revenue = t.GrossRevenue - t.Discounts + t.ServiceCharge + t.Tax - t.Refunds
+	if t.OrderCount > 0 {
+		t.AverageTicket = (t.GrossRevenue - t.Discounts + t.ServiceCharge + t.Tax) / float64(t.OrderCount)
+	}
+	if report.Total.TopProducts == nil {
+		report.Total.TopProducts = []models.TopSeller{}
+	}
+	return report, nil
+}
+
+// bucketStart mengembalikan awal hari, minggu (Senin) atau bulan dari t
+func bucketStart(t time.Time, granularity string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch granularity {
+	case models.ReportWeekly:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case models.ReportMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+	return day
+}
+
+func nextBucket(t time.Time, granularity string) time.Time {
+	switch granularity {
+	case models.ReportWeekly:
+		return t.AddDate(0, 0, 7)
+	case models.ReportMonthly:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
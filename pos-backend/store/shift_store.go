@@ -0,0 +1,387 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pos-backend/models"
+)
+
+const shiftColumns = "id, username, opening_float, opened_at, closed_at, closed_by, expected_cash, counted_cash, note"
+
+func scanShift(row rowScanner) (*models.Shift, error) {
+	var shift models.Shift
+	var closedAt sql.NullTime
+	var closedBy, note sql.NullString
+	var expected, counted sql.NullFloat64
+	if err := row.Scan(&shift.ID, &shift.Username, &shift.OpeningFloat, &shift.OpenedAt, &closedAt, &closedBy, &expected, &counted, &note); err != nil {
+		return nil, err
+	}
+	if closedAt.Valid {
+		shift.ClosedAt = &closedAt.Time
+	}
+	if expected.Valid {
+		shift.ExpectedCash = &expected.Float64
+	}
+	if counted.Valid {
+		shift.CountedCash = &counted.Float64
+	}
+	shift.ClosedBy = closedBy.String
+	shift.Note = note.String
+	return &shift, nil
+}
+
+// OpenShift membuka shift baru untuk user; satu user hanya boleh punya satu shift terbuka
+func (s *sqlStore) OpenShift(ctx context.Context, username string, open models.ShiftOpen) (*models.Shift, error) {
+	if username == "" {
+		return nil, fmt.Errorf("%w: username is required", ErrInvalid)
+	}
+	if open.OpeningFloat < 0 {
+		return nil, fmt.Errorf("%w: opening float must not be negative", ErrInvalid)
+	}
+
+	var shift *models.Shift
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		current, err := s.openShiftID(ctx, tx, username)
+		if err != nil {
+			return err
+		}
+		if current != nil {
+			return fmt.Errorf("%w: %s already has open shift %d", ErrConflict, username, *current)
+		}
+
+		id, err := s.dialect.insertReturningID(ctx, tx,
+			s.q("INSERT INTO {schema}SHIFTS (username, opening_float, note) VALUES (?, ?, ?)"),
+			username, roundMoney(open.OpeningFloat), nullString(strings.TrimSpace(open.Note)))
+		if err != nil {
+			return fmt.Errorf("failed to open shift: %w", err)
+		}
+		shift, err = scanShift(tx.QueryRowContext(ctx, s.q("SELECT "+shiftColumns+" FROM {schema}SHIFTS WHERE id = ?"), id))
+		return err
+	})
+	if err != nil && !errors.Is(err, ErrConflict) {
+		// Index unik idx_shifts_open_user menolak shift kedua yang dibuka
+		// bersamaan; laporkan sebagai konflik seperti pemeriksaan di atas
+		if current, _ := s.openShiftID(ctx, s.db, username); current != nil {
+			return nil, fmt.Errorf("%w: %s already has open shift %d", ErrConflict, username, *current)
+		}
+	}
+	return shift, err
+}
+
+// CurrentShift mengambil shift user yang masih buka, ErrNotFound jika tidak ada
+func (s *sqlStore) CurrentShift(ctx context.Context, username string) (*models.Shift, error) {
+	shift, err := scanShift(s.db.QueryRowContext(ctx, s.q("SELECT "+shiftColumns+" FROM {schema}SHIFTS WHERE username = ? AND closed_at IS NULL"), username))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s has no open shift", ErrNotFound, username)
+	}
+	return shift, err
+}
+
+func (s *sqlStore) GetShift(ctx context.Context, id int) (*models.Shift, error) {
+	shift, err := scanShift(s.db.QueryRowContext(ctx, s.q("SELECT "+shiftColumns+" FROM {schema}SHIFTS WHERE id = ?"), id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return shift, err
+}
+
+// ListShifts mengambil shift yang dibuka antara from dan to, terbaru lebih dulu
+func (s *sqlStore) ListShifts(ctx context.Context, from, to time.Time) ([]models.Shift, error) {
+	rows, err := s.db.QueryContext(ctx, s.q("SELECT "+shiftColumns+" FROM {schema}SHIFTS WHERE opened_at >= ? AND opened_at < ? ORDER BY id DESC"), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shifts := []models.Shift{}
+	for rows.Next() {
+		shift, err := scanShift(rows)
+		if err != nil {
+			return nil, err
+		}
+		shifts = append(shifts, *shift)
+	}
+	return shifts, rows.Err()
+}
+
+// AddCashMovement mencatat uang masuk atau keluar laci pada shift user yang
+// sedang buka. Uang keluar tidak boleh melebihi uang tunai di laci.
+func (s *sqlStore) AddCashMovement(ctx context.Context, movement *models.CashMovement) error {
+	if movement.Type != models.CashIn && movement.Type != models.CashOut {
+		return fmt.Errorf("%w: unknown cash movement type %q", ErrInvalid, movement.Type)
+	}
+	if movement.Amount <= 0 {
+		return fmt.Errorf("%w: amount must be positive", ErrInvalid)
+	}
+	movement.Amount = roundMoney(movement.Amount)
+	movement.Note = strings.TrimSpace(movement.Note)
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		shiftID, err := s.openShiftID(ctx, tx, movement.Username)
+		if err != nil {
+			return err
+		}
+		if shiftID == nil {
+			return fmt.Errorf("%w: %s has no open shift", ErrConflict, movement.Username)
+		}
+		shift, err := scanShift(tx.QueryRowContext(ctx, s.q("SELECT "+shiftColumns+" FROM {schema}SHIFTS WHERE id = ? "+s.dialect.forUpdate()), *shiftID))
+		if err != nil {
+			return err
+		}
+
+		if movement.Type == models.CashOut {
+			report, err := s.zReport(ctx, tx, shift)
+			if err != nil {
+				return err
+			}
+			if movement.Amount > report.ExpectedCash && !moneyEqual(movement.Amount, report.ExpectedCash) {
+				return fmt.Errorf("%w: cash out of %.2f exceeds %.2f in the drawer", ErrInvalid, movement.Amount, report.ExpectedCash)
+			}
+		}
+
+		movement.ShiftID = shift.ID
+		movement.CreatedAt = time.Now()
+		movement.ID, err = s.dialect.insertReturningID(ctx, tx,
+			s.q("INSERT INTO {schema}CASH_MOVEMENTS (shift_id, type, amount, note, username, created_at) VALUES (?, ?, ?, ?, ?, ?)"),
+			movement.ShiftID, movement.Type, movement.Amount, nullString(movement.Note), nullString(movement.Username), movement.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to record cash movement: %w", err)
+		}
+		return nil
+	})
+}
+
+// CloseShift menutup shift dengan uang tunai yang dihitung kasir dan
+// mengembalikan Z-report dengan selisih terhadap uang yang seharusnya ada
+func (s *sqlStore) CloseShift(ctx context.Context, id int, countedCash float64, note, closedBy string) (*models.ZReport, error) {
+	if countedCash < 0 {
+		return nil, fmt.Errorf("%w: counted cash must not be negative", ErrInvalid)
+	}
+
+	var report *models.ZReport
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		shift, err := scanShift(tx.QueryRowContext(ctx, s.q("SELECT "+shiftColumns+" FROM {schema}SHIFTS WHERE id = ? "+s.dialect.forUpdate()), id))
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		} else if err != nil {
+			return err
+		}
+		if shift.ClosedAt != nil {
+			return fmt.Errorf("%w: shift %d is already closed", ErrConflict, id)
+		}
+
+		report, err = s.zReport(ctx, tx, shift)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		counted := roundMoney(countedCash)
+		expected := report.ExpectedCash
+		if note = strings.TrimSpace(note); note == "" {
+			note = shift.Note
+		}
+		_, err = tx.ExecContext(ctx, s.q("UPDATE {schema}SHIFTS SET closed_at = ?, closed_by = ?, expected_cash = ?, counted_cash = ?, note = ? WHERE id = ?"),
+			now, nullString(closedBy), expected, counted, nullString(note), id)
+		if err != nil {
+			return err
+		}
+
+		report.Shift.ClosedAt = &now
+		report.Shift.ClosedBy = closedBy
+		report.Shift.ExpectedCash = &expected
+		report.Shift.CountedCash = &counted
+		report.Shift.Note = note
+		closeReport(report)
+		return nil
+	})
+	return report, err
+}
+
+// ShiftReport membuat Z-report sebuah shift; untuk shift yang masih buka
+// hasilnya adalah laporan sementara tanpa uang yang dihitung
+func (s *sqlStore) ShiftReport(ctx context.Context, id int) (*models.ZReport, error) {
+	shift, err := s.GetShift(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.zReport(ctx, s.db, shift)
+	if err != nil {
+		return nil, err
+	}
+	// Shift yang sudah tutup memakai uang seharusnya yang dicatat saat tutup
+	if shift.ExpectedCash != nil {
+		report.ExpectedCash = *shift.ExpectedCash
+	}
+	closeReport(report)
+	return report, nil
+}
+
+// closeReport mengisi uang yang dihitung dan selisihnya untuk shift yang sudah tutup
+func closeReport(report *models.ZReport) {
+	if report.Shift.CountedCash == nil {
+		return
+	}
+	counted := *report.Shift.CountedCash
+	discrepancy := roundMoney(counted - report.ExpectedCash)
+	report.CountedCash = &counted
+	report.Discrepancy = &discrepancy
+}
+
+// zReport menjumlahkan order, tender, refund dan uang laci sebuah shift
+func (s *sqlStore) zReport(ctx context.Context, q queryer, shift *models.Shift) (*models.ZReport, error) {
+	report := &models.ZReport{Shift: *shift, Tenders: []models.PaymentMethodTotal{}, CashMovements: []models.CashMovement{}}
+
+	rows, err := q.QueryContext(ctx, s.q(`
+		SELECT status, COUNT(*), COALESCE(SUM(total_price), 0), COALESCE(SUM(discount), 0), COALESCE(SUM(service_charge), 0), COALESCE(SUM(tax), 0)
+		FROM {schema}ORDERS
+		WHERE shift_id = ?
+		GROUP BY status`), shift.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status string
+		var count int
+		var total, discount, service, tax float64
+		if err := rows.Scan(&status, &count, &total, &discount, &service, &tax); err != nil {
+			return nil, err
+		}
+		report.OrderCount += count
+		switch {
+		case status == models.OrderStatusCompleted || status == models.OrderStatusRefunded:
+			report.CompletedCount += count
+			report.Sales += total
+			report.Discounts += discount
+			report.ServiceCharge += service
+			report.Tax += tax
+		case status == models.OrderStatusCanceled:
+			report.CanceledCount += count
+		case models.IsOpenOrderStatus(status):
+			report.OpenCount += count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	index := map[string]int{}
+	rows, err = q.QueryContext(ctx, s.q(`
+		SELECT method, COUNT(*), COALESCE(SUM(amount), 0)
+		FROM {schema}PAYMENTS
+		WHERE shift_id = ?
+		GROUP BY method
+		ORDER BY method`), shift.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t models.PaymentMethodTotal
+		if err := rows.Scan(&t.Method, &t.Count, &t.Total); err != nil {
+			return nil, err
+		}
+		index[t.Method] = len(report.Tenders)
+		report.Tenders = append(report.Tenders, t)
+		if t.Method == models.PaymentCash {
+			report.CashSales = t.Total
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	rows, err = q.QueryContext(ctx, s.q(`
+		SELECT method, COUNT(*), COALESCE(SUM(amount), 0)
+		FROM {schema}REFUNDS
+		WHERE shift_id = ?
+		GROUP BY method`), shift.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var method string
+		var count int
+		var amount float64
+		if err := rows.Scan(&method, &count, &amount); err != nil {
+			return nil, err
+		}
+		report.RefundCount += count
+		report.Refunded += amount
+		if method == models.PaymentCash {
+			report.CashRefunds = amount
+		}
+		// Refund bisa dibayarkan lewat metode yang tidak diterima di shift ini
+		i, ok := index[method]
+		if !ok {
+			i = len(report.Tenders)
+			index[method] = i
+			report.Tenders = append(report.Tenders, models.PaymentMethodTotal{Method: method})
+		}
+		report.Tenders[i].Refunded += amount
+		report.Tenders[i].Total -= amount
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	rows, err = q.QueryContext(ctx, s.q("SELECT id, shift_id, type, amount, note, username, created_at FROM {schema}CASH_MOVEMENTS WHERE shift_id = ? ORDER BY id ASC"), shift.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m models.CashMovement
+		var note, username sql.NullString
+		if err := rows.Scan(&m.ID, &m.ShiftID, &m.Type, &m.Amount, &note, &username, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.Note = note.String
+		m.Username = username.String
+		if m.Type == models.CashIn {
+			report.CashIn += m.Amount
+		} else {
+			report.CashOut += m.Amount
+		}
+		report.CashMovements = append(report.CashMovements, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	report.Sales = roundMoney(report.Sales)
+	report.Discounts = roundMoney(report.Discounts)
+	report.ServiceCharge = roundMoney(report.ServiceCharge)
+	report.Tax = roundMoney(report.Tax)
+	report.Refunded = roundMoney(report.Refunded)
+	report.CashIn = roundMoney(report.CashIn)
+	report.CashOut = roundMoney(report.CashOut)
+	report.ExpectedCash = roundMoney(shift.OpeningFloat + report.CashSales - report.CashRefunds + report.CashIn - report.CashOut)
+	return report, nil
+}
+
+// openShiftID mengambil shift user yang sedang buka, nil jika tidak ada.
+// Order, pembayaran dan refund dicatat ke shift ini.
+func (s *sqlStore) openShiftID(ctx context.Context, q queryer, username string) (*int, error) {
+	if username == "" {
+		return nil, nil
+	}
+	var id int
+	err := q.QueryRowContext(ctx, s.q("SELECT id FROM {schema}SHIFTS WHERE username = ? AND closed_at IS NULL"), username).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
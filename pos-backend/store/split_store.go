@@ -0,0 +1,450 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"pos-backend/models"
+)
+
+// SplitOrder memecah order yang masih berjalan. Dengan Items, baris yang
+// dipilih dipindah ke satu order anak dan kedua order dihitung ulang. Dengan
+// Parts, tagihan dibagi rata ke order anak tanpa baris; order asal menjadi
+// Order Split dan barisnya tetap di sana untuk laporan produk.
+func (s *sqlStore) SplitOrder(ctx context.Context, req models.OrderSplitRequest, username string) (*models.OrderSplit, error) {
+	if (len(req.Items) == 0) == (req.Parts == 0) {
+		return nil, fmt.Errorf("%w: give either items or parts to split an order", ErrInvalid)
+	}
+	if req.Parts != 0 && (req.Parts < models.MinSplitParts || req.Parts > models.MaxSplitParts) {
+		return nil, fmt.Errorf("%w: parts must be between %d and %d", ErrInvalid, models.MinSplitParts, models.MaxSplitParts)
+	}
+
+	var childIDs []int
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if req.Parts != 0 {
+			childIDs, err = s.splitEvenly(ctx, tx, req.OrderID, req.Parts, username, req.Reason)
+		} else {
+			var id int
+			id, err = s.splitItems(ctx, tx, req.OrderID, req.Items, username, req.Reason)
+			childIDs = []int{id}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.GetOrder(ctx, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	split := &models.OrderSplit{Order: *order, Children: make([]models.Order, 0, len(childIDs))}
+	for _, id := range childIDs {
+		child, err := s.GetOrder(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		split.Children = append(split.Children, *child)
+	}
+	return split, nil
+}
+
+// splitItems memindahkan baris (atau sebagian jumlahnya) ke order anak baru
+func (s *sqlStore) splitItems(ctx context.Context, tx *sql.Tx, orderID int, items []models.SplitItem, username, reason string) (int, error) {
+	parent, details, err := s.lockOrderLines(ctx, tx, orderID, models.OrderStatusOnProgress, models.OrderStatusReady)
+	if err != nil {
+		return 0, err
+	}
+
+	moving := map[int]int{}
+	remaining := 0
+	for _, d := range details {
+		remaining += d.Quantity
+	}
+	for _, item := range items {
+		i := detailIndex(details, item.DetailID)
+		if i < 0 {
+			return 0, fmt.Errorf("%w: order detail %d is not part of order %d", ErrInvalid, item.DetailID, orderID)
+		}
+		if _, dup := moving[item.DetailID]; dup {
+			return 0, fmt.Errorf("%w: order detail %d is listed twice", ErrInvalid, item.DetailID)
+		}
+		quantity := item.Quantity
+		if quantity == 0 {
+			quantity = details[i].Quantity
+		}
+		if quantity < 0 || quantity > details[i].Quantity {
+			return 0, fmt.Errorf("%w: order detail %d has %d item(s), cannot move %d", ErrInvalid, item.DetailID, details[i].Quantity, quantity)
+		}
+		moving[item.DetailID] = quantity
+		remaining -= quantity
+	}
+	if remaining == 0 {
+		return 0, fmt.Errorf("%w: at least one item must stay on order %d", ErrInvalid, orderID)
+	}
+
+	child := childOrder(parent, parent.Status)
+	if err := s.insertChildOrder(ctx, tx, child, username, withReason(fmt.Sprintf("split from order %d", orderID), reason)); err != nil {
+		return 0, err
+	}
+
+	var kept, moved []models.OrderDetail
+	var parentChanges, childChanges []models.OrderAmendment
+	for _, d := range details {
+		quantity, ok := moving[d.ID]
+		if !ok {
+			kept = append(kept, d)
+			continue
+		}
+
+		line, left := d, d.Quantity-quantity
+		if left == 0 {
+			if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}ORDER_DETAILS SET order_id = ? WHERE id = ?"), child.ID, d.ID); err != nil {
+				return 0, err
+			}
+		} else {
+			kept = append(kept, d)
+			kept[len(kept)-1].Quantity = left
+			kept[len(kept)-1].TotalPrice = roundMoney(d.UnitPrice * float64(left))
+
+			line.Quantity = quantity
+			line.TotalPrice = roundMoney(line.UnitPrice * float64(quantity))
+			if err := s.copyDetail(ctx, tx, &line, child.ID); err != nil {
+				return 0, err
+			}
+		}
+		line.OrderID = child.ID
+		moved = append(moved, line)
+		if err := s.moveStock(ctx, tx, orderID, child.ID, d.ProductID, quantity, username); err != nil {
+			return 0, err
+		}
+
+		parentChanges = append(parentChanges, models.OrderAmendment{
+			OrderDetailID: d.ID,
+			Action:        models.AmendSplit,
+			ProductName:   d.ProductName,
+			OldQuantity:   d.Quantity,
+			NewQuantity:   left,
+			Username:      username,
+			Reason:        withReason(fmt.Sprintf("moved to order %d", child.ID), reason),
+		})
+		childChanges = append(childChanges, models.OrderAmendment{
+			OrderDetailID: line.ID,
+			Action:        models.AmendSplit,
+			ProductName:   line.ProductName,
+			NewQuantity:   quantity,
+			Username:      username,
+			Reason:        withReason(fmt.Sprintf("moved from order %d", orderID), reason),
+		})
+	}
+
+	if err := s.repriceOrder(ctx, tx, parent, kept, parentChanges...); err != nil {
+		return 0, err
+	}
+	return child.ID, s.repriceOrder(ctx, tx, child, moved, childChanges...)
+}
+
+// splitEvenly membagi tagihan order ke parts order anak. Setiap anak
+// membawa bagian yang sama dari subtotal, diskon, pajak dan total; sisa
+// pembulatan masuk ke anak terakhir. Order harus sudah selesai disiapkan
+// dapur dan belum dibayar.
+func (s *sqlStore) splitEvenly(ctx context.Context, tx *sql.Tx, orderID, parts int, username, reason string) ([]int, error) {
+	parent, details, err := s.lockOrderLines(ctx, tx, orderID, models.OrderStatusOnProgress, models.OrderStatusReady)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range details {
+		if d.PreparedAt == nil {
+			return nil, fmt.Errorf("%w: %s on order %d is still being prepared, split evenly once everything is served", ErrConflict, d.ProductName, orderID)
+		}
+	}
+	payments, err := s.orderPayments(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(payments) > 0 {
+		return nil, fmt.Errorf("%w: order %d already has payments", ErrConflict, orderID)
+	}
+
+	promotions, err := s.promotionsByOrder(ctx, tx, []int{orderID})
+	if err != nil {
+		return nil, err
+	}
+	taxes, err := s.taxesByOrder(ctx, tx, []int{orderID})
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0.0
+	if parent.TotalPrice != nil {
+		total = *parent.TotalPrice
+	}
+	subtotals := splitAmount(parent.Subtotal, parts)
+	discounts := splitAmount(parent.Discount, parts)
+	services := splitAmount(parent.ServiceCharge, parts)
+	taxTotals := splitAmount(parent.Tax, parts)
+	included := splitAmount(parent.TaxIncluded, parts)
+	totals := splitAmount(total, parts)
+
+	// Kode promo tidak ikut ke anak supaya membatalkan satu bagian tidak
+	// mengembalikan kuota kode yang tetap dipakai bagian lain
+	promoShares := make([][]float64, len(promotions[orderID]))
+	for i, p := range promotions[orderID] {
+		promoShares[i] = splitAmount(p.Amount, parts)
+	}
+	baseShares := make([][]float64, len(taxes[orderID]))
+	taxShares := make([][]float64, len(taxes[orderID]))
+	for i, t := range taxes[orderID] {
+		baseShares[i] = splitAmount(t.Base, parts)
+		taxShares[i] = splitAmount(t.Amount, parts)
+	}
+
+	ids := make([]int, parts)
+	for n := 0; n < parts; n++ {
+		child := childOrder(parent, models.OrderStatusReady)
+		child.Subtotal, child.Discount, child.ServiceCharge = subtotals[n], discounts[n], services[n]
+		child.Tax, child.TaxIncluded, child.TotalPrice = taxTotals[n], included[n], &totals[n]
+		for i, p := range promotions[orderID] {
+			child.Promotions = append(child.Promotions, models.AppliedPromotion{PromotionID: p.PromotionID, Name: p.Name, Amount: promoShares[i][n]})
+		}
+		for i, t := range taxes[orderID] {
+			t.ID = 0
+			t.Base, t.Amount = baseShares[i][n], taxShares[i][n]
+			child.Taxes = append(child.Taxes, t)
+		}
+
+		note := fmt.Sprintf("share %d of %d of order %d", n+1, parts, orderID)
+		if err := s.insertChildOrder(ctx, tx, child, username, withReason(note, reason)); err != nil {
+			return nil, err
+		}
+		if _, err := s.insertAppliedPromotions(ctx, tx, child); err != nil {
+			return nil, err
+		}
+		if err := s.saveAppliedTaxes(ctx, tx, child); err != nil {
+			return nil, err
+		}
+		ids[n] = child.ID
+	}
+
+	_, err = s.transitionOrder(ctx, tx, orderID, models.OrderStatusSplit, username, withReason(fmt.Sprintf("split evenly into %d orders", parts), reason))
+	return ids, err
+}
+
+// MergeOrders memindahkan semua baris order sumber ke order tujuan,
+// misalnya saat tamu pindah meja. Order sumber menjadi Order Merged dan
+// order tujuan dihitung ulang dengan promo dan kode promonya sendiri.
+func (s *sqlStore) MergeOrders(ctx context.Context, sourceID, targetID int, username, reason string) (*models.Order, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("%w: cannot merge an order into itself", ErrInvalid)
+	}
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		// Kunci order dengan urutan id yang sama untuk menghindari deadlock
+		first, second := sourceID, targetID
+		if first > second {
+			first, second = second, first
+		}
+		locked := map[int]*models.Order{}
+		lines := map[int][]models.OrderDetail{}
+		for _, id := range []int{first, second} {
+			order, details, err := s.lockOrderLines(ctx, tx, id, models.OrderStatusOnProgress, models.OrderStatusReady)
+			if err != nil {
+				return err
+			}
+			locked[id], lines[id] = order, details
+		}
+		source, target := locked[sourceID], locked[targetID]
+
+		payments, err := s.orderPayments(ctx, tx, sourceID)
+		if err != nil {
+			return err
+		}
+		if len(payments) > 0 {
+			return fmt.Errorf("%w: order %d already has payments", ErrConflict, sourceID)
+		}
+		if source.RedeemPoints > 0 {
+			return fmt.Errorf("%w: order %d has redeemed loyalty points", ErrConflict, sourceID)
+		}
+
+		if err := s.releasePromoUsage(ctx, tx, sourceID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}ORDER_PROMOTIONS WHERE order_id = ?"), sourceID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("DELETE FROM {schema}ORDER_TAXES WHERE order_id = ?"), sourceID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}ORDER_DETAILS SET order_id = ? WHERE order_id = ?"), targetID, sourceID); err != nil {
+			return err
+		}
+
+		details := lines[targetID]
+		waiting := false
+		var sourceChanges, targetChanges []models.OrderAmendment
+		for _, d := range lines[sourceID] {
+			if err := s.moveStock(ctx, tx, sourceID, targetID, d.ProductID, d.Quantity, username); err != nil {
+				return err
+			}
+			d.OrderID = targetID
+			details = append(details, d)
+			waiting = waiting || d.PreparedAt == nil
+
+			sourceChanges = append(sourceChanges, models.OrderAmendment{
+				OrderDetailID: d.ID,
+				Action:        models.AmendMerge,
+				ProductName:   d.ProductName,
+				OldQuantity:   d.Quantity,
+				Username:      username,
+				Reason:        withReason(fmt.Sprintf("merged into order %d", targetID), reason),
+			})
+			targetChanges = append(targetChanges, models.OrderAmendment{
+				OrderDetailID: d.ID,
+				Action:        models.AmendMerge,
+				ProductName:   d.ProductName,
+				NewQuantity:   d.Quantity,
+				Username:      username,
+				Reason:        withReason(fmt.Sprintf("merged from order %d", sourceID), reason),
+			})
+		}
+
+		oldTotal := 0.0
+		if source.TotalPrice != nil {
+			oldTotal = *source.TotalPrice
+		}
+		_, err = tx.ExecContext(ctx, s.q(`
+			UPDATE {schema}ORDERS SET subtotal = 0, discount = 0, service_charge = 0, tax = 0, tax_included = 0, total_price = 0
+			WHERE id = ?`), sourceID)
+		if err != nil {
+			return err
+		}
+		if err := s.recordAmendments(ctx, tx, sourceID, oldTotal, 0, sourceChanges); err != nil {
+			return err
+		}
+		if _, err := s.transitionOrder(ctx, tx, sourceID, models.OrderStatusMerged, username, withReason(fmt.Sprintf("merged into order %d", targetID), reason)); err != nil {
+			return err
+		}
+
+		if err := s.repriceOrder(ctx, tx, target, details, targetChanges...); err != nil {
+			return err
+		}
+		// Baris yang belum disiapkan membuat order Ready kembali ke dapur
+		if target.Status == models.OrderStatusReady && waiting {
+			if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}ORDERS SET status = ? WHERE id = ?"), models.OrderStatusOnProgress, targetID); err != nil {
+				return err
+			}
+			return s.recordStatus(ctx, tx, targetID, models.OrderStatusReady, models.OrderStatusOnProgress, username, fmt.Sprintf("merged order %d", sourceID))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetOrder(ctx, targetID)
+}
+
+// childOrder menyiapkan order anak dengan jenis, meja, shift dan waktu
+// order asal supaya promo dan laporan mengikuti waktu order dibuat
+func childOrder(parent *models.Order, status string) *models.Order {
+	zero := 0.0
+	return &models.Order{
+		Status:          status,
+		CreatedAt:       parent.CreatedAt,
+		TotalPrice:      &zero,
+		Type:            parent.Type,
+		TableID:         parent.TableID,
+		TableName:       parent.TableName,
+		DeliveryAddress: parent.DeliveryAddress,
+		ShiftID:         parent.ShiftID,
+		ParentOrderID:   &parent.ID,
+	}
+}
+
+func (s *sqlStore) insertChildOrder(ctx context.Context, tx *sql.Tx, child *models.Order, username, reason string) error {
+	var err error
+	child.ID, err = s.dialect.insertReturningID(ctx, tx, s.q(`
+		INSERT INTO {schema}ORDERS (subtotal, discount, service_charge, tax, tax_included, total_price, status, shift_id,
+			order_type, table_id, table_name, delivery_address, parent_order_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		child.Subtotal, child.Discount, child.ServiceCharge, child.Tax, child.TaxIncluded, *child.TotalPrice, child.Status, nullInt(child.ShiftID),
+		child.Type, nullInt(child.TableID), nullString(child.TableName), nullString(child.DeliveryAddress), nullInt(child.ParentOrderID), child.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create split order: %w", err)
+	}
+	return s.recordStatus(ctx, tx, child.ID, "", child.Status, username, reason)
+}
+
+// copyDetail menyalin baris beserta modifier dan status dapurnya ke order lain
+func (s *sqlStore) copyDetail(ctx context.Context, tx *sql.Tx, detail *models.OrderDetail, orderID int) error {
+	detail.OrderID = orderID
+	detail.Modifiers = append([]models.OrderDetailModifier(nil), detail.Modifiers...)
+	if err := s.insertDetail(ctx, tx, detail); err != nil {
+		return err
+	}
+	if detail.PreparedAt == nil {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, s.q("UPDATE {schema}ORDER_DETAILS SET prepared_at = ?, prepared_by = ? WHERE id = ?"),
+		*detail.PreparedAt, nullString(detail.PreparedBy), detail.ID)
+	return err
+}
+
+// moveStock memindahkan stok yang sudah diambil dari satu order ke order
+// lain, supaya pembatalan salah satunya mengembalikan jumlah yang benar
+func (s *sqlStore) moveStock(ctx context.Context, tx *sql.Tx, fromOrder, toOrder, productID, quantity int, username string) error {
+	taken, err := s.stockTaken(ctx, tx, fromOrder, productID)
+	if err != nil {
+		return err
+	}
+	if quantity > taken {
+		quantity = taken
+	}
+	if quantity <= 0 {
+		return nil
+	}
+
+	err = s.insertStockMovement(ctx, tx, models.StockMovement{
+		ProductID: productID,
+		Quantity:  quantity,
+		Reason:    models.StockReasonTransfer,
+		OrderID:   &fromOrder,
+		Note:      fmt.Sprintf("moved to order %d", toOrder),
+		Username:  username,
+	})
+	if err != nil {
+		return err
+	}
+	return s.insertStockMovement(ctx, tx, models.StockMovement{
+		ProductID: productID,
+		Quantity:  -quantity,
+		Reason:    models.StockReasonTransfer,
+		OrderID:   &toOrder,
+		Note:      fmt.Sprintf("moved from order %d", fromOrder),
+		Username:  username,
+	})
+}
+
+// splitAmount membagi amount menjadi parts bagian yang dibulatkan; sisa
+// pembulatan masuk ke bagian terakhir supaya jumlahnya tetap amount
+func splitAmount(amount float64, parts int) []float64 {
+	shares := make([]float64, parts)
+	share := roundMoney(amount / float64(parts))
+	rest := amount
+	for i := 0; i < parts-1; i++ {
+		shares[i] = share
+		rest -= share
+	}
+	shares[parts-1] = roundMoney(rest)
+	return shares
+}
+
+// withReason menambahkan alasan dari kasir di belakang catatan sistem
+func withReason(note, reason string) string {
+	if reason = strings.TrimSpace(reason); reason == "" {
+		return note
+	}
+	return note + ": " + reason
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"testing"
+
+	"pos-backend/models"
+)
+
+func TestSplitAmount(t *testing.T) {
+	tests := []struct {
+		amount float64
+		parts  int
+		want   []float64
+	}{
+		{100000, 2, []float64{50000, 50000}},
+		{100000, 3, []float64{33333.33, 33333.33, 33333.34}},
+		{100, 6, []float64{16.67, 16.67, 16.67, 16.67, 16.67, 16.65}},
+		{53235, 4, []float64{13308.75, 13308.75, 13308.75, 13308.75}},
+		{0, 3, []float64{0, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		got := splitAmount(tt.amount, tt.parts)
+		if len(got) != len(tt.want) {
+			t.Fatalf("splitAmount(%.2f, %d) = %v, want %v", tt.amount, tt.parts, got, tt.want)
+		}
+		var sum float64
+		for i := range got {
+			if !moneyEqual(got[i], tt.want[i]) {
+				t.Errorf("splitAmount(%.2f, %d) = %v, want %v", tt.amount, tt.parts, got, tt.want)
+				break
+			}
+			sum += got[i]
+		}
+		if !moneyEqual(sum, tt.amount) {
+			t.Errorf("splitAmount(%.2f, %d) sums to %.2f", tt.amount, tt.parts, sum)
+		}
+	}
+}
+
+func TestSplitOrderEvenly(t *testing.T) {
+	tests := []struct {
+		name   string
+		price  float64
+		parts  int
+		totals []float64
+	}{
+		{"even", 10000, 2, []float64{15000, 15000}},
+		{"rounding goes to the last share", 10000, 7, []float64{4285.71, 4285.71, 4285.71, 4285.71, 4285.71, 4285.71, 4285.74}},
+		{"cents", 33333.33, 3, []float64{33333.33, 33333.33, 33333.33}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ctx := openTestStore(t)
+			product := createTestProduct(t, ctx, st, models.Product{Name: "Kopi", Price: tt.price})
+			id, err := st.Orders.CreateOrder(ctx, &models.Order{Items: []models.OrderItem{{ProductID: product, Quantity: 3}}}, "kasir")
+			if err != nil {
+				t.Fatal(err)
+			}
+			order, err := st.Orders.GetOrder(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Order hanya bisa dibagi rata setelah semua item disajikan
+			if _, err := st.Orders.SetItemPrepared(ctx, order.Details[0].ID, true, "dapur"); err != nil {
+				t.Fatal(err)
+			}
+
+			split, err := st.Orders.SplitOrder(ctx, models.OrderSplitRequest{OrderID: id, Parts: tt.parts}, "kasir")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if split.Order.Status != models.OrderStatusSplit || len(split.Children) != tt.parts {
+				t.Fatalf("order is %s with %d shares, want %s with %d", split.Order.Status, len(split.Children), models.OrderStatusSplit, tt.parts)
+			}
+			var sum, subtotal float64
+			for i, child := range split.Children {
+				if !moneyEqual(*child.TotalPrice, tt.totals[i]) {
+					t.Errorf("share %d is %.2f, want %.2f", i+1, *child.TotalPrice, tt.totals[i])
+				}
+				sum += *child.TotalPrice
+				subtotal += child.Subtotal
+			}
+			if !moneyEqual(sum, *order.TotalPrice) || !moneyEqual(subtotal, order.Subtotal) {
+				t.Errorf("shares sum to %.2f (subtotal %.2f), want %.2f (%.2f)", sum, subtotal, *order.TotalPrice, order.Subtotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+	"time"
+)
+
+// sqlStore mengimplementasikan ProductStore, OrderStore dan UserStore
+// di atas database/sql. Perbedaan antar database ditangani oleh Dialect.
+type sqlStore struct {
+	db      *sql.DB
+	dialect Dialect
+	schema  string
+
+	// Aturan poin loyalty, diisi lewat Store.SetLoyalty
+	spendPerPoint float64
+	pointValue    float64
+}
+
+// q menyiapkan query untuk dialect dan schema yang dipakai
+func (s *sqlStore) q(query string) string {
+	return rebind(s.dialect, s.schema, query)
+}
+
+// withTx menjalankan fn di dalam transaksi, rollback jika fn gagal
+func (s *sqlStore) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// count menjalankan query COUNT(*) sederhana
+func (s *sqlStore) count(ctx context.Context, query string, args ...interface{}) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, s.q(query), args...).Scan(&n)
+	return n, err
+}
+
+// expectRows mengubah UPDATE/DELETE yang tidak mengenai baris menjadi ErrNotFound
+func expectRows(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// moneyEqual membandingkan dua nilai uang sampai satuan sen
+func moneyEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
+
+// nullString menyimpan string kosong sebagai NULL
+func nullString(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: v != ""}
+}
+
+// nullInt menyimpan pointer nil sebagai NULL
+func nullInt(v *int) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*v), Valid: true}
+}
+
+// nullTime menyimpan pointer nil sebagai NULL
+func nullTime(v *time.Time) sql.NullTime {
+	if v == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *v, Valid: true}
+}
+
+// inChunkSize menjaga daftar IN di bawah batas 1000 ekspresi Oracle
+const inChunkSize = 500
+
+// forEachChunk memanggil fn untuk setiap potongan ids dengan daftar "?, ?, ..." dan argumennya
+func forEachChunk(ids []int, fn func(marks string, args []interface{}) error) error {
+	for start := 0; start < len(ids); start += inChunkSize {
+		end := start + inChunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		marks := make([]string, end-start)
+		args := make([]interface{}, end-start)
+		for i, id := range ids[start:end] {
+			marks[i] = "?"
+			args[i] = id
+		}
+		if err := fn(strings.Join(marks, ", "), args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
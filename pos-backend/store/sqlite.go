@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type sqliteDialect struct{}
+
+// SQLite memakai go-sqlite3, cocok untuk development dan integration test lokal
+var SQLite Dialect = sqliteDialect{}
+
+func (sqliteDialect) Name() string       { return "sqlite" }
+func (sqliteDialect) driverName() string { return "sqlite3" }
+
+// go-sqlite3 tidak memeriksa foreign key tanpa _foreign_keys=on, sehingga
+// ON DELETE CASCADE dan SET NULL di migrasi tidak berjalan
+func (sqliteDialect) connString(dsn string) string {
+	if strings.Contains(dsn, "_foreign_keys=") || strings.Contains(dsn, "_fk=") {
+		return dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&_foreign_keys=on"
+	}
+	return dsn + "?_foreign_keys=on"
+}
+
+func (sqliteDialect) placeholder(n int) string {
+	return "?"
+}
+
+func (sqliteDialect) insertReturningID(ctx context.Context, q queryer, query string, args ...interface{}) (int, error) {
+	result, err := q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	return int(id), err
+}
+
+func (sqliteDialect) limit(n int) string {
+	return "LIMIT " + itoa(n)
+}
+
+func (sqliteDialect) tableExists(ctx context.Context, q queryer, schema, table string) (bool, error) {
+	master := "sqlite_master"
+	if schema != "" {
+		master = schema + ".sqlite_master"
+	}
+	var n int
+	err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+master+" WHERE type = 'table' AND name = ? COLLATE NOCASE", table).Scan(&n)
+	return n > 0, err
+}
+
+func (sqliteDialect) transactionalDDL() bool { return true }
+
+// SQLite mengunci seluruh database saat menulis, jadi tidak perlu FOR UPDATE
+func (sqliteDialect) forUpdate() string { return "" }
+
+func (sqliteDialect) dateBucket(column, granularity string) string {
+	switch granularity {
+	case "week":
+		// Mundur ke hari Senin: maju ke Minggu berikutnya lalu kurangi 6 hari
+		return "date(" + column + ", 'weekday 0', '-6 days')"
+	case "month":
+		return "date(" + column + ", 'start of month')"
+	}
+	return "date(" + column + ")"
+}
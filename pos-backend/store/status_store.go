@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"pos-backend/models"
+)
+
+// transitionOrder memindahkan status order sesuai models.CanTransition dan
+// mencatatnya di ORDER_STATUS_HISTORY. Semua perubahan status lewat sini.
+func (s *sqlStore) transitionOrder(ctx context.Context, tx *sql.Tx, orderID int, to, username, reason string) (string, error) {
+	var from string
+	err := tx.QueryRowContext(ctx, s.q("SELECT status FROM {schema}ORDERS WHERE id = ? "+s.dialect.forUpdate()), orderID).Scan(&from)
+	if err == sql.ErrNoRows {
+		return "", ErrNotFound
+	} else if err != nil {
+		return "", err
+	}
+	if !models.CanTransition(from, to) {
+		return from, fmt.Errorf("%w: order %d cannot change from %s to %s", ErrConflict, orderID, from, to)
+	}
+
+	if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}ORDERS SET status = ? WHERE id = ?"), to, orderID); err != nil {
+		return from, err
+	}
+	return from, s.recordStatus(ctx, tx, orderID, from, to, username, reason)
+}
+
+func (s *sqlStore) recordStatus(ctx context.Context, q queryer, orderID int, from, to, username, reason string) error {
+	_, err := q.ExecContext(ctx, s.q("INSERT INTO {schema}ORDER_STATUS_HISTORY (order_id, from_status, to_status, username, reason) VALUES (?, ?, ?, ?, ?)"),
+		orderID, nullString(from), to, nullString(username), nullString(reason))
+	return err
+}
+
+func (s *sqlStore) SetOrderStatus(ctx context.Context, id int, status, username, reason string) error {
+	switch status {
+	case models.OrderStatusOnProgress, models.OrderStatusReady:
+	default:
+		return fmt.Errorf("%w: status %q must be set through its own endpoint", ErrInvalid, status)
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := s.transitionOrder(ctx, tx, id, status, username, reason)
+		return err
+	})
+}
+
+func (s *sqlStore) OrderTimeline(ctx context.Context, id int) ([]models.OrderStatusChange, error) {
+	if n, err := s.count(ctx, "SELECT COUNT(*) FROM {schema}ORDERS WHERE id = ?", id); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrNotFound
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.q("SELECT id, order_id, from_status, to_status, username, reason, created_at FROM {schema}ORDER_STATUS_HISTORY WHERE order_id = ? ORDER BY id ASC"), id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	timeline := []models.OrderStatusChange{}
+	for rows.Next() {
+		var c models.OrderStatusChange
+		var from, username, reason sql.NullString
+		if err := rows.Scan(&c.ID, &c.OrderID, &from, &c.ToStatus, &username, &reason, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		c.FromStatus = from.String
+		c.Username = username.String
+		c.Reason = reason.String
+		timeline = append(timeline, c)
+	}
+	return timeline, rows.Err()
+}
@@ -0,0 +1,255 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"pos-backend/models"
+)
+
+var (
+	// ErrNotFound dikembalikan jika baris yang dicari tidak ada
+	ErrNotFound = errors.New("not found")
+	// ErrInvalid membungkus input yang ditolak aturan bisnis, handler membalas 400
+	ErrInvalid = errors.New("invalid request")
+	// ErrConflict membungkus operasi yang tidak sesuai status data saat ini, handler membalas 409
+	ErrConflict = errors.New("conflict")
+	// ErrApprovalRequired menandakan operasi butuh persetujuan admin, handler membalas 403
+	ErrApprovalRequired = errors.New("manager approval required")
+)
+
+type ProductStore interface {
+	// ListProducts mengurutkan produk per kategori; categoryID 0 berarti semua kategori
+	ListProducts(ctx context.Context, categoryID int) ([]models.Product, error)
+	GetProduct(ctx context.Context, id int) (*models.Product, error)
+	CreateProduct(ctx context.Context, product *models.Product) (int, error)
+	// UpdateProduct tetap memakai image_url lama jika ImageURL kosong
+	UpdateProduct(ctx context.Context, product *models.Product) error
+	DeleteProduct(ctx context.Context, id int) error
+	CountProducts(ctx context.Context) (int, error)
+	LowStockProducts(ctx context.Context) ([]models.Product, error)
+	AdjustStock(ctx context.Context, adj models.StockAdjustment, username string) (*models.StockMovement, error)
+	StockMovements(ctx context.Context, productID int) ([]models.StockMovement, error)
+	ModifierGroups(ctx context.Context, productID int) ([]models.ModifierGroup, error)
+	// SaveModifierGroup membuat atau memperbarui grup beserta semua pilihannya
+	SaveModifierGroup(ctx context.Context, group *models.ModifierGroup) error
+	DeleteModifierGroup(ctx context.Context, id int) error
+	// ImportProducts melakukan upsert per baris dan melaporkan hasil setiap baris
+	ImportProducts(ctx context.Context, rows []models.ProductImportRow, dryRun bool) ([]models.ProductImportResult, error)
+}
+
+type CategoryStore interface {
+	// ListCategories mengurutkan kategori berdasarkan sort_order
+	ListCategories(ctx context.Context) ([]models.Category, error)
+	GetCategory(ctx context.Context, id int) (*models.Category, error)
+	CreateCategory(ctx context.Context, category *models.Category) error
+	UpdateCategory(ctx context.Context, category *models.Category) error
+	DeleteCategory(ctx context.Context, id int) error
+	ReorderCategories(ctx context.Context, ids []int) error
+}
+
+type PromotionStore interface {
+	ListPromotions(ctx context.Context) ([]models.Promotion, error)
+	GetPromotion(ctx context.Context, id int) (*models.Promotion, error)
+	// SavePromotion membuat atau memperbarui aturan promo
+	SavePromotion(ctx context.Context, promo *models.Promotion) error
+	DeletePromotion(ctx context.Context, id int) error
+	// PromotionReport menjumlahkan diskon per promo antara from dan to
+	PromotionReport(ctx context.Context, from, to time.Time) (*models.PromotionReport, error)
+}
+
+type TaxStore interface {
+	ListTaxRules(ctx context.Context) ([]models.TaxRule, error)
+	GetTaxRule(ctx context.Context, id int) (*models.TaxRule, error)
+	// SaveTaxRule membuat atau memperbarui aturan pajak atau service charge
+	SaveTaxRule(ctx context.Context, rule *models.TaxRule) error
+	DeleteTaxRule(ctx context.Context, id int) error
+	// TaxReport menjumlahkan pajak dan service charge per aturan antara from dan to
+	TaxReport(ctx context.Context, from, to time.Time) (*models.TaxReport, error)
+}
+
+type CustomerStore interface {
+	// ListCustomers mencari pelanggan berdasarkan nama atau nomor telepon; query kosong berarti semua
+	ListCustomers(ctx context.Context, query string) ([]models.Customer, error)
+	GetCustomer(ctx context.Context, id int) (*models.Customer, error)
+	CreateCustomer(ctx context.Context, customer *models.Customer) error
+	UpdateCustomer(ctx context.Context, customer *models.Customer) error
+	DeleteCustomer(ctx context.Context, id int) error
+	// CustomerHistory mengambil order dan riwayat poin pelanggan
+	CustomerHistory(ctx context.Context, id int) (*models.CustomerHistory, error)
+	AdjustPoints(ctx context.Context, adj models.PointAdjustment, username string) (*models.PointMovement, error)
+}
+
+type ShiftStore interface {
+	OpenShift(ctx context.Context, username string, open models.ShiftOpen) (*models.Shift, error)
+	// CurrentShift mengambil shift user yang masih buka
+	CurrentShift(ctx context.Context, username string) (*models.Shift, error)
+	GetShift(ctx context.Context, id int) (*models.Shift, error)
+	ListShifts(ctx context.Context, from, to time.Time) ([]models.Shift, error)
+	// AddCashMovement mencatat uang masuk/keluar laci pada shift movement.Username
+	AddCashMovement(ctx context.Context, movement *models.CashMovement) error
+	// CloseShift menutup shift dan mengembalikan Z-report dengan selisih uang tunai
+	CloseShift(ctx context.Context, id int, countedCash float64, note, closedBy string) (*models.ZReport, error)
+	ShiftReport(ctx context.Context, id int) (*models.ZReport, error)
+}
+
+type TableStore interface {
+	// ListTables mengembalikan denah meja dengan status terisi/kosong
+	ListTables(ctx context.Context) ([]models.Table, error)
+	GetTable(ctx context.Context, id int) (*models.Table, error)
+	CreateTable(ctx context.Context, table *models.Table) error
+	UpdateTable(ctx context.Context, table *models.Table) error
+	// DeleteTable menolak meja yang masih punya order terbuka
+	DeleteTable(ctx context.Context, id int) error
+}
+
+type OrderStore interface {
+	// ListOrders mengambil order sesuai filter status, jenis order dan meja
+	ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error)
+	// GetOrder mengambil satu order lengkap dengan detail, pembayaran dan refund
+	GetOrder(ctx context.Context, id int) (*models.Order, error)
+	// CreateOrder menghitung harga dari PRODUCTS.price dan menolak total
+	// dari client yang tidak cocok. Order diisi dengan hasil perhitungan.
+	CreateOrder(ctx context.Context, order *models.Order, username string) (int, error)
+	// PriceOrder menghitung harga, promo, pajak dan total seperti CreateOrder tanpa menyimpan order
+	PriceOrder(ctx context.Context, order *models.Order) error
+	// SetOrderStatus memindahkan order ke status yang tidak punya efek samping (On Progress, Ready)
+	SetOrderStatus(ctx context.Context, id int, status, username, reason string) error
+	// CancelOrder membatalkan order dan mengembalikan stok yang sudah diambil.
+	// Order yang sudah menerima pembayaran ditolak dengan ErrConflict.
+	CancelOrder(ctx context.Context, id int, username, reason string) error
+	OrderTimeline(ctx context.Context, id int) ([]models.OrderStatusChange, error)
+	// RefundOrder mencatat refund untuk order yang sudah selesai. Refund yang
+	// membuat total refund order melewati approvalThreshold tanpa ApprovedBy
+	// ditolak dengan ErrApprovalRequired.
+	RefundOrder(ctx context.Context, refund *models.Refund, approvalThreshold float64) error
+	OrderRefunds(ctx context.Context, orderID int) ([]models.Refund, error)
+	// SetItemPrepared menandai baris order di dapur; order menjadi Ready saat semua baris siap
+	SetItemPrepared(ctx context.Context, detailID int, prepared bool, username string) (*models.OrderDetail, error)
+	// AddOrderItem, SetOrderItemQuantity dan VoidOrderItem mengubah baris
+	// order yang masih On Progress, menghitung ulang totalnya dan mencatat
+	// siapa, kapan dan alasannya di riwayat perubahan order
+	AddOrderItem(ctx context.Context, orderID int, item models.OrderItem, username, reason string) (*models.Order, error)
+	SetOrderItemQuantity(ctx context.Context, detailID, quantity int, username, reason string) (*models.Order, error)
+	VoidOrderItem(ctx context.Context, detailID int, username, reason string) (*models.Order, error)
+	OrderAmendments(ctx context.Context, orderID int) ([]models.OrderAmendment, error)
+	// SplitOrder memindahkan baris ke order anak atau membagi tagihan rata
+	// ke beberapa order anak yang dibayar terpisah
+	SplitOrder(ctx context.Context, req models.OrderSplitRequest, username string) (*models.OrderSplit, error)
+	// MergeOrders memindahkan semua baris order sumber ke order tujuan
+	MergeOrders(ctx context.Context, sourceID, targetID int, username, reason string) (*models.Order, error)
+	// AddPayments mencatat tender untuk order yang masih berjalan
+	AddPayments(ctx context.Context, orderID int, payments []models.Payment, username string) (*models.PaymentSummary, error)
+	// CompleteOrder mencatat tender lalu menyelesaikan order, ditolak jika pembayaran kurang
+	CompleteOrder(ctx context.Context, orderID int, payments []models.Payment, username string) (*models.PaymentSummary, error)
+	PaymentSummary(ctx context.Context, orderID int) (*models.PaymentSummary, error)
+	RevenueByMethod(ctx context.Context) ([]models.PaymentMethodTotal, error)
+	// DeleteOrder menghapus order yang masih berjalan dan mengembalikan
+	// stoknya. Order lain ditolak dengan ErrConflict dan harus di-refund.
+	DeleteOrder(ctx context.Context, id int) error
+	CountOrders(ctx context.Context, status string) (int, error)
+	TopSellers(ctx context.Context, limit int) ([]models.TopSeller, error)
+	// SalesReport menghitung penjualan per hari, minggu atau bulan antara from dan to
+	SalesReport(ctx context.Context, from, to time.Time, granularity string, top int) (*models.SalesReport, error)
+	// ExportOrders dan ExportOrderLines mengalirkan baris ke fn satu per satu
+	ExportOrders(ctx context.Context, filter models.ExportFilter, fn func(models.OrderExportRow) error) error
+	ExportOrderLines(ctx context.Context, filter models.ExportFilter, fn func(models.OrderLineExportRow) error) error
+	TotalRevenue(ctx context.Context) (float64, error)
+}
+
+type UserStore interface {
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User) error
+	CountUsers(ctx context.Context, role string) (int, error)
+}
+
+// AuditStore hanya bisa menambah dan membaca log audit
+type AuditStore interface {
+	RecordAudit(ctx context.Context, entry *models.AuditEntry) error
+	ListAudit(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+	VerifyAudit(ctx context.Context) (*models.AuditVerification, error)
+}
+
+// Store mengumpulkan semua repository yang dipakai handler
+type Store struct {
+	DB         *sql.DB
+	Dialect    Dialect
+	Schema     string
+	Products   ProductStore
+	Categories CategoryStore
+	Orders     OrderStore
+	Promotions PromotionStore
+	Taxes      TaxStore
+	Customers  CustomerStore
+	Shifts     ShiftStore
+	Tables     TableStore
+	Users      UserStore
+	Audit      AuditStore
+
+	sql *sqlStore
+}
+
+// Open membuka koneksi database dan memilih implementasi berdasarkan driver.
+// Driver yang didukung: oracle, postgres, sqlite.
+func Open(driver, dsn, schema string) (*Store, error) {
+	d, err := dialectFor(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(d.driverName(), d.connString(dsn))
+	if err != nil {
+		return nil, err
+	}
+	return New(db, d, schema), nil
+}
+
+// New membungkus koneksi yang sudah terbuka
+func New(db *sql.DB, d Dialect, schema string) *Store {
+	s := &sqlStore{db: db, dialect: d, schema: schema}
+	return &Store{
+		DB:         db,
+		Dialect:    d,
+		Schema:     schema,
+		Products:   s,
+		Categories: s,
+		Orders:     s,
+		Promotions: s,
+		Taxes:      s,
+		Customers:  s,
+		Shifts:     s,
+		Tables:     s,
+		Users:      s,
+		Audit:      s,
+		sql:        s,
+	}
+}
+
+// SetLoyalty mengatur poin loyalty: satu poin untuk setiap spendPerPoint
+// belanja dan setiap poin bernilai pointValue saat ditukar. Nilai 0
+// mematikan pemberian atau penukaran poin.
+func (s *Store) SetLoyalty(spendPerPoint, pointValue float64) {
+	s.sql.spendPerPoint = spendPerPoint
+	s.sql.pointValue = pointValue
+}
+
+// Close menutup koneksi database
+func (s *Store) Close() error {
+	return s.DB.Close()
+}
+
+func dialectFor(driver string) (Dialect, error) {
+	switch driver {
+	case "oracle", "godror":
+		return Oracle, nil
+	case "postgres", "postgresql":
+		return Postgres, nil
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
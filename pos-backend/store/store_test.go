@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"pos-backend/models"
+)
+
+// openTestStore membuka database SQLite di memori yang sudah dimigrasi.
+// Setiap test mendapat database sendiri.
+func openTestStore(t *testing.T) (*Store, context.Context) {
+	t.Helper()
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	st, err := Open("sqlite", "file:"+name+"?mode=memory&cache=shared", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Database di memori hilang saat koneksi terakhir ditutup
+	st.DB.SetMaxOpenConns(1)
+	t.Cleanup(func() { st.Close() })
+
+	ctx := context.Background()
+	if _, err := st.Migrate(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return st, ctx
+}
+
+func createTestProduct(t *testing.T, ctx context.Context, st *Store, product models.Product) int {
+	t.Helper()
+	id, err := st.Products.CreateProduct(ctx, &product)
+	if err != nil {
+		t.Fatalf("create product %s: %v", product.Name, err)
+	}
+	return id
+}
+
+// completeTestOrder membuat order dan melunasinya dengan tunai
+func completeTestOrder(t *testing.T, ctx context.Context, st *Store, items []models.OrderItem) *models.Order {
+	t.Helper()
+	id, err := st.Orders.CreateOrder(ctx, &models.Order{Items: items}, "kasir")
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	order, err := st.Orders.GetOrder(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Orders.CompleteOrder(ctx, id, []models.Payment{{Method: models.PaymentCash, Amount: *order.TotalPrice}}, "kasir"); err != nil {
+		t.Fatalf("complete order: %v", err)
+	}
+	return order
+}
+
+func TestSQLiteForeignKeys(t *testing.T) {
+	st, ctx := openTestStore(t)
+	var enabled int
+	if err := st.DB.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&enabled); err != nil {
+		t.Fatal(err)
+	}
+	if enabled != 1 {
+		t.Fatal("foreign keys are not enforced")
+	}
+
+	for dsn, want := range map[string]string{
+		"pos.db":                   "pos.db?_foreign_keys=on",
+		"file:pos.db?cache=shared": "file:pos.db?cache=shared&_foreign_keys=on",
+		"pos.db?_foreign_keys=off": "pos.db?_foreign_keys=off",
+	} {
+		if got := SQLite.connString(dsn); got != want {
+			t.Errorf("connString(%q) = %q, want %q", dsn, got, want)
+		}
+	}
+}
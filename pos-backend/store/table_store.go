@@ -0,0 +1,194 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"pos-backend/models"
+)
+
+// ListTables mengembalikan denah meja beserta order yang masih buka di setiap meja
+func (s *sqlStore) ListTables(ctx context.Context) ([]models.Table, error) {
+	rows, err := s.db.QueryContext(ctx, s.q("SELECT id, name, seats, area, sort_order FROM {schema}DINING_TABLES ORDER BY sort_order ASC, name ASC"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []models.Table{}
+	index := map[int]int{}
+	for rows.Next() {
+		t := models.Table{Status: models.TableFree, OpenOrders: []int{}}
+		var area sql.NullString
+		if err := rows.Scan(&t.ID, &t.Name, &t.Seats, &area, &t.SortOrder); err != nil {
+			return nil, err
+		}
+		t.Area = area.String
+		index[t.ID] = len(tables)
+		tables = append(tables, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	marks := make([]string, len(models.OpenOrderStatuses))
+	args := make([]interface{}, len(models.OpenOrderStatuses))
+	for i, status := range models.OpenOrderStatuses {
+		marks[i] = "?"
+		args[i] = status
+	}
+	orders, err := s.db.QueryContext(ctx, s.q("SELECT id, table_id, total_price FROM {schema}ORDERS WHERE table_id IS NOT NULL AND status IN ("+strings.Join(marks, ", ")+") ORDER BY id ASC"), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer orders.Close()
+
+	for orders.Next() {
+		var id, tableID int
+		var total sql.NullFloat64
+		if err := orders.Scan(&id, &tableID, &total); err != nil {
+			return nil, err
+		}
+		i, ok := index[tableID]
+		if !ok {
+			continue
+		}
+		tables[i].Status = models.TableOccupied
+		tables[i].OpenOrders = append(tables[i].OpenOrders, id)
+		tables[i].OpenTotal = roundMoney(tables[i].OpenTotal + total.Float64)
+	}
+	return tables, orders.Err()
+}
+
+// GetTable mengambil data meja tanpa status terisi/kosong
+func (s *sqlStore) GetTable(ctx context.Context, id int) (*models.Table, error) {
+	var t models.Table
+	var area sql.NullString
+	err := s.db.QueryRowContext(ctx, s.q("SELECT id, name, seats, area, sort_order FROM {schema}DINING_TABLES WHERE id = ?"), id).
+		Scan(&t.ID, &t.Name, &t.Seats, &area, &t.SortOrder)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	t.Area = area.String
+	return &t, err
+}
+
+func (s *sqlStore) CreateTable(ctx context.Context, table *models.Table) error {
+	if err := s.checkTable(ctx, s.db, table); err != nil {
+		return err
+	}
+	var err error
+	table.ID, err = s.dialect.insertReturningID(ctx, s.db,
+		s.q("INSERT INTO {schema}DINING_TABLES (name, seats, area, sort_order) VALUES (?, ?, ?, ?)"),
+		table.Name, table.Seats, nullString(table.Area), table.SortOrder)
+	table.Status, table.OpenOrders = models.TableFree, []int{}
+	return err
+}
+
+// UpdateTable mengganti data meja; order lama tetap memakai nama meja saat dipesan
+func (s *sqlStore) UpdateTable(ctx context.Context, table *models.Table) error {
+	if err := s.checkTable(ctx, s.db, table); err != nil {
+		return err
+	}
+	return expectRows(s.db.ExecContext(ctx, s.q("UPDATE {schema}DINING_TABLES SET name = ?, seats = ?, area = ?, sort_order = ? WHERE id = ?"),
+		table.Name, table.Seats, nullString(table.Area), table.SortOrder, table.ID))
+}
+
+// DeleteTable menghapus meja yang tidak punya order terbuka
+func (s *sqlStore) DeleteTable(ctx context.Context, id int) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		marks := make([]string, len(models.OpenOrderStatuses))
+		args := []interface{}{id}
+		for i, status := range models.OpenOrderStatuses {
+			marks[i] = "?"
+			args = append(args, status)
+		}
+		var open int
+		err := tx.QueryRowContext(ctx, s.q("SELECT COUNT(*) FROM {schema}ORDERS WHERE table_id = ? AND status IN ("+strings.Join(marks, ", ")+")"), args...).Scan(&open)
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			return fmt.Errorf("%w: table %d still has %d open order(s)", ErrConflict, id, open)
+		}
+
+		if _, err := tx.ExecContext(ctx, s.q("UPDATE {schema}ORDERS SET table_id = NULL WHERE table_id = ?"), id); err != nil {
+			return err
+		}
+		return expectRows(tx.ExecContext(ctx, s.q("DELETE FROM {schema}DINING_TABLES WHERE id = ?"), id))
+	})
+}
+
+// checkTable menolak nama kosong, jumlah kursi negatif atau nama yang sudah dipakai meja lain
+func (s *sqlStore) checkTable(ctx context.Context, q queryer, table *models.Table) error {
+	table.Name = strings.TrimSpace(table.Name)
+	table.Area = strings.TrimSpace(table.Area)
+	if table.Name == "" {
+		return fmt.Errorf("%w: table name is required", ErrInvalid)
+	}
+	if len(table.Name) > 50 || len(table.Area) > 50 {
+		return fmt.Errorf("%w: table name and area must be at most 50 characters", ErrInvalid)
+	}
+	if table.Seats < 0 {
+		return fmt.Errorf("%w: seats must not be negative", ErrInvalid)
+	}
+
+	var id int
+	err := q.QueryRowContext(ctx, s.q("SELECT id FROM {schema}DINING_TABLES WHERE LOWER(name) = LOWER(?)"), table.Name).Scan(&id)
+	if err == sql.ErrNoRows || (err == nil && id == table.ID) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: table %s already exists", ErrConflict, table.Name)
+}
+
+// resolveOrderType memeriksa jenis order dan melengkapi meja atau alamatnya.
+// Tanpa jenis, order dengan meja dianggap dine-in dan sisanya takeaway.
+func (s *sqlStore) resolveOrderType(ctx context.Context, q queryer, order *models.Order) error {
+	order.TableName = strings.TrimSpace(order.TableName)
+	order.DeliveryAddress = strings.TrimSpace(order.DeliveryAddress)
+	if order.Type == "" {
+		order.Type = models.OrderTypeTakeaway
+		if order.TableID != nil || order.TableName != "" {
+			order.Type = models.OrderTypeDineIn
+		}
+	}
+	if !models.IsValidOrderType(order.Type) {
+		return fmt.Errorf("%w: unknown order type %q", ErrInvalid, order.Type)
+	}
+
+	switch order.Type {
+	case models.OrderTypeDineIn:
+		order.DeliveryAddress = ""
+		var id int
+		var err error
+		if order.TableID != nil {
+			err = q.QueryRowContext(ctx, s.q("SELECT id, name FROM {schema}DINING_TABLES WHERE id = ?"), *order.TableID).Scan(&id, &order.TableName)
+		} else if order.TableName != "" {
+			err = q.QueryRowContext(ctx, s.q("SELECT id, name FROM {schema}DINING_TABLES WHERE LOWER(name) = LOWER(?)"), order.TableName).Scan(&id, &order.TableName)
+		} else {
+			return fmt.Errorf("%w: dine-in orders need a table", ErrInvalid)
+		}
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: table does not exist", ErrInvalid)
+		} else if err != nil {
+			return err
+		}
+		order.TableID = &id
+	case models.OrderTypeDelivery:
+		if order.DeliveryAddress == "" {
+			return fmt.Errorf("%w: delivery orders need an address", ErrInvalid)
+		}
+		if len(order.DeliveryAddress) > 255 {
+			return fmt.Errorf("%w: delivery address is longer than 255 characters", ErrInvalid)
+		}
+		order.TableID, order.TableName = nil, ""
+	default:
+		order.TableID, order.TableName, order.DeliveryAddress = nil, "", ""
+	}
+	return nil
+}
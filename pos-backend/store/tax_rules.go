@@ -0,0 +1,84 @@
+package store
+
+import (
+	"sort"
+
+	"pos-backend/models"
+)
+
+// appliedTax adalah total satu aturan pajak untuk semua baris order
+type appliedTax struct {
+	rule   models.TaxRule
+	base   float64
+	amount float64
+}
+
+// taxMatches mengecek apakah produk pada baris dikenai aturan pajak
+func taxMatches(r models.TaxRule, d models.OrderDetail, categories map[int]int) bool {
+	if r.ProductID != nil && *r.ProductID != d.ProductID {
+		return false
+	}
+	if r.CategoryID != nil && *r.CategoryID != categories[d.ProductID] {
+		return false
+	}
+	return true
+}
+
+// applyTaxes mengisi ServiceCharge, Tax dan TaxIncluded setiap baris.
+// Service charge dihitung dari nilai baris setelah diskon, lalu pajak dari
+// nilai setelah diskon ditambah service charge. Pajak inclusive diambil dari
+// dalam nilai tersebut dan tidak menambah total.
+func applyTaxes(rules []models.TaxRule, details []models.OrderDetail, categories map[int]int) []appliedTax {
+	// Service charge harus dihitung sebelum pajak
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Type == models.TaxService && rules[j].Type != models.TaxService
+	})
+
+	totals := make([]appliedTax, len(rules))
+	for i, r := range rules {
+		totals[i].rule = r
+	}
+
+	for i := range details {
+		d := &details[i]
+		d.ServiceCharge, d.Tax, d.TaxIncluded = 0, 0, 0
+		net := d.NetTotal()
+		for j, r := range rules {
+			if !taxMatches(r, *d, categories) {
+				continue
+			}
+			if r.Type == models.TaxService {
+				amount := roundMoney(net * r.Rate / 100)
+				d.ServiceCharge += amount
+				totals[j].base += net
+				totals[j].amount += amount
+				continue
+			}
+
+			taxable := net + d.ServiceCharge
+			if r.Inclusive {
+				amount := roundMoney(taxable * r.Rate / (100 + r.Rate))
+				d.TaxIncluded += amount
+				totals[j].base += taxable - amount
+				totals[j].amount += amount
+			} else {
+				amount := roundMoney(taxable * r.Rate / 100)
+				d.Tax += amount
+				totals[j].base += taxable
+				totals[j].amount += amount
+			}
+		}
+		d.ServiceCharge = roundMoney(d.ServiceCharge)
+		d.Tax = roundMoney(d.Tax)
+		d.TaxIncluded = roundMoney(d.TaxIncluded)
+	}
+
+	var applied []appliedTax
+	for _, t := range totals {
+		if t.amount > 0 {
+			t.base, t.amount = roundMoney(t.base), roundMoney(t.amount)
+			applied = append(applied, t)
+		}
+	}
+	return applied
+}
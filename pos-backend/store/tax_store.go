@@ -0,0 +1,254 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"pos-backend/models"
+)
+
+const taxRuleColumns = "id, name, type, rate, inclusive, product_id, category_id, active, created_at"
+
+func scanTaxRule(row rowScanner) (*models.TaxRule, error) {
+	var r models.TaxRule
+	var productID, categoryID sql.NullInt64
+	var inclusive, active int
+	if err := row.Scan(&r.ID, &r.Name, &r.Type, &r.Rate, &inclusive, &productID, &categoryID, &active, &r.CreatedAt); err != nil {
+		return nil, err
+	}
+	if productID.Valid {
+		id := int(productID.Int64)
+		r.ProductID = &id
+	}
+	if categoryID.Valid {
+		id := int(categoryID.Int64)
+		r.CategoryID = &id
+	}
+	r.Inclusive = inclusive != 0
+	r.Active = active != 0
+	return &r, nil
+}
+
+func (s *sqlStore) queryTaxRules(ctx context.Context, q queryer, query string, args ...interface{}) ([]models.TaxRule, error) {
+	rows, err := q.QueryContext(ctx, s.q(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.TaxRule{}
+	for rows.Next() {
+		r, err := scanTaxRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *r)
+	}
+	return rules, rows.Err()
+}
+
+func (s *sqlStore) ListTaxRules(ctx context.Context) ([]models.TaxRule, error) {
+	return s.queryTaxRules(ctx, s.db, "SELECT "+taxRuleColumns+" FROM {schema}TAX_RULES ORDER BY id ASC")
+}
+
+func (s *sqlStore) activeTaxRules(ctx context.Context, q queryer) ([]models.TaxRule, error) {
+	return s.queryTaxRules(ctx, q, "SELECT "+taxRuleColumns+" FROM {schema}TAX_RULES WHERE active = 1 ORDER BY id ASC")
+}
+
+// SaveTaxRule membuat aturan baru (ID 0) atau memperbarui aturan yang ada.
+// Order lama tidak ikut berubah karena tarifnya disalin ke ORDER_TAXES.
+func (s *sqlStore) SaveTaxRule(ctx context.Context, rule *models.TaxRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	switch {
+	case rule.Name == "":
+		return fmt.Errorf("%w: tax rule name is required", ErrInvalid)
+	case rule.Type != models.TaxService && rule.Type != models.TaxTax:
+		return fmt.Errorf("%w: unknown tax rule type %q", ErrInvalid, rule.Type)
+	case rule.Rate <= 0 || rule.Rate > 100:
+		return fmt.Errorf("%w: rate must be between 0 and 100", ErrInvalid)
+	case rule.Type == models.TaxService && rule.Inclusive:
+		return fmt.Errorf("%w: service charge cannot be included in the price", ErrInvalid)
+	}
+
+	inclusive, active := 0, 0
+	if rule.Inclusive {
+		inclusive = 1
+	}
+	if rule.Active {
+		active = 1
+	}
+	args := []interface{}{rule.Name, rule.Type, rule.Rate, inclusive, nullInt(rule.ProductID), nullInt(rule.CategoryID), active}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if err := s.checkRuleTarget(ctx, tx, rule.ProductID, rule.CategoryID); err != nil {
+			return err
+		}
+
+		var err error
+		if rule.ID == 0 {
+			rule.ID, err = s.dialect.insertReturningID(ctx, tx, s.q(`
+				INSERT INTO {schema}TAX_RULES (name, type, rate, inclusive, product_id, category_id, active)
+				VALUES (?, ?, ?, ?, ?, ?, ?)`), args...)
+		} else {
+			err = expectRows(tx.ExecContext(ctx, s.q(`
+				UPDATE {schema}TAX_RULES
+				SET name = ?, type = ?, rate = ?, inclusive = ?, product_id = ?, category_id = ?, active = ?
+				WHERE id = ?`), append(args, rule.ID)...))
+		}
+		if err != nil {
+			return err
+		}
+
+		saved, err := scanTaxRule(tx.QueryRowContext(ctx, s.q("SELECT "+taxRuleColumns+" FROM {schema}TAX_RULES WHERE id = ?"), rule.ID))
+		if err != nil {
+			return err
+		}
+		*rule = *saved
+		return nil
+	})
+}
+
+// checkRuleTarget memastikan produk dan kategori target promo atau pajak ada
+func (s *sqlStore) checkRuleTarget(ctx context.Context, tx *sql.Tx, productID, categoryID *int) error {
+	var id int
+	if productID != nil {
+		err := tx.QueryRowContext(ctx, s.q("SELECT id FROM {schema}PRODUCTS WHERE id = ?"), *productID).Scan(&id)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: product %d not found", ErrInvalid, *productID)
+		} else if err != nil {
+			return err
+		}
+	}
+	if categoryID != nil {
+		err := tx.QueryRowContext(ctx, s.q("SELECT id FROM {schema}CATEGORIES WHERE id = ?"), *categoryID).Scan(&id)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: category %d not found", ErrInvalid, *categoryID)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sqlStore) GetTaxRule(ctx context.Context, id int) (*models.TaxRule, error) {
+	r, err := scanTaxRule(s.db.QueryRowContext(ctx, s.q("SELECT "+taxRuleColumns+" FROM {schema}TAX_RULES WHERE id = ?"), id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return r, err
+}
+
+func (s *sqlStore) DeleteTaxRule(ctx context.Context, id int) error {
+	return expectRows(s.db.ExecContext(ctx, s.q("DELETE FROM {schema}TAX_RULES WHERE id = ?"), id))
+}
+
+// saveAppliedTaxes menyimpan rincian pajak dan service charge order
+func (s *sqlStore) saveAppliedTaxes(ctx context.Context, tx *sql.Tx, order *models.Order) error {
+	for i := range order.Taxes {
+		t := &order.Taxes[i]
+		t.OrderID = order.ID
+		inclusive := 0
+		if t.Inclusive {
+			inclusive = 1
+		}
+		var err error
+		t.ID, err = s.dialect.insertReturningID(ctx, tx, s.q(`
+			INSERT INTO {schema}ORDER_TAXES (order_id, tax_rule_id, name, type, rate, inclusive, base, amount)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+			t.OrderID, t.TaxRuleID, t.Name, t.Type, t.Rate, inclusive, t.Base, t.Amount)
+		if err != nil {
+			return fmt.Errorf("failed to record taxes: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *sqlStore) taxesByOrder(ctx context.Context, q queryer, orderIDs []int) (map[int][]models.AppliedTax, error) {
+	taxes := map[int][]models.AppliedTax{}
+	err := forEachChunk(orderIDs, func(marks string, args []interface{}) error {
+		rows, err := q.QueryContext(ctx, s.q(`
+			SELECT id, order_id, tax_rule_id, name, type, rate, inclusive, base, amount
+			FROM {schema}ORDER_TAXES
+			WHERE order_id IN (`+marks+`)
+			ORDER BY id ASC`), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var t models.AppliedTax
+			var ruleID sql.NullInt64
+			var inclusive int
+			if err := rows.Scan(&t.ID, &t.OrderID, &ruleID, &t.Name, &t.Type, &t.Rate, &inclusive, &t.Base, &t.Amount); err != nil {
+				return err
+			}
+			t.TaxRuleID = int(ruleID.Int64)
+			t.Inclusive = inclusive != 0
+			taxes[t.OrderID] = append(taxes[t.OrderID], t)
+		}
+		return rows.Err()
+	})
+	return taxes, err
+}
+
+// TaxReport menjumlahkan pajak dan service charge order selesai antara from
+// dan to per aturan. Bagian pajak dari refund dihitung dari baris yang
+// direfund, pada tanggal refund dibuat.
+func (s *sqlStore) TaxReport(ctx context.Context, from, to time.Time) (*models.TaxReport, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: to must be after from", ErrInvalid)
+	}
+	report := &models.TaxReport{From: from, To: to, Rules: []models.TaxSummary{}}
+	args := []interface{}{models.OrderStatusCompleted, models.OrderStatusRefunded, from, to}
+	const orderFilter = "o.status IN (?, ?) AND o.created_at >= ? AND o.created_at < ?"
+
+	err := s.db.QueryRowContext(ctx, s.q(`
+		SELECT COALESCE(SUM(o.service_charge), 0), COALESCE(SUM(o.tax), 0), COALESCE(SUM(o.tax_included), 0)
+		FROM {schema}ORDERS o
+		WHERE `+orderFilter), args...).Scan(&report.ServiceCharge, &report.Tax, &report.TaxIncluded)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.QueryRowContext(ctx, s.q(`
+		SELECT COALESCE(SUM(d.service_charge * ri.quantity / d.quantity), 0),
+			COALESCE(SUM((d.tax + d.tax_included) * ri.quantity / d.quantity), 0)
+		FROM {schema}REFUND_ITEMS ri
+		JOIN {schema}REFUNDS r ON r.id = ri.refund_id
+		JOIN {schema}ORDER_DETAILS d ON d.id = ri.order_detail_id
+		WHERE d.quantity > 0 AND r.created_at >= ? AND r.created_at < ?`), from, to).Scan(&report.RefundedServiceCharge, &report.RefundedTax)
+	if err != nil {
+		return nil, err
+	}
+	report.RefundedServiceCharge = roundMoney(report.RefundedServiceCharge)
+	report.RefundedTax = roundMoney(report.RefundedTax)
+
+	rows, err := s.db.QueryContext(ctx, s.q(`
+		SELECT t.tax_rule_id, t.name, t.type, t.rate, t.inclusive, COUNT(DISTINCT t.order_id), COALESCE(SUM(t.base), 0), COALESCE(SUM(t.amount), 0)
+		FROM {schema}ORDER_TAXES t
+		JOIN {schema}ORDERS o ON o.id = t.order_id
+		WHERE `+orderFilter+`
+		GROUP BY t.tax_rule_id, t.name, t.type, t.rate, t.inclusive
+		ORDER BY t.type, t.name, t.rate`), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t models.TaxSummary
+		var ruleID sql.NullInt64
+		var inclusive int
+		if err := rows.Scan(&ruleID, &t.Name, &t.Type, &t.Rate, &inclusive, &t.OrderCount, &t.Base, &t.Amount); err != nil {
+			return nil, err
+		}
+		t.TaxRuleID = int(ruleID.Int64)
+		t.Inclusive = inclusive != 0
+		report.Rules = append(report.Rules, t)
+	}
+	return report, rows.Err()
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"testing"
+
+	"pos-backend/models"
+)
+
+func TestTaxRules(t *testing.T) {
+	// nasi 20000 tanpa kategori dan air 11100 di kategori Retail
+	tests := []struct {
+		name        string
+		rules       func(nasi, retail int) []models.TaxRule
+		items       func(nasi, air int) []models.OrderItem
+		service     float64
+		tax         float64
+		taxIncluded float64
+		total       float64
+	}{
+		{
+			name: "exclusive tax",
+			rules: func(nasi, retail int) []models.TaxRule {
+				return []models.TaxRule{{Name: "PB1", Type: models.TaxTax, Rate: 10, Active: true}}
+			},
+			items: func(nasi, air int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: nasi, Quantity: 1}}
+			},
+			tax:   2000,
+			total: 22000,
+		},
+		{
+			name: "inclusive tax",
+			rules: func(nasi, retail int) []models.TaxRule {
+				return []models.TaxRule{{Name: "PPN", Type: models.TaxTax, Rate: 11, Inclusive: true, Active: true}}
+			},
+			items: func(nasi, air int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: air, Quantity: 1}}
+			},
+			taxIncluded: 1100,
+			total:       11100,
+		},
+		{
+			name: "tax on top of service charge",
+			rules: func(nasi, retail int) []models.TaxRule {
+				return []models.TaxRule{
+					{Name: "Service", Type: models.TaxService, Rate: 5, Active: true},
+					{Name: "PB1", Type: models.TaxTax, Rate: 10, Active: true},
+				}
+			},
+			items: func(nasi, air int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: nasi, Quantity: 1}}
+			},
+			service: 1000,
+			tax:     2100,
+			total:   23100,
+		},
+		{
+			name: "inclusive and exclusive by target",
+			rules: func(nasi, retail int) []models.TaxRule {
+				return []models.TaxRule{
+					{Name: "Service", Type: models.TaxService, Rate: 5, Active: true},
+					{Name: "PB1", Type: models.TaxTax, Rate: 10, ProductID: &nasi, Active: true},
+					{Name: "PPN", Type: models.TaxTax, Rate: 11, Inclusive: true, CategoryID: &retail, Active: true},
+				}
+			},
+			items: func(nasi, air int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: nasi, Quantity: 1}, {ProductID: air, Quantity: 1}}
+			},
+			// nasi 20000 + 1000 + 2100, air 11100 + 555 dengan PPN 11655 * 11/111
+			service:     1555,
+			tax:         2100,
+			taxIncluded: 1155,
+			total:       34755,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ctx := openTestStore(t)
+			retail := &models.Category{Name: "Retail"}
+			if err := st.Categories.CreateCategory(ctx, retail); err != nil {
+				t.Fatal(err)
+			}
+			nasi := createTestProduct(t, ctx, st, models.Product{Name: "Nasi", Price: 20000})
+			air := createTestProduct(t, ctx, st, models.Product{Name: "Air", Price: 11100, CategoryID: &retail.ID})
+			for _, r := range tt.rules(nasi, retail.ID) {
+				if err := st.Taxes.SaveTaxRule(ctx, &r); err != nil {
+					t.Fatalf("save tax rule %s: %v", r.Name, err)
+				}
+			}
+
+			order := &models.Order{Items: tt.items(nasi, air)}
+			if err := st.Orders.PriceOrder(ctx, order); err != nil {
+				t.Fatal(err)
+			}
+			if !moneyEqual(order.ServiceCharge, tt.service) || !moneyEqual(order.Tax, tt.tax) ||
+				!moneyEqual(order.TaxIncluded, tt.taxIncluded) || !moneyEqual(*order.TotalPrice, tt.total) {
+				t.Errorf("service %.2f tax %.2f included %.2f total %.2f, want %.2f %.2f %.2f %.2f",
+					order.ServiceCharge, order.Tax, order.TaxIncluded, *order.TotalPrice,
+					tt.service, tt.tax, tt.taxIncluded, tt.total)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"pos-backend/models"
+)
+
+func (s *sqlStore) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	var user models.User
+	err := s.db.QueryRowContext(ctx, s.q("SELECT username, password, role FROM {schema}USERS WHERE username = ?"), username).
+		Scan(&user.Username, &user.Password, &user.Role)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *sqlStore) CreateUser(ctx context.Context, user *models.User) error {
+	_, err := s.db.ExecContext(ctx, s.q("INSERT INTO {schema}USERS (username, password, role) VALUES (?, ?, ?)"),
+		user.Username, user.Password, user.Role)
+	return err
+}
+
+func (s *sqlStore) CountUsers(ctx context.Context, role string) (int, error) {
+	return s.count(ctx, "SELECT COUNT(*) FROM {schema}USERS WHERE role = ?", role)
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// AuditRecord diisi handler dengan entitas yang diubah beserta nilai
+// sebelum dan sesudahnya. Field yang kosong tetap dicatat dari path.
+type AuditRecord struct {
+	Entity   string
+	EntityID string
+	Before   interface{}
+	After    interface{}
+}
+
+type auditContextKey struct{}
+
+// AuditFromContext mengambil record audit yang dipasang oleh Audit.
+// Hasilnya nil untuk request yang tidak diaudit.
+func AuditFromContext(ctx context.Context) *AuditRecord {
+	rec, _ := ctx.Value(auditContextKey{}).(*AuditRecord)
+	return rec
+}
+
+// Audit memanggil record setelah setiap request yang bisa mengubah data
+// (selain GET, HEAD dan OPTIONS) selesai, termasuk yang ditolak. Body
+// request tidak pernah dicatat karena bisa berisi password.
+func Audit(publicPaths []string, record func(r *http.Request, status int, rec *AuditRecord), next http.Handler) http.Handler {
+	public := make(map[string]bool, len(publicPaths))
+	for _, path := range publicPaths {
+		public[path] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+		if public[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &AuditRecord{}
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		r = r.WithContext(context.WithValue(r.Context(), auditContextKey{}, rec))
+		next.ServeHTTP(sw, r)
+
+		if rec.EntityID == "" {
+			rec.EntityID = pathEntityID(r)
+		}
+		record(r, sw.status, rec)
+	})
+}
+
+// AuditAction menamai aksi dari path tanpa segmen angka,
+// misalnya /update-product/12 menjadi update-product
+func AuditAction(path string) string {
+	var parts []string
+	for _, part := range strings.Split(path, "/") {
+		if part == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(part); err == nil {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// pathEntityID memakai segmen angka terakhir pada path atau parameter id
+func pathEntityID(r *http.Request) string {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if _, err := strconv.Atoi(parts[i]); err == nil {
+			return parts[i]
+		}
+	}
+	return r.URL.Query().Get("id")
+}
+
+// ClientIP mengambil IP client. X-Forwarded-For hanya dipercaya jika
+// trustProxy aktif karena header itu bisa diisi sembarang oleh client.
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// statusWriter menyimpan status code yang dikirim handler
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
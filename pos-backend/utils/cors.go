@@ -4,9 +4,21 @@ import (
 	"net/http"
 )
 
-func EnableCORS(next http.Handler) http.Handler {
+// EnableCORS mengizinkan request dari origin yang terdaftar, "*" berarti semua origin
+func EnableCORS(allowedOrigins []string, next http.Handler) http.Handler {
+    allowed := make(map[string]bool, len(allowedOrigins))
+    for _, origin := range allowedOrigins {
+        allowed[origin] = true
+    }
+
     return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-        w.Header().Set("Access-Control-Allow-Origin", "http://localhost:5173")
+        origin := r.Header.Get("Origin")
+        if allowed["*"] {
+            w.Header().Set("Access-Control-Allow-Origin", "*")
+        } else if allowed[origin] {
+            w.Header().Set("Access-Control-Allow-Origin", origin)
+        }
+        w.Header().Add("Vary", "Origin")
         w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
         w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
         
@@ -19,4 +31,4 @@ func EnableCORS(next http.Handler) http.Handler {
         // Call the next handler
         next.ServeHTTP(w, r)
     })
-}
\ No newline at end of file
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"pos-backend/models"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// OrderEventsChannel adalah channel Redis untuk event order, semua instance
+// backend berlangganan ke channel yang sama
+const OrderEventsChannel = "orders:events"
+
+// PublishOrderEvent menyebarkan event order ke semua subscriber
+func PublishOrderEvent(ctx context.Context, rdb *redis.Client, event models.OrderEvent) error {
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return rdb.Publish(ctx, OrderEventsChannel, data).Err()
+}
+
+// SubscribeOrderEvents berlangganan event order. Pemanggil wajib menutup PubSub.
+func SubscribeOrderEvents(ctx context.Context, rdb *redis.Client) *redis.PubSub {
+	return rdb.Subscribe(ctx, OrderEventsChannel)
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// AnyRole pada tabel permission mengizinkan semua user yang sudah login
+const AnyRole = "*"
+
+type sessionContextKey struct{}
+
+// SessionFromContext mengambil session yang dipasang oleh RequireAuth
+func SessionFromContext(ctx context.Context) *Session {
+	session, _ := ctx.Value(sessionContextKey{}).(*Session)
+	return session
+}
+
+// BearerToken mengambil token dari header Authorization
+func BearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// RequireAuth menolak request tanpa access token yang valid,
+// kecuali path yang terdaftar di publicPaths. Hanya GET ke streamPaths yang
+// boleh membawa token lewat ?access_token=.
+func RequireAuth(rdb *redis.Client, publicPaths, streamPaths []string, next http.Handler) http.Handler {
+	public := make(map[string]bool, len(publicPaths))
+	for _, path := range publicPaths {
+		public[path] = true
+	}
+	stream := make(map[string]bool, len(streamPaths))
+	for _, path := range streamPaths {
+		stream[path] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions || public[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := BearerToken(r)
+		// EventSource di browser tidak bisa mengirim header Authorization.
+		// Token di URL bisa tercatat di log, jadi hanya diterima di stream.
+		if token == "" && r.Method == http.MethodGet && stream[r.URL.Path] {
+			token = r.URL.Query().Get("access_token")
+		}
+		if token == "" {
+			http.Error(w, "Missing authorization token", http.StatusUnauthorized)
+			return
+		}
+
+		session, err := ValidateAccessToken(r.Context(), rdb, token)
+		if err == ErrInvalidSession {
+			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+			return
+		} else if err != nil {
+			http.Error(w, "Failed to validate session: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), sessionContextKey{}, session)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Authorize menolak request dengan 403 jika role pada session tidak
+// diizinkan oleh tabel permissions. Harus dipasang di dalam RequireAuth.
+func Authorize(publicPaths []string, permissions map[string][]string, next http.Handler) http.Handler {
+	public := make(map[string]bool, len(publicPaths))
+	for _, path := range publicPaths {
+		public[path] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions || public[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		session := SessionFromContext(r.Context())
+		if session == nil {
+			http.Error(w, "Missing authorization token", http.StatusUnauthorized)
+			return
+		}
+
+		if !roleAllowed(matchPermission(permissions, r.URL.Path), session.Role) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// matchPermission mencari pola paling spesifik untuk path, mengikuti aturan http.ServeMux
+func matchPermission(permissions map[string][]string, path string) []string {
+	if roles, ok := permissions[path]; ok {
+		return roles
+	}
+
+	var best string
+	for pattern := range permissions {
+		if strings.HasSuffix(pattern, "/") && strings.HasPrefix(path, pattern) && len(pattern) > len(best) {
+			best = pattern
+		}
+	}
+	if best == "" {
+		return nil
+	}
+	return permissions[best]
+}
+
+func roleAllowed(roles []string, role string) bool {
+	for _, allowed := range roles {
+		if allowed == AnyRole || allowed == role {
+			return true
+		}
+	}
+	return false
+}
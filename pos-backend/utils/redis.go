@@ -2,20 +2,21 @@ package utils
 
 import (
 	"context"
-	"log"
+	"pos-backend/config"
 
 	"github.com/go-redis/redis/v8"
 )
 
-var rdb *redis.Client
-var Ctx = context.Background()
-
-func InitRedis() {
-    rdb = redis.NewClient(&redis.Options{
-        Addr: "localhost:6379",
+// NewRedisClient membuat client Redis dari konfigurasi dan memastikan koneksi berhasil
+func NewRedisClient(ctx context.Context, cfg config.RedisConfig) (*redis.Client, error) {
+    rdb := redis.NewClient(&redis.Options{
+        Addr:     cfg.Addr,
+        Password: cfg.Password,
+        DB:       cfg.DB,
     })
-    _, err := rdb.Ping(Ctx).Result()
-    if err != nil {
-        log.Fatalf("Tidak dapat terhubung ke Redis: %v", err)
+    if _, err := rdb.Ping(ctx).Result(); err != nil {
+        rdb.Close()
+        return nil, err
     }
-}
\ No newline at end of file
+    return rdb, nil
+}
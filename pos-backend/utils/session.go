@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 12 * time.Hour
+
+	sessionKeyPrefix = "session:"
+	refreshKeyPrefix = "refresh:"
+)
+
+var ErrInvalidSession = errors.New("invalid or expired session")
+
+// sessionSecret dipakai untuk menandatangani access token
+var sessionSecret []byte
+
+// SetSessionSecret mengatur kunci HMAC untuk access token
+func SetSessionSecret(secret []byte) {
+	sessionSecret = secret
+}
+
+// Session disimpan di Redis selama refresh token masih berlaku
+type Session struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SessionTokens dikirim ke client setelah login atau refresh
+type SessionTokens struct {
+	AccessToken  string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type sessionClaims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// CreateSession menyimpan session baru di Redis dan mengembalikan token-nya
+func CreateSession(ctx context.Context, rdb *redis.Client, username, role string) (*Session, *SessionTokens, error) {
+	id, err := randomToken(16)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session := &Session{
+		ID:        id,
+		Username:  username,
+		Role:      role,
+		CreatedAt: time.Now(),
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := rdb.Set(ctx, sessionKeyPrefix+id, data, RefreshTokenTTL).Err(); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := issueTokens(ctx, rdb, session)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, tokens, nil
+}
+
+// ValidateAccessToken memeriksa tanda tangan, expiry dan status session di Redis
+func ValidateAccessToken(ctx context.Context, rdb *redis.Client, token string) (*Session, error) {
+	var claims sessionClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		return sessionSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, ErrInvalidSession
+	}
+	return getSession(ctx, rdb, claims.ID)
+}
+
+// RefreshSession menukar refresh token dengan pasangan token baru.
+// Refresh token lama langsung tidak berlaku lagi.
+func RefreshSession(ctx context.Context, rdb *redis.Client, refreshToken string) (*Session, *SessionTokens, error) {
+	sessionID, err := rdb.GetDel(ctx, refreshKeyPrefix+refreshToken).Result()
+	if err == redis.Nil {
+		return nil, nil, ErrInvalidSession
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	session, err := getSession(ctx, rdb, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := rdb.Expire(ctx, sessionKeyPrefix+session.ID, RefreshTokenTTL).Err(); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := issueTokens(ctx, rdb, session)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, tokens, nil
+}
+
+// RevokeSession menghapus session sehingga semua token-nya ditolak
+func RevokeSession(ctx context.Context, rdb *redis.Client, sessionID string) error {
+	return rdb.Del(ctx, sessionKeyPrefix+sessionID).Err()
+}
+
+func getSession(ctx context.Context, rdb *redis.Client, sessionID string) (*Session, error) {
+	val, err := rdb.Get(ctx, sessionKeyPrefix+sessionID).Result()
+	if err == redis.Nil {
+		return nil, ErrInvalidSession
+	} else if err != nil {
+		return nil, err
+	}
+
+	var session Session
+	if err := json.Unmarshal([]byte(val), &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func issueTokens(ctx context.Context, rdb *redis.Client, session *Session) (*SessionTokens, error) {
+	now := time.Now()
+	expiresAt := now.Add(AccessTokenTTL)
+	claims := sessionClaims{
+		Role: session.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        session.ID,
+			Subject:   session.Username,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(sessionSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	// Refresh token tetap mengarah ke session, jadi logout juga mematikannya
+	if err := rdb.Set(ctx, refreshKeyPrefix+refreshToken, session.ID, RefreshTokenTTL).Err(); err != nil {
+		return nil, err
+	}
+
+	return &SessionTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -46,13 +46,22 @@ const ActiveOrders = () => {
     }
   };
 
-  const completeOrder = async (orderId) => {
+  const completeOrder = async (order) => {
+    // Pembayaran tunai, kembalian dihitung oleh server
+    const input = window.prompt(
+      `Total ${formatPrice(order.total_price)}. Uang diterima:`,
+      order.total_price
+    );
+    if (input === null) return;
     try {
       const response = await axios.post(
-        `http://localhost:8080/complete-order?id=${orderId}`
+        `http://localhost:8080/complete-order?id=${order.id}`,
+        { payments: [{ method: "cash", amount: Number(input) }] }
       );
       if (response.status === 200) {
-        alert("Order Completed!");
+        alert(
+          "Order Completed! Kembalian: " + formatPrice(response.data.change_due)
+        );
         await fetchOrders();
       }
     } catch (error) {
@@ -138,7 +147,7 @@ const ActiveOrders = () => {
                 </div>
                 <div className="buttonorder">
                   <button
-                    onClick={() => completeOrder(order.id)}
+                    onClick={() => completeOrder(order)}
                     className="button-complete-order"
                   >
                     Complete Order
@@ -73,6 +73,7 @@ const Cashier = ({ onCheckout }) => {
         total_price: total, // Pastikan total dikirim
         status: "On Progress",
         items: cart.map((item) => ({
+          product_id: item.product.id,
           product_name: item.product.name,
           quantity: item.quantity,
           total_price: item.product.price * item.quantity,
@@ -24,7 +24,12 @@ const LoginPage = () => {
         password,
       });
 
-      const user = { username, role: response.data.role };
+      const user = {
+        username,
+        role: response.data.role,
+        token: response.data.token,
+        refresh_token: response.data.refresh_token,
+      };
       console.log("Login successful, user:", user);
       login(user);
       localStorage.setItem("role", response.data.role);
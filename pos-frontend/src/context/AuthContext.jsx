@@ -1,24 +1,82 @@
 import React, { createContext, useState, useEffect } from "react";
 import PropTypes from "prop-types";
+import axios from "axios";
 
 export const AuthContext = createContext();
 
+const setAuthHeader = (token) => {
+  if (token) {
+    axios.defaults.headers.common["Authorization"] = `Bearer ${token}`;
+  } else {
+    delete axios.defaults.headers.common["Authorization"];
+  }
+};
+
 export const AuthProvider = ({ children }) => {
   const [user, setUser] = useState(null);
   const [loading, setLoading] = useState(true); // Tambahkan state loading
 
   const login = async (user) => {
     console.log("Login function called with user:", user);
+    setAuthHeader(user.token);
     setUser(user);
     localStorage.setItem("user", JSON.stringify(user));
   };
 
-  const logout = () => {
-    console.log("Logout function called");
+  const clearSession = () => {
+    setAuthHeader(null);
     setUser(null);
     localStorage.removeItem("user");
   };
 
+  const logout = async () => {
+    console.log("Logout function called");
+    try {
+      await axios.post("http://localhost:8080/logout");
+    } catch (error) {
+      console.error("Failed to revoke session", error);
+    }
+    clearSession();
+  };
+
+  // Coba perbarui token sekali saat server membalas 401
+  useEffect(() => {
+    const interceptor = axios.interceptors.response.use(
+      (response) => response,
+      async (error) => {
+        const original = error.config;
+        const storedUser = JSON.parse(localStorage.getItem("user") || "null");
+        if (
+          error.response?.status === 401 &&
+          storedUser?.refresh_token &&
+          !original._retry &&
+          !original.url.endsWith("/refresh-token")
+        ) {
+          original._retry = true;
+          try {
+            const response = await axios.post(
+              "http://localhost:8080/refresh-token",
+              { refresh_token: storedUser.refresh_token }
+            );
+            const refreshed = {
+              ...storedUser,
+              token: response.data.token,
+              refresh_token: response.data.refresh_token,
+            };
+            login(refreshed);
+            original.headers["Authorization"] = `Bearer ${refreshed.token}`;
+            return axios(original);
+          } catch (refreshError) {
+            clearSession();
+            return Promise.reject(refreshError);
+          }
+        }
+        return Promise.reject(error);
+      }
+    );
+    return () => axios.interceptors.response.eject(interceptor);
+  }, []);
+
   useEffect(() => {
     console.log("useEffect called to check localStorage for user");
     const storedUser = localStorage.getItem("user");
@@ -26,6 +84,7 @@ export const AuthProvider = ({ children }) => {
       try {
         const parsedUser = JSON.parse(storedUser);
         if (parsedUser) {
+          setAuthHeader(parsedUser.token);
           setUser(parsedUser);
           console.log("User state set with parsed user");
         }